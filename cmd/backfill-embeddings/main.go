@@ -0,0 +1,33 @@
+// Command backfill-embeddings membuat embedding untuk blog, project, dan bookmark yang sudah ada.
+//
+//	go run ./cmd/backfill-embeddings          # hanya konten baru / berubah / beda model
+//	go run ./cmd/backfill-embeddings -force   # embed ulang semuanya
+//
+// Server yang sedang jalan memuat hasilnya saat reload index berikutnya.
+package main
+
+import (
+	"arlchoose/backend-api/config"
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"flag"
+	"log"
+	"os"
+)
+
+func main() {
+
+	force := flag.Bool("force", false, "re-embed all content, even if unchanged")
+	flag.Parse()
+
+	config.LoadEnv()
+	database.InitDB()
+
+	log.Printf("[BACKFILL] embedding model: %s", helpers.EmbeddingModel())
+	indexed, skipped, failed := helpers.BackfillEmbeddings(*force)
+	log.Printf("[BACKFILL] done: %d embedded, %d unchanged, %d failed", indexed, skipped, failed)
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,419 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apOutboxPageSize = 20
+
+// Jeda sebelum retry pengiriman ke inbox yang gagal
+var apRetryDelays = []time.Duration{10 * time.Second, time.Minute}
+
+// apJSON kirim response dengan content type ActivityPub
+func apJSON(c *gin.Context, status int, data any) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, helpers.ActivityContentType+"; charset=utf-8", body)
+}
+
+func apUrl(path string) string {
+	return strings.TrimSuffix(helpers.GetBaseUrl(), "/") + "/api/ap/" + path
+}
+
+// GET /.well-known/webfinger?resource=acct:user@domain — discovery dari server fediverse (publik)
+func WebFinger(c *gin.Context) {
+
+	resource := c.Query("resource")
+	acct := "acct:" + helpers.ApUsername() + "@" + helpers.ApDomain()
+
+	if !strings.EqualFold(resource, acct) && resource != helpers.ApActorUrl() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+
+	c.Header("Content-Type", "application/jrd+json; charset=utf-8")
+	c.JSON(http.StatusOK, gin.H{
+		"subject": acct,
+		"aliases": []string{helpers.ApActorUrl()},
+		"links": []gin.H{
+			{"rel": "self", "type": helpers.ActivityContentType, "href": helpers.ApActorUrl()},
+			{"rel": "http://webfinger.net/rel/profile-page", "type": "text/html", "href": frontendUrl("")},
+		},
+	})
+}
+
+// GET /api/ap/actor — dokumen actor blog (publik)
+func ApActor(c *gin.Context) {
+
+	publicKey, err := helpers.ApPublicKeyPem()
+	if err != nil {
+		log.Printf("[AP ERROR] key: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Actor key is not available"})
+		return
+	}
+
+	var profile models.Profile
+	database.DB.First(&profile)
+
+	actor := map[string]any{
+		"@context":          []string{helpers.ActivityStreamsContext, "https://w3id.org/security/v1"},
+		"id":                helpers.ApActorUrl(),
+		"type":              "Person",
+		"preferredUsername": helpers.ApUsername(),
+		"name":              ogBranding().SiteName,
+		"summary":           profile.Tagline,
+		"url":               frontendUrl(""),
+		"inbox":             apUrl("inbox"),
+		"outbox":            apUrl("outbox"),
+		"followers":         apUrl("followers"),
+		"discoverable":      true,
+		"publicKey": map[string]string{
+			"id":           helpers.ApKeyId(),
+			"owner":        helpers.ApActorUrl(),
+			"publicKeyPem": publicKey,
+		},
+	}
+	if profile.Avatar != "" {
+		actor["icon"] = map[string]string{"type": "Image", "url": absoluteUrl(profile.Avatar)}
+	}
+
+	apJSON(c, http.StatusOK, actor)
+}
+
+// GET /api/ap/outbox?page= — Create(Article) dari blog yang sudah publish (publik)
+func ApOutbox(c *gin.Context) {
+
+	var total int64
+	published := database.DB.Model(&models.Blog{}).Where("status = ?", "published")
+	published.Count(&total)
+
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page < 1 {
+		apJSON(c, http.StatusOK, map[string]any{
+			"@context":   helpers.ActivityStreamsContext,
+			"id":         apUrl("outbox"),
+			"type":       "OrderedCollection",
+			"totalItems": total,
+			"first":      apUrl("outbox?page=1"),
+		})
+		return
+	}
+
+	var blogs []models.Blog
+	database.DB.Preload("Tags").
+		Where("status = ?", "published").
+		Order("published_at desc, id desc").
+		Limit(apOutboxPageSize).Offset((page - 1) * apOutboxPageSize).
+		Find(&blogs)
+
+	items := make([]map[string]any, 0, len(blogs))
+	for _, blog := range blogs {
+		items = append(items, apBlogActivity("Create", blog))
+	}
+
+	collection := map[string]any{
+		"@context":     helpers.ActivityStreamsContext,
+		"id":           apUrl(fmt.Sprintf("outbox?page=%d", page)),
+		"type":         "OrderedCollectionPage",
+		"partOf":       apUrl("outbox"),
+		"totalItems":   total,
+		"orderedItems": items,
+	}
+	if int64(page*apOutboxPageSize) < total {
+		collection["next"] = apUrl(fmt.Sprintf("outbox?page=%d", page+1))
+	}
+	if page > 1 {
+		collection["prev"] = apUrl(fmt.Sprintf("outbox?page=%d", page-1))
+	}
+
+	apJSON(c, http.StatusOK, collection)
+}
+
+// GET /api/ap/followers — jumlah follower, daftar tidak dibuka (publik)
+func ApFollowers(c *gin.Context) {
+
+	var total int64
+	database.DB.Model(&models.ApFollower{}).Count(&total)
+
+	apJSON(c, http.StatusOK, map[string]any{
+		"@context":   helpers.ActivityStreamsContext,
+		"id":         apUrl("followers"),
+		"type":       "OrderedCollection",
+		"totalItems": total,
+	})
+}
+
+// GET /api/ap/articles/:id — object Article untuk satu blog (publik)
+func ApArticle(c *gin.Context) {
+
+	var blog models.Blog
+	if err := database.DB.Preload("Tags").Where("status = ?", "published").First(&blog, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Article not found"})
+		return
+	}
+
+	article := apArticle(blog)
+	article["@context"] = helpers.ActivityStreamsContext
+	apJSON(c, http.StatusOK, article)
+}
+
+// POST /api/ap/inbox — terima Follow/Undo dari server lain, wajib HTTP Signature (publik)
+func ApInbox(c *gin.Context) {
+
+	body, err := helpers.ReadActivityBody(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+
+	signer, err := helpers.VerifyRequest(c.Request, body)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var activity struct {
+		Id     string          `json:"id"`
+		Type   string          `json:"type"`
+		Actor  string          `json:"actor"`
+		Object json.RawMessage `json:"object"`
+	}
+	if err := json.Unmarshal(body, &activity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activity"})
+		return
+	}
+
+	// Activity harus ditandatangani oleh actor-nya sendiri
+	if activity.Actor != signer.Id {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Actor does not match signature"})
+		return
+	}
+
+	switch activity.Type {
+	case "Follow":
+		var target string
+		if json.Unmarshal(activity.Object, &target) != nil || target != helpers.ApActorUrl() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Can only follow " + helpers.ApActorUrl()})
+			return
+		}
+		follower := saveApFollower(signer, activity.Id)
+		go sendApAccept(follower, json.RawMessage(body))
+
+	case "Undo":
+		var inner struct {
+			Type   string `json:"type"`
+			Object string `json:"object"`
+		}
+		if json.Unmarshal(activity.Object, &inner) == nil && inner.Type == "Follow" {
+			database.DB.Where("actor_url = ?", signer.Id).Delete(&models.ApFollower{})
+		}
+
+	case "Delete":
+		// Akun remote dihapus
+		var object string
+		if json.Unmarshal(activity.Object, &object) == nil && object == signer.Id {
+			database.DB.Where("actor_url = ?", signer.Id).Delete(&models.ApFollower{})
+		}
+	}
+
+	// Activity lain (Like, Announce, Create reply, ...) diterima tapi diabaikan
+	c.Status(http.StatusAccepted)
+}
+
+// saveApFollower simpan atau perbarui follower dari dokumen actor
+func saveApFollower(actor *helpers.ApActor, followId string) models.ApFollower {
+
+	var follower models.ApFollower
+	database.DB.Where("actor_url = ?", actor.Id).First(&follower)
+
+	follower.ActorUrl = actor.Id
+	follower.Username = actor.PreferredUsername
+	follower.Inbox = actor.Inbox
+	follower.SharedInbox = actor.Endpoints.SharedInbox
+	follower.FollowId = followId
+	if u, err := url.Parse(actor.Id); err == nil {
+		follower.Domain = u.Host
+	}
+
+	database.DB.Save(&follower)
+	return follower
+}
+
+// sendApAccept balas Follow dengan Accept supaya follow tidak menggantung di sisi remote
+func sendApAccept(follower models.ApFollower, follow json.RawMessage) {
+	accept := map[string]any{
+		"@context": helpers.ActivityStreamsContext,
+		"id":       fmt.Sprintf("%s#accepts/%d", helpers.ApActorUrl(), time.Now().UnixNano()),
+		"type":     "Accept",
+		"actor":    helpers.ApActorUrl(),
+		"object":   follow,
+	}
+	deliverApActivity(nil, "Accept", follower.Inbox, accept)
+}
+
+// FederateBlog kirim blog yang baru dipublish ke semua follower.
+// Create untuk pertama kali, Update kalau blog ini sudah pernah dikirim sebelumnya.
+func FederateBlog(blog models.Blog) {
+
+	if os.Getenv("AP_DISABLED") == "true" {
+		return
+	}
+
+	var followers []models.ApFollower
+	database.DB.Find(&followers)
+	if len(followers) == 0 {
+		return
+	}
+
+	activityType := "Create"
+	var sent int64
+	database.DB.Model(&models.ApDelivery{}).
+		Where("blog_id = ? AND activity_type = ? AND status = ?", blog.Id, "Create", "sent").
+		Count(&sent)
+	if sent > 0 {
+		activityType = "Update"
+	}
+
+	activity := apBlogActivity(activityType, blog)
+	activity["@context"] = helpers.ActivityStreamsContext
+	if activityType == "Update" {
+		activity["id"] = fmt.Sprintf("%s#updates/%d", apArticleUrl(blog.Id), time.Now().Unix())
+	}
+
+	// Satu server cukup dikirimi sekali lewat shared inbox
+	inboxes := map[string]bool{}
+	for _, f := range followers {
+		inbox := f.SharedInbox
+		if inbox == "" {
+			inbox = f.Inbox
+		}
+		inboxes[inbox] = true
+	}
+
+	log.Printf("[AP] %s blog %d → %d inboxes", activityType, blog.Id, len(inboxes))
+	for inbox := range inboxes {
+		go deliverApActivity(&blog.Id, activityType, inbox, activity)
+	}
+}
+
+// deliverApActivity kirim activity dengan retry, hasil akhir dicatat di ap_deliveries
+func deliverApActivity(blogId *uint, activityType string, inbox string, activity map[string]any) {
+
+	delivery := models.ApDelivery{
+		BlogId:       blogId,
+		ActivityType: activityType,
+		Inbox:        inbox,
+	}
+	if id, ok := activity["id"].(string); ok {
+		delivery.ActivityId = id
+	}
+
+	for attempt := 0; ; attempt++ {
+		delivery.Attempts = attempt + 1
+		code, err := helpers.DeliverActivity(inbox, activity)
+		delivery.StatusCode = code
+
+		if err == nil {
+			delivery.Status = "sent"
+			delivery.Error = ""
+			break
+		}
+
+		delivery.Status = "failed"
+		delivery.Error = err.Error()
+
+		// 4xx selain 429 tidak akan berhasil kalau diulang
+		if code >= 400 && code < 500 && code != http.StatusTooManyRequests {
+			break
+		}
+		if attempt >= len(apRetryDelays) {
+			break
+		}
+		time.Sleep(apRetryDelays[attempt])
+	}
+
+	if delivery.Status == "failed" {
+		log.Printf("[AP ERROR] %s → %s: %s", activityType, inbox, delivery.Error)
+	}
+	database.DB.Create(&delivery)
+}
+
+func apArticleUrl(id uint) string {
+	return apUrl(fmt.Sprintf("articles/%d", id))
+}
+
+// apArticle object Article dari blog
+func apArticle(blog models.Blog) map[string]any {
+
+	published := blog.CreatedAt
+	if blog.PublishedAt != nil {
+		published = *blog.PublishedAt
+	}
+
+	tags := make([]map[string]string, 0, len(blog.Tags))
+	for _, tag := range blog.Tags {
+		tags = append(tags, map[string]string{
+			"type": "Hashtag",
+			"name": "#" + strings.ReplaceAll(tag.Name, " ", ""),
+			"href": frontendUrl("/blog?tag=" + url.QueryEscape(tag.Slug)),
+		})
+	}
+
+	article := map[string]any{
+		"id":           apArticleUrl(blog.Id),
+		"type":         "Article",
+		"attributedTo": helpers.ApActorUrl(),
+		"name":         blog.Title,
+		"summary":      blog.Description,
+		"content":      blog.Content,
+		"mediaType":    "text/html",
+		"url":          frontendBlogUrl(blog.Slug),
+		"published":    published.UTC().Format(time.RFC3339),
+		"updated":      blog.UpdatedAt.UTC().Format(time.RFC3339),
+		"to":           []string{helpers.ActivityStreamsPublic},
+		"cc":           []string{apUrl("followers")},
+		"tag":          tags,
+	}
+	if blog.CoverImage != "" {
+		article["image"] = map[string]string{"type": "Image", "url": absoluteUrl(blog.CoverImage)}
+	}
+	return article
+}
+
+// apBlogActivity bungkus Article dalam activity Create/Update
+func apBlogActivity(activityType string, blog models.Blog) map[string]any {
+	article := apArticle(blog)
+	return map[string]any{
+		"id":        apArticleUrl(blog.Id) + "#" + strings.ToLower(activityType),
+		"type":      activityType,
+		"actor":     helpers.ApActorUrl(),
+		"published": article["published"],
+		"to":        article["to"],
+		"cc":        article["cc"],
+		"object":    article,
+	}
+}
+
+// absoluteUrl ubah "/uploads/..." jadi URL lengkap
+func absoluteUrl(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimSuffix(helpers.GetBaseUrl(), "/") + path
+}
@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -51,7 +52,8 @@ func BlogStream(c *gin.Context) {
 	c.Header("Connection", "keep-alive")
 	c.Header("Access-Control-Allow-Origin", "*")
 
-	ch := make(chan string, 10)
+	// Buffer cukup besar untuk event generate_chunk yang datang beruntun
+	ch := make(chan string, 100)
 	addSSEClient(ch)
 	defer removeSSEClient(ch)
 
@@ -68,203 +70,138 @@ func BlogStream(c *gin.Context) {
 	})
 }
 
-func assignTagsToBlog(blog *models.Blog) {
-	prompt := fmt.Sprintf(`Berikan 3-5 tag yang relevan untuk artikel berjudul: "%s"
-Balas HANYA dengan nama tag, satu per baris, huruf kecil, tanpa penjelasan.
-Contoh:
-golang
-backend
-tutorial`, blog.Title)
+// assignTagsToBlog minta tag ke LLM dan pasang ke blog. Usulan dicocokkan ke kosakata tag yang ada;
+// tag baru hanya dibuat kalau kosakata masih kosong atau setting tag_allow_new aktif.
+func assignTagsToBlog(blog *models.Blog, ref helpers.LLMUsageRef) error {
+	ref.BlogId = &blog.Id
+	vocabulary := helpers.LoadTagVocabulary()
 
-	response, err := helpers.AskOllama(prompt)
+	matches, unmatched, err := suggestTags(vocabulary, blog.Title, blog.Description, ref)
 	if err != nil {
-		log.Printf("[TAGS ERROR] %v", err)
-		return
+		return err
 	}
 
-	lines := helpers.SplitLines(response)
 	var tags []models.Tag
+	for _, match := range matches {
+		tags = append(tags, match.Tag)
+	}
 
-	for _, line := range lines {
-		tagName := helpers.CleanLine(line)
-		tagName = strings.Trim(tagName, "`*_")
-		if tagName == "" || len(tagName) < 2 {
-			continue
-		}
-
-		var tag models.Tag
-		tagSlug := helpers.GenerateSlug(tagName)
-
-		if err := database.DB.Where("slug = ?", tagSlug).First(&tag).Error; err != nil {
-			tag = models.Tag{Name: tagName, Slug: tagSlug}
+	if len(vocabulary.Tags) == 0 || helpers.TagAllowNew() {
+		for _, tagName := range unmatched {
+			tag := models.Tag{Name: tagName, Slug: helpers.GenerateSlug(tagName)}
 			if err := database.DB.Create(&tag).Error; err != nil {
 				log.Printf("[TAGS ERROR] failed to create tag: %s, err: %v", tagName, err)
 				continue
 			}
+			tags = append(tags, tag)
 		}
-		tags = append(tags, tag)
+	} else if len(unmatched) > 0 {
+		log.Printf("[TAGS] skipped tags outside vocabulary for blog %s: %s", blog.Title, strings.Join(unmatched, ", "))
 	}
 
-	if len(tags) > 0 {
-		database.DB.Model(blog).Association("Tags").Replace(tags)
-		log.Printf("[TAGS OK] assigned %d tags to blog: %s", len(tags), blog.Title)
+	if len(tags) == 0 {
+		if len(unmatched) > 0 {
+			// Semua usulan di luar kosakata: biarkan editor memberi tag manual
+			return nil
+		}
+		return fmt.Errorf("no usable tags in response")
 	}
-}
-
-// POST /api/blogs/generate
-func GenerateAiBlog(c *gin.Context) {
-
-	var req structs.AiBlogGenerateRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
-			Success: false,
-			Message: "Validation Errors",
-			Errors:  helpers.TranslateErrorMessage(err),
-		})
-		return
+	if err := database.DB.Model(blog).Association("Tags").Replace(tags); err != nil {
+		return err
 	}
-
-	log.Printf("[GENERATE] start background: keyword=%s, total=%d", req.Keyword, req.Total)
-
-	go generateBlogsBackground(req.Keyword, req.Total)
-
-	c.JSON(http.StatusAccepted, structs.SuccessResponse{
-		Success: true,
-		Message: "Blog generation started in background",
-		Data: map[string]any{
-			"keyword": req.Keyword,
-			"total":   req.Total,
-			"status":  "processing",
-		},
-	})
+	log.Printf("[TAGS OK] assigned %d tags to blog: %s", len(tags), blog.Title)
+	return nil
 }
 
-func generateBlogsBackground(keyword string, total int) {
-
-	log.Printf("[BG] start: keyword=%s, total=%d", keyword, total)
-
-	// Broadcast: mulai generate
-	broadcastSSE(fmt.Sprintf(
-		`{"type":"generate_progress","keyword":"%s","saved":0,"total_target":%d,"status":"generating_titles"}`,
-		keyword, total,
-	))
-
-	titles, err := helpers.GenerateBlogTitles(keyword, total)
+// suggestTags minta tag ke LLM dengan daftar kosakata di prompt, lalu cocokkan setiap usulan
+// (persis, alias, atau typo). Usulan yang tidak cocok dikembalikan terpisah, tanpa duplikat.
+func suggestTags(vocabulary *helpers.TagVocabulary, title string, description string, ref helpers.LLMUsageRef) ([]helpers.TagMatch, []string, error) {
+	data := helpers.NewPromptData()
+	data.Title = title
+	data.Description = description
+	data.Tags = vocabulary.Names()
+	prompt, _, err := helpers.RenderPrompt(helpers.LLMTaskTags, data)
 	if err != nil {
-		log.Printf("[BG ERROR] generate titles: %v", err)
-		broadcastSSE(fmt.Sprintf(
-			`{"type":"generate_done","keyword":"%s","saved":0,"total_target":%d,"failed":true}`,
-			keyword, total,
-		))
-		return
+		return nil, nil, err
 	}
 
-	log.Printf("[BG] titles: %v", titles)
-
-	type titleWithRefs struct {
-		Title      string
-		References []string
+	response, err := helpers.AskLLM(helpers.LLMTaskTags, prompt, ref)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	var titlesWithRefs []titleWithRefs
+	var matches []helpers.TagMatch
+	var unmatched []string
+	seenTags := map[uint]bool{}
+	seenNames := map[string]bool{}
 
-	for _, title := range titles {
-		searchResults, err := helpers.SearchBrave(title)
-		if err != nil {
-			log.Printf("[BG BRAVE ERROR] %s: %v", title, err)
-			titlesWithRefs = append(titlesWithRefs, titleWithRefs{Title: title})
+	for _, line := range helpers.SplitLines(response) {
+		tagName := helpers.CleanLine(line)
+		tagName = strings.Trim(tagName, "`*_")
+		if tagName == "" || len(tagName) < 2 {
 			continue
 		}
 
-		var refs []string
-		for _, r := range searchResults {
-			if r.Description != "" {
-				refs = append(refs, r.Description)
+		if match, ok := vocabulary.Match(tagName); ok {
+			if !seenTags[match.Tag.Id] {
+				seenTags[match.Tag.Id] = true
+				matches = append(matches, match)
 			}
-			refs = append(refs, r.Url)
-		}
-		titlesWithRefs = append(titlesWithRefs, titleWithRefs{Title: title, References: refs})
-	}
-
-	// Scrape URLs
-	for i, twr := range titlesWithRefs {
-		var scrapedRefs []string
-		for _, ref := range twr.References {
-			if len(ref) > 4 && ref[:4] == "http" {
-				content, err := helpers.ScrapeArticle(ref)
-				if err != nil || content == "" {
-					continue
-				}
-				scrapedRefs = append(scrapedRefs, content)
-			} else {
-				scrapedRefs = append(scrapedRefs, ref)
-			}
-		}
-		titlesWithRefs[i].References = scrapedRefs
-	}
-
-	// Generate konten — broadcast progress per blog
-	savedCount := 0
-	totalTarget := len(titlesWithRefs)
-
-	for idx, twr := range titlesWithRefs {
-		// Broadcast: sedang nulis blog ke-N
-		broadcastSSE(fmt.Sprintf(
-			`{"type":"generate_progress","keyword":"%s","saved":%d,"total_target":%d,"current_title":"%s","status":"writing"}`,
-			keyword, savedCount, totalTarget,
-			strings.ReplaceAll(twr.Title, `"`, `'`),
-		))
-
-		if len(twr.References) == 0 {
-			log.Printf("[BG SKIP] no refs: %s", twr.Title)
 			continue
 		}
-
-		description, content, err := helpers.GenerateBlogContent(twr.Title, twr.References)
-		if err != nil {
-			log.Printf("[BG CONTENT ERROR] %s: %v", twr.Title, err)
-			continue
+		if key := helpers.TagKey(tagName); key != "" && !seenNames[key] {
+			seenNames[key] = true
+			unmatched = append(unmatched, tagName)
 		}
+	}
+	return matches, unmatched, nil
+}
 
-		content = helpers.CleanAIOutput(content)
-		description = helpers.CleanAIOutput(description)
-
-		blog := models.Blog{
-			Title:       twr.Title,
-			Slug:        helpers.GenerateSlug(twr.Title),
-			Description: description,
-			Content:     content,
-			Author:      "aibys",
-			Status:      "pending",
-		}
+// POST /api/blogs/generate
+func GenerateAiBlog(c *gin.Context) {
 
-		if err := database.DB.Create(&blog).Error; err != nil {
-			log.Printf("[BG DB ERROR] %s: %v", twr.Title, err)
-			continue
-		}
+	var req structs.AiBlogGenerateRequest
 
-		assignTagsToBlog(&blog)
-		savedCount++
-		log.Printf("[BG OK] saved %d/%d: %s", savedCount, totalTarget, blog.Title)
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
 
-		// Broadcast: blog ke-N selesai disimpan
-		broadcastSSE(fmt.Sprintf(
-			`{"type":"generate_progress","keyword":"%s","saved":%d,"total_target":%d,"current_title":"%s","status":"saved"}`,
-			keyword, savedCount, totalTarget,
-			strings.ReplaceAll(twr.Title, `"`, `'`),
-		))
+	job := models.AiJob{
+		Keyword: req.Keyword,
+		Total:   req.Total,
+		Status:  "queued",
+		Step:    "titles",
+	}
 
-		_ = idx
+	if err := database.DB.Create(&job).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to queue blog generation",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
 	}
 
-	log.Printf("[BG] done: keyword=%s, saved: %d/%d", keyword, savedCount, totalTarget)
+	log.Printf("[GENERATE] queued job %d: keyword=%s, total=%d", job.Id, req.Keyword, req.Total)
+	wakeAiWorkers()
 
-	// Broadcast: selesai semua
-	broadcastSSE(fmt.Sprintf(
-		`{"type":"generate_done","keyword":"%s","saved":%d,"total_target":%d,"failed":%t}`,
-		keyword, savedCount, totalTarget, savedCount == 0,
-	))
+	c.Header("Location", fmt.Sprintf("/api/blogs/generate/jobs/%d", job.Id))
+	c.JSON(http.StatusAccepted, structs.SuccessResponse{
+		Success: true,
+		Message: "Blog generation queued",
+		Data: map[string]any{
+			"job_id":  job.Id,
+			"keyword": job.Keyword,
+			"total":   job.Total,
+			"status":  job.Status,
+		},
+	})
 }
 
 // PUT /api/blogs/:id/publish
@@ -283,6 +220,10 @@ func PublishBlog(c *gin.Context) {
 
 	blog.Status = "published"
 	blog.RejectComment = ""
+	if blog.PublishedAt == nil {
+		now := time.Now()
+		blog.PublishedAt = &now
+	}
 
 	if err := database.DB.Save(&blog).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
@@ -294,6 +235,8 @@ func PublishBlog(c *gin.Context) {
 	}
 
 	go helpers.RevalidateFrontend("blog", blog.Slug)
+	go FederateBlog(blog)
+	go SendBlogWebmentions(blog)
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
 		Success: true,
@@ -361,36 +304,20 @@ func RejectBlog(c *gin.Context) {
 func regenerateBlog(blog *models.Blog, comment string) {
 	log.Printf("[REGENERATE] blog id: %d, title: %s", blog.Id, blog.Title)
 
-	prompt := fmt.Sprintf(`Kamu adalah Aibys, AI Assistant dari Arlchoose.
-
-Kamu sebelumnya menulis artikel berjudul: "%s"
-
-Deskripsi sebelumnya:
-%s
+	data := helpers.NewPromptData()
+	data.Title = blog.Title
+	data.Description = blog.Description
+	data.Content = blog.Content
+	data.Comment = comment
 
-Konten artikel sebelumnya:
-%s
-
-Artikel ini ditolak dengan catatan berikut dari editor:
-"%s"
-
-Tugasmu: Perbaiki artikel di atas sesuai catatan yang diberikan.
-
-Instruksi:
-- Perbaiki SESUAI catatan penolakan, jangan abaikan
-- Tetap tulis dalam Bahasa Indonesia
-- Format menggunakan HTML (h2, h3, p, ul, li, strong, em)
-- JANGAN gunakan backtick atau markdown, HANYA HTML murni
-- JANGAN ubah judul artikel
-- Pertahankan fakta dan informasi yang sudah benar
-
-Format response:
----DESCRIPTION---
-[deskripsi singkat artikel yang sudah diperbaiki]
----CONTENT---
-[konten artikel HTML yang sudah diperbaiki]`, blog.Title, blog.Description, blog.Content, comment)
+	prompt, templateId, err := helpers.RenderPrompt(helpers.LLMTaskRegenerate, data)
+	if err != nil {
+		log.Printf("[REGENERATE ERROR] blog id: %d, err: %v", blog.Id, err)
+		broadcastSSE(fmt.Sprintf(`{"type":"regenerate_done","blog_id":%d,"success":false}`, blog.Id))
+		return
+	}
 
-	response, err := helpers.AskOllama(prompt)
+	response, err := helpers.AskLLM(helpers.LLMTaskRegenerate, prompt, helpers.LLMUsageRef{BlogId: &blog.Id})
 	if err != nil {
 		log.Printf("[REGENERATE ERROR] blog id: %d, err: %v", blog.Id, err)
 		broadcastSSE(fmt.Sprintf(`{"type":"regenerate_done","blog_id":%d,"success":false}`, blog.Id))
@@ -404,7 +331,10 @@ Format response:
 		return
 	}
 
-	content = helpers.CleanAIOutput(content)
+	var sourceCount int64
+	database.DB.Model(&models.BlogSource{}).Where("blog_id = ?", blog.Id).Count(&sourceCount)
+
+	content = helpers.LinkCitations(helpers.CleanAIOutput(content), int(sourceCount))
 	description = helpers.CleanAIOutput(description)
 
 	var freshBlog models.Blog
@@ -416,12 +346,18 @@ Format response:
 	freshBlog.Content = content
 	freshBlog.Status = "pending"
 	freshBlog.RejectComment = ""
+	freshBlog.PromptTemplateId = &templateId
+
+	scores := scoreBlogSimilarity(&freshBlog, researchSourcesForBlog(freshBlog.Id))
+	applySimilarityAction(&freshBlog)
 
-	if err := database.DB.Select("description", "content", "status", "reject_comment").Save(&freshBlog).Error; err != nil {
+	if err := database.DB.Select("description", "content", "status", "reject_comment", "prompt_template_id").Save(&freshBlog).Error; err != nil {
 		log.Printf("[REGENERATE DB ERROR] blog id: %d, err: %v", blog.Id, err)
 		broadcastSSE(fmt.Sprintf(`{"type":"regenerate_done","blog_id":%d,"success":false}`, blog.Id))
 		return
 	}
+	saveSimilarityScores(&freshBlog, scores)
+	helpers.QueueEmbedding("blog", freshBlog.Id)
 
 	log.Printf("[REGENERATE OK] blog id: %d done", blog.Id)
 	broadcastSSE(fmt.Sprintf(`{"type":"regenerate_done","blog_id":%d,"success":true}`, blog.Id))
@@ -0,0 +1,354 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GET /api/blogs/generate/jobs — list job generate AI (auth)
+func FindAiJobs(c *gin.Context) {
+
+	var jobs []models.AiJob
+	var total int64
+
+	status := c.Query("status")
+	pg := helpers.GetPagination(c)
+
+	query := database.DB.Model(&models.AiJob{})
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	query.Count(&total)
+	query.Order("created_at desc").Limit(pg.Limit).Offset(pg.Offset).Find(&jobs)
+
+	totalPages := int(total) / pg.Limit
+	if int(total)%pg.Limit != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, structs.PaginatedResponse{
+		Success: true,
+		Message: "List Data AI Jobs",
+		Data:    jobs,
+		Meta: structs.PaginationMeta{
+			Page:       pg.Page,
+			Limit:      pg.Limit,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	})
+}
+
+// GET /api/blogs/generate/jobs/:id — status & progress job beserta task per judul (auth)
+func FindAiJobById(c *gin.Context) {
+
+	job, ok := findAiJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "AI Job Found",
+		Data:    aiJobResponse(job),
+	})
+}
+
+// POST /api/blogs/generate/jobs/:id/cancel — batalkan job; task yang sedang jalan berhenti di step berikutnya (auth)
+func CancelAiJob(c *gin.Context) {
+
+	job, ok := findAiJob(c)
+	if !ok {
+		return
+	}
+
+	if job.Status != "queued" && job.Status != "running" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Job cannot be cancelled",
+			Errors:  map[string]string{"status": "job is already " + job.Status},
+		})
+		return
+	}
+
+	database.DB.Model(&models.AiJob{}).Where("id = ?", job.Id).Update("cancel_requested", true)
+	database.DB.Model(&models.AiJobTask{}).
+		Where("job_id = ? AND status = ?", job.Id, "queued").
+		Update("status", "cancelled")
+
+	// Step dibaca ulang setelah flag cancel terpasang: worker titles bisa saja baru pindah ke step tasks
+	job, _ = reloadAiJob(job.Id)
+	if job.Step == "tasks" {
+		// Task yang sedang berjalan dibiarkan berhenti sendiri; job ditutup (beserta hitungannya)
+		// oleh refreshAiJobStatus setelah task terakhir selesai
+		refreshAiJobStatus(job.Id)
+	} else {
+		database.DB.Model(&models.AiJob{}).Where("id = ?", job.Id).Updates(map[string]any{
+			"status":      "cancelled",
+			"finished_at": time.Now(),
+		})
+		job, _ = reloadAiJob(job.Id)
+		broadcastJobDone(&job)
+	}
+
+	job, _ = reloadAiJob(job.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Job cancelled",
+		Data:    aiJobResponse(job),
+	})
+}
+
+// POST /api/blogs/generate/jobs/:id/retry — ulangi semua step yang gagal/dibatalkan (auth)
+func RetryAiJob(c *gin.Context) {
+
+	job, ok := findAiJob(c)
+	if !ok {
+		return
+	}
+
+	if job.Step == "titles" {
+		if job.Status != "failed" && job.Status != "cancelled" {
+			c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+				Success: false,
+				Message: "Nothing to retry",
+				Errors:  map[string]string{"status": "job is " + job.Status},
+			})
+			return
+		}
+		database.DB.Model(&job).Updates(map[string]any{
+			"status":           "queued",
+			"error":            "",
+			"cancel_requested": false,
+			"finished_at":      nil,
+		})
+	} else {
+		res := database.DB.Model(&models.AiJobTask{}).
+			Where("job_id = ? AND status IN ?", job.Id, []string{"failed", "cancelled"}).
+			Updates(map[string]any{"status": "queued", "error": "", "failed_step": "", "partial_content": ""})
+		if res.RowsAffected == 0 {
+			c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+				Success: false,
+				Message: "Nothing to retry",
+				Errors:  map[string]string{"tasks": "no failed or cancelled tasks"},
+			})
+			return
+		}
+		reopenAiJob(job.Id)
+	}
+
+	wakeAiWorkers()
+	job, _ = reloadAiJob(job.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Job queued for retry",
+		Data:    aiJobResponse(job),
+	})
+}
+
+// POST /api/blogs/generate/jobs/:id/tasks/:taskId/retry — ulangi satu judul dari step yang gagal (auth)
+func RetryAiJobTask(c *gin.Context) {
+
+	job, ok := findAiJob(c)
+	if !ok {
+		return
+	}
+
+	var task models.AiJobTask
+	if err := database.DB.Where("job_id = ?", job.Id).First(&task, c.Param("taskId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Task not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	if task.Status != "failed" && task.Status != "cancelled" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Nothing to retry",
+			Errors:  map[string]string{"status": "task is " + task.Status},
+		})
+		return
+	}
+
+	database.DB.Model(&task).Updates(map[string]any{"status": "queued", "error": "", "failed_step": "", "partial_content": ""})
+	reopenAiJob(job.Id)
+	wakeAiWorkers()
+
+	job, _ = reloadAiJob(job.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Task queued for retry",
+		Data:    aiJobResponse(job),
+	})
+}
+
+// POST /api/blogs/generate/jobs/:id/tasks/:taskId/draft — simpan artikel setengah jadi sebagai blog pending (auth)
+// Dipakai kalau proses mati saat menulis; setelah disimpan task lanjut ke step tag.
+func SaveAiTaskDraft(c *gin.Context) {
+
+	job, ok := findAiJob(c)
+	if !ok {
+		return
+	}
+
+	var task models.AiJobTask
+	if err := database.DB.Where("job_id = ?", job.Id).First(&task, c.Param("taskId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Task not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	if task.Status == "running" || task.Step != "write" || task.BlogId != nil || task.PartialContent == "" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "No draft to save",
+			Errors:  map[string]string{"task": "task has no partial draft"},
+		})
+		return
+	}
+
+	// Job yang sedang dibatalkan jangan dibuka lagi sebelum task yang berjalan berhenti
+	if job.CancelRequested && job.Status == "running" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "No draft to save",
+			Errors:  map[string]string{"status": "job is being cancelled"},
+		})
+		return
+	}
+
+	sources := helpers.DecodeResearchSources(task.ScrapedRefs)
+	description, content := helpers.ParseOllamaResponse(task.PartialContent)
+	content = helpers.LinkCitations(helpers.CleanAIOutput(content), len(sources))
+	if content == "" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "No draft to save",
+			Errors:  map[string]string{"task": "partial draft has no content yet"},
+		})
+		return
+	}
+
+	blog := models.Blog{
+		Title:       task.Title,
+		Slug:        helpers.UniqueSlug("blog", task.Title, 0),
+		Description: helpers.CleanAIOutput(description),
+		Content:     content,
+		Author:      "aibys",
+		Status:      "pending",
+	}
+	scores := scoreBlogSimilarity(&blog, sources)
+	applySimilarityAction(&blog)
+
+	// Sama dengan worker: blog, sumber, dan task disimpan bersama, task yang sudah punya blog ditolak
+	err := createAiTaskBlog(&task, &blog, sources, scores, map[string]any{
+		"status":      "queued",
+		"error":       "",
+		"failed_step": "",
+	})
+	if errors.Is(err, errAiTaskHasBlog) {
+		c.JSON(http.StatusConflict, structs.ErrorResponse{
+			Success: false,
+			Message: "Draft already saved",
+			Errors:  map[string]string{"task": err.Error()},
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to save draft",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+	helpers.LinkLLMUsageToBlog(task.Id, blog.Id)
+
+	reopenAiJob(job.Id)
+	wakeAiWorkers()
+
+	job, _ = reloadAiJob(job.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Draft saved as pending blog",
+		Data:    aiJobResponse(job),
+	})
+}
+
+// reopenAiJob kembalikan job ke running supaya task-nya diambil worker lagi
+func reopenAiJob(jobId uint) {
+	database.DB.Model(&models.AiJob{}).Where("id = ?", jobId).Updates(map[string]any{
+		"status":           "running",
+		"cancel_requested": false,
+		"finished_at":      nil,
+	})
+}
+
+func findAiJob(c *gin.Context) (models.AiJob, bool) {
+	var job models.AiJob
+	if err := database.DB.Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).First(&job, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Job not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return job, false
+	}
+	return job, true
+}
+
+func reloadAiJob(id uint) (models.AiJob, error) {
+	var job models.AiJob
+	err := database.DB.Preload("Tasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).First(&job, id).Error
+	return job, err
+}
+
+// aiJobResponse job + ringkasan progress
+func aiJobResponse(job models.AiJob) map[string]any {
+	counts := aiTaskCounts(job.Id)
+
+	percent := 0
+	if counts["total"] > 0 {
+		percent = int((counts["completed"] + counts["failed"] + counts["cancelled"]) * 100 / counts["total"])
+	}
+	if job.Status == "completed" {
+		percent = 100
+	}
+
+	return map[string]any{
+		"job": job,
+		"progress": map[string]any{
+			"total":     counts["total"],
+			"queued":    counts["queued"],
+			"running":   counts["running"],
+			"completed": counts["completed"],
+			"failed":    counts["failed"],
+			"cancelled": counts["cancelled"],
+			"percent":   percent,
+		},
+	}
+}
@@ -0,0 +1,823 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Lease diperpanjang terus selama worker hidup; kalau proses mati, job/task diambil worker lain setelah lease habis
+	aiLeaseDuration  = 2 * time.Minute
+	aiLeaseHeartbeat = 30 * time.Second
+	aiPollInterval   = 5 * time.Second
+
+	// Potongan artikel dikirim ke SSE per batch, draft disimpan ke database lebih jarang
+	aiChunkFlushInterval = 300 * time.Millisecond
+	aiChunkFlushSize     = 200
+	aiDraftSaveInterval  = 2 * time.Second
+)
+
+var (
+	errAiJobCancelled = errors.New("job was cancelled")
+	errAiTaskHasBlog  = errors.New("task already has a blog")
+
+	// Sinyal ke worker bahwa ada pekerjaan baru, supaya tidak menunggu poll berikutnya
+	aiWorkerWake = make(chan struct{}, 1)
+)
+
+// wakeAiWorkers bangunkan worker tanpa blocking
+func wakeAiWorkers() {
+	select {
+	case aiWorkerWake <- struct{}{}:
+	default:
+	}
+}
+
+// StartAiWorkers jalankan worker pool generate blog (jumlah dari env AI_WORKERS, default 2)
+func StartAiWorkers() {
+	count, err := strconv.Atoi(os.Getenv("AI_WORKERS"))
+	if err != nil || count < 1 {
+		count = 2
+	}
+
+	host, _ := os.Hostname()
+	for i := 1; i <= count; i++ {
+		workerId := fmt.Sprintf("%s-%d-%d", host, os.Getpid(), i)
+		go runAiWorker(workerId)
+	}
+	log.Printf("[AI WORKER] started %d workers", count)
+}
+
+func runAiWorker(workerId string) {
+	for {
+		// Kerjakan sampai tidak ada yang bisa diambil, lalu tunggu sinyal atau poll
+		if claimAndRunAiJob(workerId) || claimAndRunAiTask(workerId) {
+			continue
+		}
+		select {
+		case <-aiWorkerWake:
+		case <-time.After(aiPollInterval):
+		}
+	}
+}
+
+// leaseFree kondisi baris yang boleh diambil: belum di-lease atau lease-nya sudah habis
+const leaseFree = "(lease_until IS NULL OR lease_until < ?)"
+
+// claimAndRunAiJob ambil satu job yang masih di step titles
+func claimAndRunAiJob(workerId string) bool {
+
+	now := time.Now()
+	var job models.AiJob
+	err := database.DB.
+		Where("step = ? AND cancel_requested = ?", "titles", false).
+		Where("status = ? OR (status = ? AND lease_until < ?)", "queued", "running", now).
+		Order("id asc").
+		First(&job).Error
+	if err != nil {
+		return false
+	}
+
+	// Kondisi SELECT diulang di UPDATE: job yang sudah selesai/dibatalkan worker lain setelah SELECT tidak ikut terambil
+	res := database.DB.Model(&models.AiJob{}).
+		Where("id = ? AND step = ? AND cancel_requested = ? AND status IN ?", job.Id, "titles", false, []string{"queued", "running"}).
+		Where(leaseFree, now).
+		Updates(map[string]any{
+			"status":      "running",
+			"lease_owner": workerId,
+			"lease_until": now.Add(aiLeaseDuration),
+			"attempts":    gorm.Expr("attempts + 1"),
+			"started_at":  gorm.Expr("COALESCE(started_at, ?)", now),
+		})
+	if res.RowsAffected != 1 {
+		// Diambil worker lain duluan
+		return true
+	}
+
+	stop := startLeaseHeartbeat(&models.AiJob{}, job.Id, workerId)
+	defer stop()
+
+	runAiJobTitles(job.Id)
+	return true
+}
+
+// claimAndRunAiTask ambil satu task yang antre (atau yang worker-nya mati)
+func claimAndRunAiTask(workerId string) bool {
+
+	now := time.Now()
+	var task models.AiJobTask
+	err := database.DB.
+		Where("status = ? OR (status = ? AND lease_until < ?)", "queued", "running", now).
+		Where("job_id IN (?)", database.DB.Model(&models.AiJob{}).Select("id").
+			Where("status = ? AND cancel_requested = ?", "running", false)).
+		Order("id asc").
+		First(&task).Error
+	if err != nil {
+		return false
+	}
+
+	res := database.DB.Model(&models.AiJobTask{}).
+		Where("id = ? AND status IN ?", task.Id, []string{"queued", "running"}).
+		Where(leaseFree, now).
+		Updates(map[string]any{
+			"status":      "running",
+			"lease_owner": workerId,
+			"lease_until": now.Add(aiLeaseDuration),
+			"attempts":    gorm.Expr("attempts + 1"),
+		})
+	if res.RowsAffected != 1 {
+		return true
+	}
+
+	stop := startLeaseHeartbeat(&models.AiJobTask{}, task.Id, workerId)
+	defer stop()
+
+	runAiTask(task.Id)
+	return true
+}
+
+// startLeaseHeartbeat perpanjang lease secara berkala selama pekerjaan berjalan
+func startLeaseHeartbeat(model any, id uint, workerId string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(aiLeaseHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				database.DB.Model(model).
+					Where("id = ? AND lease_owner = ?", id, workerId).
+					Update("lease_until", time.Now().Add(aiLeaseDuration))
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// generateUniqueTitles minta judul ke LLM dan buang yang topiknya sudah pernah ditulis.
+// Kalau banyak yang terbuang, minta lagi (maksimal 3 kali) dengan judul terbuang ikut dihindari.
+func generateUniqueTitles(job *models.AiJob) ([]string, []models.SkippedTitle, uint, error) {
+
+	var blogs []models.Blog
+	database.DB.Select("id", "title", "description").
+		Where("status IN ?", []string{"pending", "published"}).
+		Order("created_at desc").Limit(500).Find(&blogs)
+
+	existing := make([]helpers.TopicDoc, 0, len(blogs))
+	var avoid []string
+	for i, b := range blogs {
+		existing = append(existing, helpers.TopicDoc{BlogId: b.Id, Title: b.Title, Description: b.Description})
+		if i < 30 {
+			avoid = append(avoid, b.Title)
+		}
+	}
+
+	var titles []string
+	var skipped []models.SkippedTitle
+	var templateId uint
+
+	for attempt := 0; attempt < 3 && len(titles) < job.Total; attempt++ {
+		need := job.Total - len(titles)
+
+		// Minta lebih banyak dari yang dibutuhkan supaya ada cadangan kalau ada yang duplikat
+		candidates, tmplId, err := helpers.GenerateBlogTitles(job.Keyword, need+min(need, 5), avoid, helpers.LLMUsageRef{JobId: &job.Id})
+		templateId = tmplId
+		if err != nil {
+			if len(titles) > 0 {
+				break
+			}
+			return nil, skipped, templateId, err
+		}
+
+		// Judul yang sudah diterima di putaran sebelumnya ikut jadi pembanding
+		compare := existing
+		for _, t := range titles {
+			compare = append(compare, helpers.TopicDoc{Title: t})
+		}
+
+		kept, dropped := helpers.FilterDuplicateTitles(candidates, compare)
+		for _, d := range dropped {
+			log.Printf("[AI JOB %d] skip %q: %.2f similar to %q (%s)", job.Id, d.Title, d.Score, d.SimilarTo, d.Method)
+			avoid = append(avoid, d.Title)
+		}
+		skipped = append(skipped, dropped...)
+
+		for _, t := range kept {
+			if len(titles) < job.Total {
+				titles = append(titles, t)
+			}
+		}
+	}
+
+	return titles, skipped, templateId, nil
+}
+
+// runAiJobTitles step pertama: minta judul ke LLM lalu pecah jadi task
+func runAiJobTitles(jobId uint) {
+
+	var job models.AiJob
+	if database.DB.First(&job, jobId).Error != nil {
+		return
+	}
+
+	if job.CancelRequested {
+		cancelAiJobTitles(&job)
+		return
+	}
+
+	log.Printf("[AI JOB %d] generating titles: keyword=%s, total=%d", job.Id, job.Keyword, job.Total)
+	broadcastJobProgress(&job, "generating_titles", "")
+
+	titles, skipped, templateId, err := generateUniqueTitles(&job)
+	// Cancel selama LLM membuat judul: judul dibuang, task tidak dibuat
+	if aiJobCancelled(job.Id) {
+		cancelAiJobTitles(&job)
+		return
+	}
+	if templateId != 0 {
+		database.DB.Model(&job).Update("titles_template_id", templateId)
+	}
+	if len(skipped) > 0 {
+		job.SkippedTitles = skipped
+		database.DB.Model(&job).Select("skipped_titles").Updates(&job)
+	}
+	if err == nil && len(titles) == 0 {
+		err = errors.New("no titles generated")
+		if len(skipped) > 0 {
+			err = errors.New("all candidate titles duplicate existing posts")
+		}
+	}
+	if err != nil {
+		log.Printf("[AI JOB %d ERROR] titles: %v", job.Id, err)
+		database.DB.Model(&job).Updates(map[string]any{
+			"status":      "failed",
+			"error":       "titles: " + err.Error(),
+			"lease_owner": "",
+			"lease_until": nil,
+			"finished_at": time.Now(),
+		})
+		database.DB.First(&job, jobId)
+		broadcastJobDone(&job)
+		return
+	}
+
+	// Pindah step hanya kalau job belum dibatalkan dan belum dipindah worker lain, di transaksi yang sama
+	// dengan pembuatan task supaya task tidak dobel
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.AiJob{}).
+			Where("id = ? AND step = ? AND cancel_requested = ?", job.Id, "titles", false).
+			Updates(map[string]any{
+				"step":        "tasks",
+				"error":       "",
+				"lease_owner": "",
+				"lease_until": nil,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return errAiJobCancelled
+		}
+		for i, title := range titles {
+			task := models.AiJobTask{JobId: job.Id, Position: i + 1, Title: title, Step: "search", Status: "queued"}
+			if err := tx.Create(&task).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errAiJobCancelled) {
+		if aiJobCancelled(job.Id) {
+			cancelAiJobTitles(&job)
+		}
+		return
+	}
+	if err != nil {
+		log.Printf("[AI JOB %d DB ERROR] %v", job.Id, err)
+		return
+	}
+
+	log.Printf("[AI JOB %d] titles: %v", job.Id, titles)
+	wakeAiWorkers()
+}
+
+// cancelAiJobTitles tutup job yang dibatalkan sebelum step titles selesai, supaya bisa di-retry
+func cancelAiJobTitles(job *models.AiJob) {
+	log.Printf("[AI JOB %d] cancelled while generating titles", job.Id)
+	database.DB.Model(job).Updates(map[string]any{
+		"status":      "cancelled",
+		"lease_owner": "",
+		"lease_until": nil,
+		"finished_at": time.Now(),
+	})
+	database.DB.First(job, job.Id)
+	broadcastJobDone(job)
+}
+
+// runAiTask kerjakan step task satu per satu mulai dari step yang tersimpan.
+// Hasil setiap step disimpan, jadi retry hanya mengulang step yang gagal.
+func runAiTask(taskId uint) {
+
+	var task models.AiJobTask
+	if database.DB.First(&task, taskId).Error != nil {
+		return
+	}
+	var job models.AiJob
+	if database.DB.First(&job, task.JobId).Error != nil {
+		return
+	}
+
+	// Proses sebelumnya mati di tengah menulis: jangan tulis ulang otomatis, biarkan editor memilih
+	// antara menyimpan draft yang ada atau retry dari awal
+	if task.Step == "write" && task.PartialContent != "" {
+		finishAiTask(&task, "failed", "write", errors.New("interrupted while writing, partial draft was kept"))
+		refreshAiJobStatus(job.Id)
+		return
+	}
+
+	for task.Step != "done" {
+		if aiJobCancelled(job.Id) {
+			finishAiTask(&task, "cancelled", "", errAiJobCancelled)
+			refreshAiJobStatus(job.Id)
+			return
+		}
+
+		broadcastJobProgress(&job, aiStepStatus(task.Step), task.Title)
+
+		step := task.Step
+		next, err := runAiTaskStep(&task, &job)
+		if err != nil {
+			if errors.Is(err, errAiJobCancelled) {
+				finishAiTask(&task, "cancelled", "", err)
+			} else {
+				log.Printf("[AI TASK %d ERROR] %s: %v", task.Id, step, err)
+				finishAiTask(&task, "failed", step, err)
+			}
+			refreshAiJobStatus(job.Id)
+			return
+		}
+
+		task.Step = next
+		database.DB.Model(&task).Updates(map[string]any{
+			"step":            task.Step,
+			"search_results":  task.SearchResults,
+			"scraped_refs":    task.ScrapedRefs,
+			"blog_id":         task.BlogId,
+			"partial_content": task.PartialContent,
+		})
+	}
+
+	finishAiTask(&task, "completed", "", nil)
+	log.Printf("[AI TASK %d OK] %s", task.Id, task.Title)
+
+	database.DB.First(&job, job.Id)
+	broadcastJobProgress(&job, "saved", task.Title)
+	refreshAiJobStatus(job.Id)
+}
+
+// runAiTaskStep jalankan satu step dan kembalikan step berikutnya
+func runAiTaskStep(task *models.AiJobTask, job *models.AiJob) (string, error) {
+
+	switch task.Step {
+
+	case "search":
+		results, err := helpers.SearchWeb(task.Title)
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return "", errors.New("no search results")
+		}
+		raw, _ := json.Marshal(results)
+		task.SearchResults = string(raw)
+		return "scrape", nil
+
+	case "scrape":
+		var results []helpers.SearchResult
+		json.Unmarshal([]byte(task.SearchResults), &results)
+
+		var sources []helpers.ResearchSource
+		for _, r := range results {
+			if aiJobCancelled(job.Id) {
+				return "", errAiJobCancelled
+			}
+			// Snippet dari mesin pencari jadi cadangan kalau halaman gagal diambil
+			title, content := r.Title, r.Description
+			if article, err := helpers.ScrapeArticle(r.Url); err == nil && article.Text != "" {
+				content = article.Text
+				if title == "" {
+					title = article.Title
+				}
+			}
+			if content == "" {
+				continue
+			}
+			sources = append(sources, helpers.ResearchSource{
+				Title:       title,
+				Url:         r.Url,
+				Snippet:     r.Description,
+				Content:     content,
+				ContentHash: helpers.ContentHash(content),
+				FetchedAt:   time.Now(),
+			})
+		}
+		if len(sources) == 0 {
+			return "", errors.New("no references could be collected")
+		}
+		raw, _ := json.Marshal(sources)
+		task.ScrapedRefs = string(raw)
+		return "write", nil
+
+	case "write":
+		// Blog sudah dibuat tapi step belum sempat tersimpan: lanjut ke tag, jangan buat blog lagi
+		if task.BlogId != nil {
+			return "tag", nil
+		}
+
+		sources := helpers.DecodeResearchSources(task.ScrapedRefs)
+
+		description, content, templateId, err := writeAiDraft(task, job, sources)
+		if err != nil {
+			return "", err
+		}
+		content = helpers.LinkCitations(content, len(sources))
+
+		// Job bisa dibatalkan selama LLM menulis; jangan simpan hasilnya
+		if aiJobCancelled(job.Id) {
+			return "", errAiJobCancelled
+		}
+
+		blog := models.Blog{
+			Title:       task.Title,
+			Slug:        helpers.UniqueSlug("blog", task.Title, 0),
+			Description: description,
+			Content:     content,
+			Author:      "aibys",
+			Status:      "pending",
+		}
+		if templateId != 0 {
+			blog.PromptTemplateId = &templateId
+		}
+
+		// Cek kemiripan sebelum masuk antrian pending
+		scores := scoreBlogSimilarity(&blog, sources)
+		applySimilarityAction(&blog)
+		if blog.SimilarityFlagged {
+			log.Printf("[AI JOB %d] %q flagged: source %.2f, blog %.2f", job.Id, task.Title, blog.SourceSimilarity, blog.BlogSimilarity)
+		}
+
+		if err := createAiTaskBlog(task, &blog, sources, scores, nil); err != nil {
+			return "", err
+		}
+		helpers.LinkLLMUsageToBlog(task.Id, blog.Id)
+		task.BlogId = &blog.Id
+		task.PartialContent = ""
+		return "tag", nil
+
+	case "tag":
+		var blog models.Blog
+		if task.BlogId == nil || database.DB.First(&blog, *task.BlogId).Error != nil {
+			return "", errors.New("generated blog no longer exists")
+		}
+		if err := assignTagsToBlog(&blog, aiTaskUsageRef(task)); err != nil {
+			return "", err
+		}
+		applyScheduleTags(&blog, job)
+		// Embedding dibuat setelah tag terpasang karena nama tag ikut di-embed
+		helpers.QueueEmbedding("blog", blog.Id)
+		return "seo", nil
+
+	case "seo":
+		var blog models.Blog
+		if task.BlogId == nil || database.DB.First(&blog, *task.BlogId).Error != nil {
+			return "", errors.New("generated blog no longer exists")
+		}
+		// SEO opsional: blog sudah tersimpan & bertag, jadi usulan yang gagal/tidak valid cukup dicatat.
+		// Editor bisa meminta ulang lewat POST /api/blogs/:id/seo.
+		proposal, err := helpers.ProposeSeo(&blog, aiTaskUsageRef(task))
+		if err != nil {
+			log.Printf("[AI TASK %d] seo skipped for blog %d: %v", task.Id, blog.Id, err)
+			return "done", nil
+		}
+		helpers.ApplySeo(&blog, proposal)
+		if err := saveBlogSeo(&blog); err != nil {
+			return "", err
+		}
+		return "done", nil
+	}
+
+	return "", fmt.Errorf("unknown step %s", task.Step)
+}
+
+// writeAiDraft tulis artikel lalu cek dengan quality gate. Draft yang tidak lolos ditulis ulang
+// dengan prompt koreksi sampai QualityMaxRetries kali; alasan setiap kegagalan disimpan di task.
+func writeAiDraft(task *models.AiJobTask, job *models.AiJob, sources []helpers.ResearchSource) (string, string, uint, error) {
+
+	var issues []models.QualityIssue
+	maxRetries := helpers.QualityMaxRetries()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if aiJobCancelled(job.Id) {
+				return "", "", 0, errAiJobCancelled
+			}
+			broadcastJobProgress(job, "retrying", task.Title)
+		}
+
+		draft := newAiDraftWriter(job, task)
+		description, content, templateId, err := helpers.GenerateBlogContent(task.Title, sources, issues, aiTaskUsageRef(task), draft.Write)
+		draft.Flush()
+		if err != nil {
+			return "", "", templateId, err
+		}
+		content = helpers.CleanAIOutput(content)
+		description = helpers.CleanAIOutput(description)
+
+		issues = helpers.CheckDraftQuality(description, content)
+		if len(issues) == 0 {
+			return description, content, templateId, nil
+		}
+
+		task.QualityFailures = append(task.QualityFailures, models.QualityFailure{
+			Attempt: len(task.QualityFailures) + 1,
+			Issues:  issues,
+			At:      time.Now(),
+		})
+		database.DB.Model(task).Select("quality_failures").Updates(task)
+		log.Printf("[AI TASK %d] quality gate failed (attempt %d): %s", task.Id, attempt+1, helpers.QualityIssuesSummary(issues))
+
+		if attempt >= maxRetries {
+			return "", "", templateId, fmt.Errorf("quality gate failed after %d attempts: %s", attempt+1, helpers.QualityIssuesSummary(issues))
+		}
+	}
+}
+
+// createAiTaskBlog simpan blog, sumber, dan step task dalam satu transaksi. blog_id dan status task
+// jadi kunci supaya blog tidak dibuat dua kali oleh worker dan request simpan draft.
+func createAiTaskBlog(task *models.AiJobTask, blog *models.Blog, sources []helpers.ResearchSource, scores []float64, taskUpdates map[string]any) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(blog).Error; err != nil {
+			return err
+		}
+		if err := saveBlogSources(tx, blog.Id, sources, scores); err != nil {
+			return err
+		}
+		updates := map[string]any{"blog_id": blog.Id, "step": "tag", "partial_content": ""}
+		for key, value := range taskUpdates {
+			updates[key] = value
+		}
+		res := tx.Model(&models.AiJobTask{}).
+			Where("id = ? AND blog_id IS NULL AND status = ?", task.Id, task.Status).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return errAiTaskHasBlog
+		}
+		return nil
+	})
+}
+
+// aiTaskUsageRef tautan metering LLM untuk panggilan dari sebuah task
+func aiTaskUsageRef(task *models.AiJobTask) helpers.LLMUsageRef {
+	return helpers.LLMUsageRef{JobId: &task.JobId, TaskId: &task.Id, BlogId: task.BlogId}
+}
+
+// finishAiTask simpan status akhir task dan lepas lease
+func finishAiTask(task *models.AiJobTask, status string, failedStep string, err error) {
+	task.Status = status
+	task.FailedStep = failedStep
+	task.Error = ""
+	if err != nil {
+		task.Error = err.Error()
+	}
+	database.DB.Model(task).Updates(map[string]any{
+		"status":      task.Status,
+		"failed_step": task.FailedStep,
+		"error":       task.Error,
+		"lease_owner": "",
+		"lease_until": nil,
+	})
+}
+
+// aiJobCancelled cek flag cancel langsung dari database (bisa di-set dari request lain)
+func aiJobCancelled(jobId uint) bool {
+	var job models.AiJob
+	if database.DB.Select("cancel_requested").First(&job, jobId).Error != nil {
+		return true
+	}
+	return job.CancelRequested
+}
+
+// refreshAiJobStatus tutup job kalau semua task sudah selesai
+func refreshAiJobStatus(jobId uint) {
+
+	var job models.AiJob
+	if database.DB.First(&job, jobId).Error != nil || job.Step != "tasks" {
+		return
+	}
+
+	counts := aiTaskCounts(jobId)
+	if counts["queued"]+counts["running"] > 0 {
+		return
+	}
+
+	status := "completed"
+	switch {
+	case counts["failed"] > 0:
+		status = "failed"
+	case job.CancelRequested:
+		status = "cancelled"
+	}
+
+	res := database.DB.Model(&models.AiJob{}).
+		Where("id = ? AND status = ?", jobId, "running").
+		Updates(map[string]any{"status": status, "finished_at": time.Now()})
+	if res.RowsAffected == 1 {
+		database.DB.First(&job, jobId)
+		log.Printf("[AI JOB %d] %s: saved %d/%d", job.Id, status, counts["completed"], counts["total"])
+		broadcastJobDone(&job)
+	}
+}
+
+// aiTaskCounts jumlah task per status
+func aiTaskCounts(jobId uint) map[string]int64 {
+	type statusCount struct {
+		Status string
+		Count  int64
+	}
+	var rows []statusCount
+	database.DB.Model(&models.AiJobTask{}).
+		Select("status, COUNT(*) as count").
+		Where("job_id = ?", jobId).
+		Group("status").
+		Scan(&rows)
+
+	counts := map[string]int64{"total": 0, "queued": 0, "running": 0, "completed": 0, "failed": 0, "cancelled": 0}
+	for _, r := range rows {
+		counts[r.Status] = r.Count
+		counts["total"] += r.Count
+	}
+	return counts
+}
+
+// aiStepStatus nama status SSE untuk setiap step (dipakai frontend yang sudah ada)
+func aiStepStatus(step string) string {
+	switch step {
+	case "search":
+		return "searching"
+	case "scrape":
+		return "scraping"
+	case "write":
+		return "writing"
+	case "tag":
+		return "tagging"
+	case "seo":
+		return "optimizing"
+	}
+	return step
+}
+
+// broadcastJobProgress kirim event generate_progress ke SSE
+func broadcastJobProgress(job *models.AiJob, status string, currentTitle string) {
+	counts := aiTaskCounts(job.Id)
+	total := counts["total"]
+	if total == 0 {
+		total = int64(job.Total)
+	}
+
+	msg, _ := json.Marshal(map[string]any{
+		"type":          "generate_progress",
+		"job_id":        job.Id,
+		"keyword":       job.Keyword,
+		"saved":         counts["completed"],
+		"total_target":  total,
+		"current_title": currentTitle,
+		"status":        status,
+	})
+	broadcastSSE(string(msg))
+}
+
+// broadcastJobDone kirim event generate_done ke SSE
+func broadcastJobDone(job *models.AiJob) {
+	counts := aiTaskCounts(job.Id)
+	total := counts["total"]
+	if total == 0 {
+		total = int64(job.Total)
+	}
+
+	msg, _ := json.Marshal(map[string]any{
+		"type":         "generate_done",
+		"job_id":       job.Id,
+		"keyword":      job.Keyword,
+		"saved":        counts["completed"],
+		"total_target": total,
+		"status":       job.Status,
+		"failed":       counts["completed"] == 0,
+	})
+	broadcastSSE(string(msg))
+}
+
+// aiDraftWriter terima potongan artikel dari LLM: relay ke SSE sebagai generate_chunk
+// dan simpan draft sementara ke task secara berkala
+type aiDraftWriter struct {
+	job       *models.AiJob
+	task      *models.AiJobTask
+	text      strings.Builder
+	pending   strings.Builder
+	offset    int
+	lastFlush time.Time
+	lastSave  time.Time
+}
+
+func newAiDraftWriter(job *models.AiJob, task *models.AiJobTask) *aiDraftWriter {
+	now := time.Now()
+	return &aiDraftWriter{job: job, task: task, lastFlush: now, lastSave: now}
+}
+
+func (w *aiDraftWriter) Write(chunk string) {
+	w.text.WriteString(chunk)
+	w.pending.WriteString(chunk)
+
+	if w.pending.Len() >= aiChunkFlushSize || time.Since(w.lastFlush) >= aiChunkFlushInterval {
+		w.broadcast()
+	}
+	if time.Since(w.lastSave) >= aiDraftSaveInterval {
+		w.save()
+	}
+}
+
+// Flush kirim sisa potongan dan simpan draft terakhir
+func (w *aiDraftWriter) Flush() {
+	w.broadcast()
+	if w.text.Len() > 0 {
+		w.save()
+	}
+}
+
+func (w *aiDraftWriter) broadcast() {
+	w.lastFlush = time.Now()
+	if w.pending.Len() == 0 {
+		return
+	}
+
+	chunk := w.pending.String()
+	msg, _ := json.Marshal(map[string]any{
+		"type":    "generate_chunk",
+		"job_id":  w.job.Id,
+		"task_id": w.task.Id,
+		"title":   w.task.Title,
+		"offset":  w.offset, // posisi chunk di artikel, untuk mendeteksi chunk yang terlewat
+		"chunk":   chunk,
+	})
+	broadcastSSE(string(msg))
+
+	w.offset += len(chunk)
+	w.pending.Reset()
+}
+
+func (w *aiDraftWriter) save() {
+	w.lastSave = time.Now()
+	w.task.PartialContent = w.text.String()
+	database.DB.Model(&models.AiJobTask{}).
+		Where("id = ?", w.task.Id).
+		Update("partial_content", w.task.PartialContent)
+}
+
+// saveBlogSources simpan sumber yang dipakai menulis blog, urutannya sama dengan nomor sitasi
+func saveBlogSources(tx *gorm.DB, blogId uint, sources []helpers.ResearchSource, scores []float64) error {
+	var rows []models.BlogSource
+	for i, src := range sources {
+		if src.Url == "" {
+			continue
+		}
+		rows = append(rows, models.BlogSource{
+			BlogId:      blogId,
+			Position:    i + 1,
+			Url:         src.Url,
+			Title:       src.Title,
+			Snippet:     src.Snippet,
+			ContentHash: src.ContentHash,
+			Similarity:  scores[i],
+			FetchedAt:   src.FetchedAt,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Create(&rows).Error
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// useDryRunDB ganti database.DB dengan koneksi dry-run: query dibangun tapi tidak pernah dikirim ke MySQL
+func useDryRunDB(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "test:test@tcp(127.0.0.1:1)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database.DB
+	database.DB = db
+	t.Cleanup(func() { database.DB = previous })
+}
+
+func TestWriteAiDraftWithFakeProvider(t *testing.T) {
+	useDryRunDB(t)
+	t.Setenv("LLM_PROVIDER", "fake")
+	t.Setenv("QUALITY_MIN_WORDS", "")
+	t.Setenv("QUALITY_MAX_RETRIES", "0")
+
+	job := &models.AiJob{Id: 1}
+	task := &models.AiJobTask{Id: 1, JobId: 1, Title: "Panduan Praktis Golang"}
+	sources := []helpers.ResearchSource{
+		{Title: "Sumber Pertama", Url: "https://example.com/satu", Content: "Isi referensi pertama."},
+	}
+
+	description, content, _, err := writeAiDraft(task, job, sources)
+	if err != nil {
+		t.Fatalf("writeAiDraft failed: %v", err)
+	}
+	if strings.TrimSpace(description) == "" {
+		t.Error("description is empty")
+	}
+	if issues := helpers.CheckDraftQuality(description, content); len(issues) > 0 {
+		t.Errorf("fake draft does not pass the quality gate: %s", helpers.QualityIssuesSummary(issues))
+	}
+	if len(task.QualityFailures) > 0 {
+		t.Errorf("expected no quality failures, got %d", len(task.QualityFailures))
+	}
+}
@@ -0,0 +1,268 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/blogs/generate/schedules — list jadwal generate AI (auth)
+func FindAiSchedules(c *gin.Context) {
+
+	var schedules []models.AiSchedule
+	database.DB.Preload("Tags").Order("id asc").Find(&schedules)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "List Data AI Schedules",
+		Data:    schedules,
+	})
+}
+
+// GET /api/blogs/generate/schedules/:id — detail jadwal (auth)
+func FindAiScheduleById(c *gin.Context) {
+
+	schedule, ok := findAiSchedule(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "AI Schedule Found",
+		Data:    schedule,
+	})
+}
+
+// POST /api/blogs/generate/schedules — buat jadwal baru (auth)
+func CreateAiSchedule(c *gin.Context) {
+
+	var req structs.AiScheduleRequest
+	if !bindAiSchedule(c, &req) {
+		return
+	}
+
+	schedule := models.AiSchedule{
+		Name:     req.Name,
+		Cron:     req.Cron,
+		Keywords: req.Keywords,
+		Total:    req.Total,
+		Enabled:  req.Enabled == nil || *req.Enabled,
+	}
+	if schedule.Enabled {
+		schedule.NextRunAt = nextAiScheduleRun(schedule.Cron, time.Now())
+	}
+
+	if err := database.DB.Create(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to create schedule",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+	// Kolom enabled punya default true, jadi false harus disimpan terpisah
+	if !schedule.Enabled {
+		database.DB.Model(&schedule).Update("enabled", false)
+	}
+	replaceAiScheduleTags(&schedule, req.TagIds)
+
+	database.DB.Preload("Tags").First(&schedule, schedule.Id)
+
+	c.JSON(http.StatusCreated, structs.SuccessResponse{
+		Success: true,
+		Message: "Schedule created successfully",
+		Data:    schedule,
+	})
+}
+
+// PUT /api/blogs/generate/schedules/:id — ubah jadwal (auth)
+func UpdateAiSchedule(c *gin.Context) {
+
+	schedule, ok := findAiSchedule(c)
+	if !ok {
+		return
+	}
+
+	var req structs.AiScheduleRequest
+	if !bindAiSchedule(c, &req) {
+		return
+	}
+
+	// Daftar keyword berubah: mulai lagi dari keyword pertama
+	if !slices.Equal(schedule.Keywords, req.Keywords) {
+		schedule.NextKeyword = 0
+	}
+	enabled := req.Enabled == nil || *req.Enabled
+	if enabled && (!schedule.Enabled || schedule.Cron != req.Cron || schedule.NextRunAt == nil) {
+		schedule.NextRunAt = nextAiScheduleRun(req.Cron, time.Now())
+	}
+	if !enabled {
+		schedule.NextRunAt = nil
+	}
+
+	schedule.Name = req.Name
+	schedule.Cron = req.Cron
+	schedule.Keywords = req.Keywords
+	schedule.Total = req.Total
+	schedule.Enabled = enabled
+
+	if err := database.DB.Omit("Tags").Save(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to update schedule",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+	replaceAiScheduleTags(&schedule, req.TagIds)
+
+	database.DB.Preload("Tags").First(&schedule, schedule.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Schedule updated successfully",
+		Data:    schedule,
+	})
+}
+
+// DELETE /api/blogs/generate/schedules/:id — hapus jadwal beserta riwayatnya; job yang sudah dibuat tetap ada (auth)
+func DeleteAiSchedule(c *gin.Context) {
+
+	schedule, ok := findAiSchedule(c)
+	if !ok {
+		return
+	}
+
+	database.DB.Model(&schedule).Association("Tags").Clear()
+	database.DB.Where("schedule_id = ?", schedule.Id).Delete(&models.AiScheduleRun{})
+
+	if err := database.DB.Delete(&schedule).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete schedule",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Schedule deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GET /api/blogs/generate/schedules/:id/runs — riwayat jadwal dijalankan beserta status job-nya (auth)
+func FindAiScheduleRuns(c *gin.Context) {
+
+	schedule, ok := findAiSchedule(c)
+	if !ok {
+		return
+	}
+
+	var runs []models.AiScheduleRun
+	var total int64
+	pg := helpers.GetPagination(c)
+
+	query := database.DB.Model(&models.AiScheduleRun{}).Where("schedule_id = ?", schedule.Id)
+	query.Count(&total)
+	query.Preload("Job").Order("created_at desc").Limit(pg.Limit).Offset(pg.Offset).Find(&runs)
+
+	totalPages := int(total) / pg.Limit
+	if int(total)%pg.Limit != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, structs.PaginatedResponse{
+		Success: true,
+		Message: "List Data AI Schedule Runs",
+		Data:    runs,
+		Meta: structs.PaginationMeta{
+			Page:       pg.Page,
+			Limit:      pg.Limit,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	})
+}
+
+// POST /api/blogs/generate/schedules/:id/run — jalankan jadwal sekarang tanpa mengubah jadwal berikutnya (auth)
+func RunAiScheduleNow(c *gin.Context) {
+
+	schedule, ok := findAiSchedule(c)
+	if !ok {
+		return
+	}
+
+	run, err := runAiSchedule(&schedule, "manual")
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to run schedule",
+			Errors:  map[string]string{"schedule": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, structs.SuccessResponse{
+		Success: true,
+		Message: "Blog generation queued",
+		Data:    run,
+	})
+}
+
+func findAiSchedule(c *gin.Context) (models.AiSchedule, bool) {
+	var schedule models.AiSchedule
+	if err := database.DB.Preload("Tags").First(&schedule, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Schedule not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return schedule, false
+	}
+	return schedule, true
+}
+
+// bindAiSchedule bind dan validasi request, termasuk ekspresi cron
+func bindAiSchedule(c *gin.Context, req *structs.AiScheduleRequest) bool {
+
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return false
+	}
+
+	cron, err := helpers.ParseCron(req.Cron)
+	if err == nil && cron.Next(time.Now()).IsZero() {
+		err = errNeverRuns
+	}
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"cron": err.Error()},
+		})
+		return false
+	}
+	return true
+}
+
+// replaceAiScheduleTags ganti tag target jadwal sesuai tag_ids
+func replaceAiScheduleTags(schedule *models.AiSchedule, tagIds []uint) {
+	var tags []models.Tag
+	if len(tagIds) > 0 {
+		database.DB.Where("id IN ?", tagIds).Find(&tags)
+	}
+	database.DB.Model(schedule).Association("Tags").Replace(tags)
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errNeverRuns = errors.New("cron expression never matches a date")
+
+const (
+	aiSchedulerTick = 30 * time.Second
+	// Lease leader lebih panjang dari beberapa tick; kalau instance leader mati, instance lain ambil alih
+	aiSchedulerLease    = 90 * time.Second
+	aiSchedulerLockName = "ai_schedules"
+)
+
+// StartAiScheduler cek jadwal AI yang sudah waktunya setiap 30 detik.
+// Semua instance menjalankan loop ini, tapi hanya pemegang lock leader yang membuat job.
+func StartAiScheduler() {
+	if os.Getenv("AI_SCHEDULER_DISABLED") == "true" {
+		return
+	}
+
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s-%d", host, os.Getpid())
+
+	go func() {
+		ticker := time.NewTicker(aiSchedulerTick)
+		defer ticker.Stop()
+		for range ticker.C {
+			if acquireSchedulerLock(aiSchedulerLockName, owner, aiSchedulerLease) {
+				runDueAiSchedules()
+			}
+		}
+	}()
+}
+
+// acquireSchedulerLock ambil atau perpanjang lock leader, true kalau instance ini leader-nya
+func acquireSchedulerLock(name string, owner string, lease time.Duration) bool {
+	now := time.Now()
+
+	database.DB.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.SchedulerLock{Name: name, LeaseUntil: now.Add(-time.Second)})
+
+	res := database.DB.Model(&models.SchedulerLock{}).
+		Where("name = ? AND (owner = ? OR lease_until < ?)", name, owner, now).
+		Updates(map[string]any{"owner": owner, "lease_until": now.Add(lease)})
+	return res.Error == nil && res.RowsAffected == 1
+}
+
+// runDueAiSchedules jalankan jadwal aktif yang next_run_at-nya sudah lewat.
+// Jadwal yang terlewat saat server mati hanya dijalankan sekali, bukan dikejar semua.
+func runDueAiSchedules() {
+
+	now := time.Now()
+	var schedules []models.AiSchedule
+	database.DB.Where("enabled = ? AND next_run_at <= ?", true, now).Find(&schedules)
+
+	for i := range schedules {
+		schedule := &schedules[i]
+
+		// Geser next_run_at dulu supaya jadwal tidak jalan dua kali kalau proses mati di tengah
+		res := database.DB.Model(&models.AiSchedule{}).
+			Where("id = ? AND next_run_at = ?", schedule.Id, schedule.NextRunAt).
+			Update("next_run_at", nextAiScheduleRun(schedule.Cron, now))
+		if res.RowsAffected != 1 {
+			continue
+		}
+
+		if _, err := runAiSchedule(schedule, "schedule"); err != nil {
+			log.Printf("[AI SCHEDULE %d ERROR] %v", schedule.Id, err)
+		}
+	}
+}
+
+// nextAiScheduleRun waktu jalan berikutnya, nil kalau cron tidak valid atau tidak pernah cocok
+func nextAiScheduleRun(expr string, after time.Time) *time.Time {
+	cron, err := helpers.ParseCron(expr)
+	if err != nil {
+		return nil
+	}
+	next := cron.Next(after)
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// runAiSchedule antrekan job untuk keyword giliran berikutnya dan catat riwayatnya
+func runAiSchedule(schedule *models.AiSchedule, trigger string) (models.AiScheduleRun, error) {
+
+	run := models.AiScheduleRun{ScheduleId: schedule.Id, Trigger: trigger}
+
+	var err error
+	if len(schedule.Keywords) == 0 {
+		err = errors.New("schedule has no keywords")
+	} else {
+		index := schedule.NextKeyword % len(schedule.Keywords)
+		run.Keyword = schedule.Keywords[index]
+
+		err = database.DB.Transaction(func(tx *gorm.DB) error {
+			job := models.AiJob{
+				Keyword:    run.Keyword,
+				Total:      schedule.Total,
+				Status:     "queued",
+				Step:       "titles",
+				ScheduleId: &schedule.Id,
+			}
+			if err := tx.Create(&job).Error; err != nil {
+				return err
+			}
+			run.JobId = &job.Id
+
+			now := time.Now()
+			schedule.NextKeyword = (index + 1) % len(schedule.Keywords)
+			schedule.LastRunAt = &now
+			return tx.Model(&models.AiSchedule{}).Where("id = ?", schedule.Id).Updates(map[string]any{
+				"next_keyword": schedule.NextKeyword,
+				"last_run_at":  now,
+			}).Error
+		})
+	}
+
+	if err != nil {
+		run.JobId = nil
+		run.Error = err.Error()
+	}
+	database.DB.Create(&run)
+
+	if err == nil {
+		log.Printf("[AI SCHEDULE %d] queued job %d: keyword=%s, total=%d", schedule.Id, *run.JobId, run.Keyword, schedule.Total)
+		wakeAiWorkers()
+	}
+	return run, err
+}
+
+// applyScheduleTags tambahkan tag target jadwal ke blog hasil job terjadwal
+func applyScheduleTags(blog *models.Blog, job *models.AiJob) {
+	if job.ScheduleId == nil {
+		return
+	}
+
+	var tags []models.Tag
+	database.DB.Model(&models.AiSchedule{Id: *job.ScheduleId}).Association("Tags").Find(&tags)
+	if len(tags) == 0 {
+		return
+	}
+	if err := database.DB.Model(blog).Association("Tags").Append(tags); err != nil {
+		log.Printf("[AI SCHEDULE %d] failed to add tags to blog %d: %v", *job.ScheduleId, blog.Id, err)
+	}
+}
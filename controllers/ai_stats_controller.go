@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/ai/stats?days=30 — pemakaian LLM: total token & waktu, rata-rata per artikel, tingkat gagal, per hari (auth)
+func AiStats(c *gin.Context) {
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		days = 30
+	}
+	days = min(days, 365)
+
+	// Total keseluruhan
+	type UsageTotals struct {
+		Calls            int64   `json:"calls"`
+		Failed           int64   `json:"failed"`
+		FailureRate      float64 `json:"failure_rate"`
+		PromptTokens     int64   `json:"prompt_tokens"`
+		CompletionTokens int64   `json:"completion_tokens"`
+		DurationMs       int64   `json:"duration_ms"`
+	}
+	var totals UsageTotals
+	database.DB.Raw(`
+		SELECT COUNT(*) as calls,
+			COALESCE(SUM(success = 0), 0) as failed,
+			COALESCE(SUM(prompt_tokens), 0) as prompt_tokens,
+			COALESCE(SUM(completion_tokens), 0) as completion_tokens,
+			COALESCE(SUM(duration_ms), 0) as duration_ms
+		FROM llm_usages
+	`).Scan(&totals)
+	totals.FailureRate = failureRate(totals.Failed, totals.Calls)
+
+	// Per jenis prompt (titles, content, tags, regenerate)
+	type TaskUsage struct {
+		Task             string  `json:"task"`
+		Calls            int64   `json:"calls"`
+		Failed           int64   `json:"failed"`
+		FailureRate      float64 `json:"failure_rate"`
+		PromptTokens     int64   `json:"prompt_tokens"`
+		CompletionTokens int64   `json:"completion_tokens"`
+		AvgDurationMs    float64 `json:"avg_duration_ms"`
+	}
+	var perTask []TaskUsage
+	database.DB.Raw(`
+		SELECT task, COUNT(*) as calls,
+			SUM(success = 0) as failed,
+			SUM(prompt_tokens) as prompt_tokens,
+			SUM(completion_tokens) as completion_tokens,
+			AVG(duration_ms) as avg_duration_ms
+		FROM llm_usages
+		GROUP BY task
+		ORDER BY calls DESC
+	`).Scan(&perTask)
+	for i := range perTask {
+		perTask[i].FailureRate = failureRate(perTask[i].Failed, perTask[i].Calls)
+	}
+
+	// Per provider & model
+	type ModelUsage struct {
+		Provider         string `json:"provider"`
+		Model            string `json:"model"`
+		Calls            int64  `json:"calls"`
+		PromptTokens     int64  `json:"prompt_tokens"`
+		CompletionTokens int64  `json:"completion_tokens"`
+		DurationMs       int64  `json:"duration_ms"`
+	}
+	var perModel []ModelUsage
+	database.DB.Raw(`
+		SELECT provider, model, COUNT(*) as calls,
+			SUM(prompt_tokens) as prompt_tokens,
+			SUM(completion_tokens) as completion_tokens,
+			SUM(duration_ms) as duration_ms
+		FROM llm_usages
+		GROUP BY provider, model
+		ORDER BY calls DESC
+	`).Scan(&perModel)
+
+	// Rata-rata per artikel: semua panggilan yang tertaut ke blog (tulis, retry quality gate, tag, regenerate).
+	// Panggilan judul dipakai bersama oleh satu job, jadi tidak dihitung per artikel.
+	type ArticleUsage struct {
+		Articles            int64   `json:"articles"`
+		AvgCalls            float64 `json:"avg_calls"`
+		AvgPromptTokens     float64 `json:"avg_prompt_tokens"`
+		AvgCompletionTokens float64 `json:"avg_completion_tokens"`
+		AvgDurationMs       float64 `json:"avg_duration_ms"`
+	}
+	var perArticle ArticleUsage
+	database.DB.Raw(`
+		SELECT COUNT(*) as articles,
+			COALESCE(AVG(calls), 0) as avg_calls,
+			COALESCE(AVG(prompt_tokens), 0) as avg_prompt_tokens,
+			COALESCE(AVG(completion_tokens), 0) as avg_completion_tokens,
+			COALESCE(AVG(duration_ms), 0) as avg_duration_ms
+		FROM (
+			SELECT blog_id, COUNT(*) as calls,
+				SUM(prompt_tokens) as prompt_tokens,
+				SUM(completion_tokens) as completion_tokens,
+				SUM(duration_ms) as duration_ms
+			FROM llm_usages
+			WHERE blog_id IS NOT NULL
+			GROUP BY blog_id
+		) per_blog
+	`).Scan(&perArticle)
+
+	// Tingkat gagal di level artikel: task generate yang berakhir failed
+	type ArticleOutcome struct {
+		Total       int64   `json:"total"`
+		Completed   int64   `json:"completed"`
+		Failed      int64   `json:"failed"`
+		FailureRate float64 `json:"failure_rate"`
+	}
+	var articles ArticleOutcome
+	database.DB.Model(&models.AiJobTask{}).Where("status IN ?", []string{"completed", "failed"}).Count(&articles.Total)
+	database.DB.Model(&models.AiJobTask{}).Where("status = ?", "completed").Count(&articles.Completed)
+	articles.Failed = articles.Total - articles.Completed
+	articles.FailureRate = failureRate(articles.Failed, articles.Total)
+
+	// Per hari
+	type DailyUsage struct {
+		Date             string `json:"date"`
+		Calls            int64  `json:"calls"`
+		Failed           int64  `json:"failed"`
+		PromptTokens     int64  `json:"prompt_tokens"`
+		CompletionTokens int64  `json:"completion_tokens"`
+		DurationMs       int64  `json:"duration_ms"`
+	}
+	var daily []DailyUsage
+	database.DB.Raw(`
+		SELECT DATE_FORMAT(created_at, '%Y-%m-%d') as date, COUNT(*) as calls,
+			SUM(success = 0) as failed,
+			SUM(prompt_tokens) as prompt_tokens,
+			SUM(completion_tokens) as completion_tokens,
+			SUM(duration_ms) as duration_ms
+		FROM llm_usages
+		WHERE created_at >= DATE_SUB(CURDATE(), INTERVAL ? DAY)
+		GROUP BY date
+		ORDER BY date ASC
+	`, days-1).Scan(&daily)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "AI Stats",
+		Data: map[string]any{
+			"totals":      totals,
+			"per_task":    perTask,
+			"per_model":   perModel,
+			"per_article": perArticle,
+			"articles":    articles,
+			"daily":       daily,
+			"days":        days,
+		},
+	})
+}
+
+// failureRate persentase gagal dengan 2 desimal
+func failureRate(failed int64, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(failed*10000/total) / 100
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/structs"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/ask?q= — "Ask Aibys": tanya jawab seputar portfolio, jawaban di-stream lewat SSE (publik).
+// Event: sources (sumber bernomor), chunk (potongan jawaban), done (jawaban lengkap), error.
+func AskAibys(c *gin.Context) {
+
+	question := strings.TrimSpace(c.Query("q"))
+	guard := helpers.RagGuard(question)
+	if guard == "invalid" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"q": "is required and must be at most 500 characters"},
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+
+	send := func(event string, data any) {
+		if c.Request.Context().Err() != nil {
+			return
+		}
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+
+	var sources []helpers.RagSource
+	method := ""
+	if guard == "" {
+		var err error
+		sources, method, err = helpers.RetrieveRagSources(question)
+		if err != nil {
+			log.Printf("[ASK ERROR] retrieval: %v", err)
+			send("error", gin.H{"message": "Failed to search portfolio content"})
+			return
+		}
+	}
+
+	// Diblokir guard atau tidak ada konten relevan: tolak tanpa memanggil LLM
+	if guard != "" || len(sources) == 0 {
+		answer := helpers.RagRefusal(question)
+		send("sources", []helpers.RagSource{})
+		send("chunk", gin.H{"text": answer})
+		send("done", gin.H{"answer": answer, "sources": []helpers.RagSource{}, "refused": true})
+		return
+	}
+
+	send("sources", sources)
+
+	data := helpers.NewPromptData()
+	data.Question = question
+	data.Sources = helpers.RagPromptSources(sources)
+	prompt, _, err := helpers.RenderPrompt(helpers.LLMTaskAsk, data)
+	if err != nil {
+		log.Printf("[ASK ERROR] prompt: %v", err)
+		send("error", gin.H{"message": "Failed to prepare answer"})
+		return
+	}
+
+	// Pengunjung menutup halaman = koneksi putus, generate ke LLM ikut dihentikan
+	ctx := c.Request.Context()
+	answer, err := helpers.AskLLMStream(ctx, helpers.LLMTaskAsk, prompt, helpers.LLMUsageRef{}, func(chunk string) {
+		send("chunk", gin.H{"text": chunk})
+	})
+	if ctx.Err() != nil {
+		log.Printf("[ASK] client disconnected, generation stopped")
+		return
+	}
+	if err != nil {
+		log.Printf("[ASK ERROR] llm: %v", err)
+		send("error", gin.H{"message": "Failed to generate answer"})
+		return
+	}
+
+	send("done", gin.H{
+		"answer":  strings.TrimSpace(answer),
+		"sources": sources,
+		"method":  method,
+		"refused": false,
+	})
+}
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Batas ukuran zip yang boleh di-import
+const maxImportBundleSize = 200 << 20
+
+var validBlogStatuses = map[string]bool{
+	"pending": true, "published": true, "rejected": true, "archived": true,
+}
+
+// GET /api/blogs/export — export blog ke zip Markdown + file upload (auth)
+// Query: ids=1,2,3 (opsional), status=published (opsional)
+func ExportBlogs(c *gin.Context) {
+
+	query := database.DB.Preload("Tags").Order("created_at asc")
+
+	if ids := c.Query("ids"); ids != "" {
+		var idList []uint
+		for _, raw := range strings.Split(ids, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(raw), 10, 64)
+			if err != nil {
+				c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+					Success: false,
+					Message: "Validation Errors",
+					Errors:  map[string]string{"ids": "ids must be a comma separated list of numbers"},
+				})
+				return
+			}
+			idList = append(idList, uint(id))
+		}
+		query = query.Where("id IN ?", idList)
+	}
+
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var blogs []models.Blog
+	query.Find(&blogs)
+
+	if len(blogs) == 0 {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "No blogs to export",
+			Errors:  map[string]string{"Error": "Record not found"},
+		})
+		return
+	}
+
+	posts := make([]helpers.BundlePost, 0, len(blogs))
+	for _, blog := range blogs {
+		var tags []string
+		for _, tag := range blog.Tags {
+			tags = append(tags, tag.Name)
+		}
+
+		posts = append(posts, helpers.BundlePost{
+			FrontMatter: helpers.BlogFrontMatter{
+				Title:       blog.Title,
+				Slug:        blog.Slug,
+				Description: blog.Description,
+				Status:      blog.Status,
+				Author:      blog.Author,
+				Tags:        tags,
+				CoverImage:  blog.CoverImage,
+				PublishedAt: blog.PublishedAt,
+				CreatedAt:   blog.CreatedAt,
+				UpdatedAt:   blog.UpdatedAt,
+			},
+			Content: blog.Content,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := helpers.WriteBlogBundle(&buf, posts); err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to export blogs",
+			Errors:  map[string]string{"export": err.Error()},
+		})
+		return
+	}
+
+	fileName := fmt.Sprintf("blogs-export-%s.zip", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", `attachment; filename="`+fileName+`"`)
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// POST /api/blogs/import — import zip Markdown, upsert berdasarkan slug (auth)
+// Form: file (zip), dry_run (bool), overwrite (bool)
+func ImportBlogs(c *gin.Context) {
+
+	var req structs.BlogImportRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"file": "file is required"},
+		})
+		return
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to read bundle",
+			Errors:  map[string]string{"file": err.Error()},
+		})
+		return
+	}
+	defer src.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(src, maxImportBundleSize+1))
+	if err == nil && len(raw) > maxImportBundleSize {
+		err = fmt.Errorf("bundle is larger than %d MB", maxImportBundleSize>>20)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to read bundle",
+			Errors:  map[string]string{"file": err.Error()},
+		})
+		return
+	}
+
+	bundle, err := helpers.ReadBlogBundle(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Invalid bundle",
+			Errors:  map[string]string{"file": err.Error()},
+		})
+		return
+	}
+
+	userId := c.MustGet("userId").(uint)
+	report := importBundle(bundle, req, userId)
+
+	if !req.DryRun && len(report.Creates)+len(report.Updates) > 0 {
+		go helpers.RevalidateFrontend("blog", "")
+	}
+
+	message := "Blogs imported successfully"
+	if req.DryRun {
+		message = "Dry run completed, nothing was saved"
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// importBundle jalankan upsert semua post di bundle, atau hanya rencana kalau dry run
+func importBundle(bundle *helpers.Bundle, req structs.BlogImportRequest, userId uint) structs.BlogImportReport {
+
+	report := structs.BlogImportReport{
+		DryRun:    req.DryRun,
+		Creates:   []string{},
+		Updates:   []string{},
+		Conflicts: []structs.BlogImportConflict{},
+	}
+
+	// old path → new path, dipakai bersama oleh semua post
+	uploadMapping := map[string]string{}
+	seenSlugs := map[string]bool{}
+
+	for _, post := range bundle.Posts {
+		fm := post.FrontMatter
+
+		conflict := func(slug string, reason string) {
+			report.Conflicts = append(report.Conflicts, structs.BlogImportConflict{File: post.File, Slug: slug, Reason: reason})
+		}
+
+		if strings.TrimSpace(fm.Title) == "" {
+			conflict(fm.Slug, "title is required")
+			continue
+		}
+
+		slug := helpers.GenerateSlug(fm.Slug)
+		if slug == "" {
+			slug = helpers.GenerateSlug(fm.Title)
+		}
+		if seenSlugs[slug] {
+			conflict(slug, "duplicate slug in bundle")
+			continue
+		}
+		seenSlugs[slug] = true
+
+		status := fm.Status
+		if status == "" {
+			status = "published"
+		}
+		if !validBlogStatuses[status] {
+			conflict(slug, "invalid status: "+status)
+			continue
+		}
+
+		author := fm.Author
+		if author != "aibys" {
+			author = "user"
+		}
+
+		var blog models.Blog
+		exists := database.DB.Where("slug = ?", slug).First(&blog).Error == nil
+
+		if !exists {
+			// Slug lama milik blog lain yang sudah di-rename
+			if _, ok := helpers.FindSlugRedirect("blog", slug); ok {
+				conflict(slug, "slug redirects to another blog")
+				continue
+			}
+		} else if !req.Overwrite && !fm.UpdatedAt.IsZero() && blog.UpdatedAt.After(fm.UpdatedAt) {
+			conflict(slug, "existing blog was modified after this export, use overwrite to replace it")
+			continue
+		}
+
+		// Tentukan path file upload yang direferensikan post ini; file baru ditulis setelah blog
+		// tersimpan supaya post yang gagal disimpan tidak meninggalkan file yatim di uploads/
+		pending := map[string][]byte{}
+		for _, ref := range helpers.FindUploadRefs(fm.CoverImage, post.Content) {
+			if _, done := uploadMapping[ref]; done {
+				continue
+			}
+			data, ok := bundle.Uploads[ref]
+			if !ok {
+				continue
+			}
+			uploadMapping[ref] = helpers.PlanBundleUpload(ref, data)
+			pending[ref] = data
+		}
+
+		// Path yang belum ditulis dilepas lagi supaya post berikutnya yang memakai file sama bisa menulisnya
+		release := func() {
+			for ref := range pending {
+				delete(uploadMapping, ref)
+			}
+		}
+
+		if req.DryRun {
+			report.Uploads += len(pending)
+			if exists {
+				report.Updates = append(report.Updates, slug)
+			} else {
+				report.Creates = append(report.Creates, slug)
+			}
+			continue
+		}
+
+		blog.Title = fm.Title
+		blog.Slug = slug
+		blog.Description = fm.Description
+		blog.Content = helpers.RewriteUploadRefs(post.Content, uploadMapping)
+		blog.CoverImage = helpers.RewriteUploadRefs(fm.CoverImage, uploadMapping)
+		blog.Author = author
+		blog.Status = status
+		if fm.PublishedAt != nil {
+			blog.PublishedAt = fm.PublishedAt
+		} else if status == "published" && blog.PublishedAt == nil {
+			now := time.Now()
+			blog.PublishedAt = &now
+		}
+
+		if exists {
+			if err := database.DB.Save(&blog).Error; err != nil {
+				conflict(slug, "failed to update: "+err.Error())
+				release()
+				continue
+			}
+			report.Updates = append(report.Updates, slug)
+		} else {
+			blog.UserId = &userId
+			if !fm.CreatedAt.IsZero() {
+				blog.CreatedAt = fm.CreatedAt
+			}
+			if err := database.DB.Create(&blog).Error; err != nil {
+				conflict(slug, "failed to create: "+err.Error())
+				release()
+				continue
+			}
+			report.Creates = append(report.Creates, slug)
+		}
+
+		for ref, data := range pending {
+			if err := helpers.SaveBundleUpload(uploadMapping[ref], data); err != nil {
+				log.Printf("[IMPORT ERROR] failed to save %s for blog %s: %v", ref, slug, err)
+				continue
+			}
+			report.Uploads++
+		}
+
+		database.DB.Model(&blog).Association("Tags").Replace(findOrCreateTags(fm.Tags))
+		helpers.QueueEmbedding("blog", blog.Id)
+	}
+
+	return report
+}
+
+// findOrCreateTags cari tag berdasarkan nama/slug, buat baru kalau belum ada
+func findOrCreateTags(names []string) []models.Tag {
+	var tags []models.Tag
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		var tag models.Tag
+		if err := database.DB.Where("name = ? OR slug = ?", name, helpers.GenerateSlug(name)).First(&tag).Error; err != nil {
+			tag = models.Tag{Name: name, Slug: helpers.GenerateSlug(name)}
+			if err := database.DB.Create(&tag).Error; err != nil {
+				continue
+			}
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
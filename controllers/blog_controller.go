@@ -6,8 +6,10 @@ import (
 	"arlchoose/backend-api/models"
 	"arlchoose/backend-api/structs"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GET /api/blogs — publik, hanya published
@@ -43,10 +45,15 @@ func FindBlogs(c *gin.Context) {
 		totalPages++
 	}
 
+	public := make([]models.PublicBlog, len(blogs))
+	for i, blog := range blogs {
+		public[i] = blog.Public()
+	}
+
 	c.JSON(http.StatusOK, structs.PaginatedResponse{
 		Success: true,
 		Message: "List Data Blogs",
-		Data:    blogs,
+		Data:    public,
 		Meta: structs.PaginationMeta{
 			Page:       pg.Page,
 			Limit:      pg.Limit,
@@ -118,6 +125,11 @@ func FindAllBlogs(c *gin.Context) {
 			"%"+search+"%", "%"+search+"%")
 	}
 
+	// ?flagged=true — hanya blog yang terlalu mirip sumber/blog lain
+	if c.Query("flagged") == "true" {
+		query = query.Where("blogs.similarity_flagged = ?", true)
+	}
+
 	query.Count(&total)
 	query.Order("blogs.created_at desc").Limit(pg.Limit).Offset(pg.Offset).Find(&blogs)
 
@@ -145,7 +157,30 @@ func FindBlogBySlug(c *gin.Context) {
 	slug := c.Param("slug")
 	var blog models.Blog
 
-	if err := database.DB.Preload("Tags").Preload("User").Where("slug = ?", slug).First(&blog).Error; err != nil {
+	if err := database.DB.Preload("Tags").Preload("User").Preload("Sources", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).Where("slug = ?", slug).First(&blog).Error; err != nil {
+
+		// Slug lama → arahkan ke slug yang sekarang
+		if blogId, ok := helpers.FindSlugRedirect("blog", slug); ok {
+			var moved models.Blog
+			if database.DB.Select("id", "slug").First(&moved, blogId).Error == nil {
+				location := "/api/blogs/" + moved.Slug
+				c.Header("Location", location)
+				c.JSON(http.StatusMovedPermanently, structs.SuccessResponse{
+					Success: true,
+					Message: "Blog moved permanently",
+					Data: structs.SlugRedirectResponse{
+						EntityType: "blog",
+						OldSlug:    slug,
+						Slug:       moved.Slug,
+						Location:   location,
+					},
+				})
+				return
+			}
+		}
+
 		c.JSON(http.StatusNotFound, structs.ErrorResponse{
 			Success: false,
 			Message: "Blog not found",
@@ -154,10 +189,18 @@ func FindBlogBySlug(c *gin.Context) {
 		return
 	}
 
+	// Daftar sumber untuk sitasi [n] di konten artikel AI
+	blog.ReferencesHtml = helpers.RenderReferencesHtml(blog.Sources)
+	// FAQPage JSON-LD untuk structured data di halaman artikel
+	blog.FaqSchema = helpers.FaqSchema(blog.Faq)
+
+	// Endpoint webmention untuk discovery
+	c.Header("Link", "<"+helpers.GetBaseUrl()+`/api/webmention>; rel="webmention"`)
+
 	c.JSON(http.StatusOK, structs.SuccessResponse{
 		Success: true,
 		Message: "Blog Found",
-		Data:    blog,
+		Data:    blog.Public(),
 	})
 }
 
@@ -191,14 +234,16 @@ func CreateBlog(c *gin.Context) {
 		coverImage = helpers.GetFileUrl(path)
 	}
 
+	now := time.Now()
 	blog := models.Blog{
 		Title:       req.Title,
-		Slug:        helpers.GenerateSlug(req.Title),
+		Slug:        helpers.UniqueSlug("blog", req.Title, 0),
 		Description: req.Description,
 		Content:     req.Content,
 		CoverImage:  coverImage,
 		Author:      "user",
 		Status:      "published",
+		PublishedAt: &now,
 		UserId:      &userId,
 	}
 
@@ -221,6 +266,9 @@ func CreateBlog(c *gin.Context) {
 	database.DB.Preload("Tags").Preload("User").First(&blog, blog.Id)
 
 	go helpers.RevalidateFrontend("blog", blog.Slug)
+	go FederateBlog(blog)
+	go SendBlogWebmentions(blog)
+	helpers.QueueEmbedding("blog", blog.Id)
 
 	c.JSON(http.StatusCreated, structs.SuccessResponse{
 		Success: true,
@@ -255,6 +303,19 @@ func UpdateBlog(c *gin.Context) {
 		return
 	}
 
+	if req.UpdateSeo {
+		seo, errs := bindBlogSeo(&req)
+		if errs != nil {
+			c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+				Success: false,
+				Message: "Validation Errors",
+				Errors:  errs,
+			})
+			return
+		}
+		helpers.ApplySeo(&blog, seo)
+	}
+
 	if _, err := c.FormFile("cover_image"); err == nil {
 		helpers.DeleteFile(blog.CoverImage)
 		path, err := helpers.UploadFile(c, "cover_image", "blogs")
@@ -269,8 +330,17 @@ func UpdateBlog(c *gin.Context) {
 		blog.CoverImage = helpers.GetFileUrl(path)
 	}
 
+	oldSlug := blog.Slug
+
+	// Slug hanya dibuat ulang kalau judul berubah, slug lama dicatat untuk redirect
+	if req.Title != blog.Title {
+		blog.Slug = helpers.UniqueSlug("blog", req.Title, blog.Id)
+	}
+	// Signature kemiripan dihitung ulang saat dibandingkan berikutnya
+	if req.Content != blog.Content {
+		blog.SimilaritySignature = ""
+	}
 	blog.Title = req.Title
-	blog.Slug = helpers.GenerateSlug(req.Title)
 	blog.Description = req.Description
 	blog.Content = req.Content
 
@@ -283,6 +353,8 @@ func UpdateBlog(c *gin.Context) {
 		return
 	}
 
+	helpers.RecordSlugRedirect("blog", blog.Id, oldSlug, blog.Slug)
+
 	if req.UpdateTags {
 		var tags []models.Tag
 		if len(req.TagIds) > 0 {
@@ -294,6 +366,10 @@ func UpdateBlog(c *gin.Context) {
 	database.DB.Preload("Tags").Preload("User").First(&blog, blog.Id)
 
 	go helpers.RevalidateFrontend("blog", blog.Slug)
+	if blog.Status == "published" {
+		go SendBlogWebmentions(blog)
+	}
+	helpers.QueueEmbedding("blog", blog.Id)
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
 		Success: true,
@@ -329,6 +405,9 @@ func DeleteBlog(c *gin.Context) {
 		return
 	}
 
+	helpers.DeleteSlugRedirects("blog", blog.Id)
+	helpers.RemoveEmbedding("blog", blog.Id)
+
 	go helpers.RevalidateFrontend("blog", "")
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
@@ -400,11 +479,28 @@ func BulkActionBlog(c *gin.Context) {
 
 	switch req.Action {
 	case "publish":
+		// Hanya blog yang baru dipublish yang dikirim ke follower & target webmention
+		var newIds []uint
+		database.DB.Model(&models.Blog{}).Where("id IN ? AND status <> ?", req.IDs, "published").Pluck("id", &newIds)
+
 		result := database.DB.Model(&models.Blog{}).
 			Where("id IN ?", req.IDs).
-			Updates(map[string]any{"status": "published", "reject_comment": ""})
+			Updates(map[string]any{
+				"status":         "published",
+				"reject_comment": "",
+				"published_at":   gorm.Expr("COALESCE(published_at, ?)", time.Now()),
+			})
 		affected = result.RowsAffected
 
+		if result.Error == nil && len(newIds) > 0 {
+			var published []models.Blog
+			database.DB.Where("id IN ?", newIds).Find(&published)
+			for _, blog := range published {
+				go FederateBlog(blog)
+				go SendBlogWebmentions(blog)
+			}
+		}
+
 	case "archive":
 		result := database.DB.Model(&models.Blog{}).
 			Where("id IN ?", req.IDs).
@@ -433,9 +529,11 @@ func BulkActionBlog(c *gin.Context) {
 		for _, b := range blogs {
 			helpers.DeleteFile(b.CoverImage)
 			database.DB.Model(&b).Association("Tags").Clear()
+			helpers.DeleteSlugRedirects("blog", b.Id)
 		}
 		result := database.DB.Where("id IN ?", req.IDs).Delete(&models.Blog{})
 		affected = result.RowsAffected
+		helpers.RemoveEmbedding("blog", req.IDs...)
 	}
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
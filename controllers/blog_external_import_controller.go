@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/blogs/import/external — import dari WordPress WXR atau export Medium (auth)
+// Form: source (wordpress|medium), file, dry_run (bool), overwrite (bool)
+func ImportExternalBlogs(c *gin.Context) {
+
+	var req structs.BlogExternalImportRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"file": "file is required"},
+		})
+		return
+	}
+
+	src, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to read file",
+			Errors:  map[string]string{"file": err.Error()},
+		})
+		return
+	}
+	defer src.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(src, maxImportBundleSize+1))
+	if err == nil && len(raw) > maxImportBundleSize {
+		err = fmt.Errorf("file is larger than %d MB", maxImportBundleSize>>20)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to read file",
+			Errors:  map[string]string{"file": err.Error()},
+		})
+		return
+	}
+
+	var posts []helpers.ExternalPost
+	if req.Source == "wordpress" {
+		posts, err = helpers.ParseWordpressWXR(raw)
+	} else {
+		posts, err = helpers.ParseMediumExport(raw)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Invalid export file",
+			Errors:  map[string]string{"file": err.Error()},
+		})
+		return
+	}
+
+	userId := c.MustGet("userId").(uint)
+	report := importExternalPosts(posts, req, userId)
+
+	if !req.DryRun && len(report.Creates)+len(report.Updates) > 0 {
+		go helpers.RevalidateFrontend("blog", "")
+	}
+
+	message := "Blogs imported successfully"
+	if req.DryRun {
+		message = "Dry run completed, nothing was saved"
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: message,
+		Data:    report,
+	})
+}
+
+// importExternalPosts simpan post hasil parse; post yang sudah pernah di-import dikenali dari URL asalnya
+func importExternalPosts(posts []helpers.ExternalPost, req structs.BlogExternalImportRequest, userId uint) structs.BlogImportReport {
+
+	report := structs.BlogImportReport{
+		DryRun:    req.DryRun,
+		Creates:   []string{},
+		Updates:   []string{},
+		Conflicts: []structs.BlogImportConflict{},
+	}
+
+	for _, post := range posts {
+
+		conflict := func(slug string, reason string) {
+			report.Conflicts = append(report.Conflicts, structs.BlogImportConflict{File: post.SourceUrl, Slug: slug, Reason: reason})
+		}
+
+		if post.Skipped != "" {
+			conflict(post.Slug, post.Skipped)
+			continue
+		}
+		if strings.TrimSpace(post.Title) == "" {
+			conflict(post.Slug, "title is required")
+			continue
+		}
+
+		// Sudah pernah di-import → update hanya kalau overwrite
+		var blog models.Blog
+		exists := false
+		if post.SourceUrl != "" {
+			var legacy models.BlogLegacyUrl
+			if database.DB.Where("url = ?", post.SourceUrl).First(&legacy).Error == nil {
+				exists = database.DB.First(&blog, legacy.BlogId).Error == nil
+			}
+		}
+
+		if exists && !req.Overwrite {
+			conflict(blog.Slug, "already imported, use overwrite to re-import")
+			continue
+		}
+
+		slugSource := post.Slug
+		if helpers.GenerateSlug(slugSource) == "" {
+			slugSource = post.Title
+		}
+
+		if req.DryRun {
+			report.Uploads += helpers.CountRemoteImages(post.Content)
+			if post.CoverImage != "" {
+				report.Uploads++
+			}
+			if exists {
+				report.Updates = append(report.Updates, blog.Slug)
+			} else {
+				report.Creates = append(report.Creates, helpers.UniqueSlug("blog", slugSource, 0))
+			}
+			continue
+		}
+
+		// Download & kompres gambar ke uploads/blogs
+		content, downloaded := helpers.LocalizeImages(post.Content, "blogs")
+		report.Uploads += downloaded
+
+		coverImage := blog.CoverImage
+		if post.CoverImage != "" {
+			if path, err := helpers.DownloadImage(post.CoverImage, "blogs"); err == nil {
+				helpers.DeleteFile(blog.CoverImage)
+				coverImage = helpers.GetFileUrl(path)
+				report.Uploads++
+			}
+		}
+
+		blog.Title = post.Title
+		blog.Description = post.Description
+		blog.Content = content
+		blog.CoverImage = coverImage
+		blog.Status = post.Status
+
+		// Pertahankan tanggal publish asli, baik saat create maupun overwrite;
+		// blog terbit tanpa tanggal dari sumber memakai waktu import
+		if blog.Status == "published" {
+			switch {
+			case !post.PublishedAt.IsZero():
+				publishedAt := post.PublishedAt
+				blog.PublishedAt = &publishedAt
+			case blog.PublishedAt == nil:
+				now := time.Now()
+				blog.PublishedAt = &now
+			}
+		}
+
+		if exists {
+			if err := database.DB.Save(&blog).Error; err != nil {
+				conflict(blog.Slug, "failed to update: "+err.Error())
+				continue
+			}
+			report.Updates = append(report.Updates, blog.Slug)
+		} else {
+			blog.Slug = helpers.UniqueSlug("blog", slugSource, 0)
+			blog.Author = "user"
+			blog.UserId = &userId
+			if !post.PublishedAt.IsZero() {
+				blog.CreatedAt = post.PublishedAt
+			}
+			if err := database.DB.Create(&blog).Error; err != nil {
+				conflict(blog.Slug, "failed to create: "+err.Error())
+				continue
+			}
+			report.Creates = append(report.Creates, blog.Slug)
+
+			if post.SourceUrl != "" {
+				legacy := models.BlogLegacyUrl{
+					BlogId: blog.Id,
+					Source: req.Source,
+					Url:    post.SourceUrl,
+				}
+				if u, err := url.Parse(post.SourceUrl); err == nil {
+					legacy.Path = u.Path
+				}
+				database.DB.Create(&legacy)
+			}
+		}
+
+		database.DB.Model(&blog).Association("Tags").Replace(findOrCreateTags(post.Tags))
+		helpers.QueueEmbedding("blog", blog.Id)
+	}
+
+	return report
+}
+
+// GET /api/redirects?url=... atau ?path=... — cari blog dari URL lama WordPress/Medium (publik)
+func ResolveLegacyUrl(c *gin.Context) {
+
+	oldUrl := c.Query("url")
+	path := c.Query("path")
+
+	// Hanya blog yang sudah terbit; slug draft/pending/rejected tidak boleh bocor lewat endpoint publik
+	query := database.DB.Preload("Blog").
+		Joins("JOIN blogs ON blogs.id = blog_legacy_urls.blog_id AND blogs.status = ?", "published")
+	switch {
+	case oldUrl != "":
+		query = query.Where("blog_legacy_urls.url = ?", oldUrl)
+	case path != "":
+		// Toleransi trailing slash: "/2019/05/post" dan "/2019/05/post/" dianggap sama
+		trimmed := strings.TrimSuffix(path, "/")
+		query = query.Where("blog_legacy_urls.path IN ?", []string{trimmed, trimmed + "/"})
+	default:
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"url": "url or path is required"},
+		})
+		return
+	}
+
+	var legacy models.BlogLegacyUrl
+	if err := query.First(&legacy).Error; err != nil || legacy.Blog == nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Redirect not found",
+			Errors:  map[string]string{"Error": "Record not found"},
+		})
+		return
+	}
+
+	location := "/api/blogs/" + legacy.Blog.Slug
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Redirect Found",
+		Data: structs.LegacyRedirectResponse{
+			Source:   legacy.Source,
+			OldUrl:   legacy.Url,
+			Slug:     legacy.Blog.Slug,
+			Location: location,
+		},
+	})
+}
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GET /api/blog-rewrites?blog_id=&status= — daftar usulan rewrite bagian artikel, terbaru dulu (auth)
+func FindBlogRewrites(c *gin.Context) {
+
+	var rewrites []models.BlogRewrite
+	query := database.DB.Order("id desc")
+	if blogId := c.Query("blog_id"); blogId != "" {
+		query = query.Where("blog_id = ?", blogId)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query.Find(&rewrites)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "List Data Blog Rewrites",
+		Data:    rewrites,
+	})
+}
+
+// POST /api/blog-rewrites — minta LLM menulis ulang satu bagian artikel (heading lewat anchor atau
+// paragraf lewat paragraph_index). Hanya menyimpan usulan + diff; konten & status blog tidak berubah (auth)
+func ProposeBlogRewrite(c *gin.Context) {
+
+	var req structs.BlogSectionRewriteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	var blog models.Blog
+	if err := database.DB.First(&blog, req.BlogId).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	req.Anchor = strings.TrimSpace(req.Anchor)
+	if (req.Anchor == "") == (req.ParagraphIndex == nil) {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"section": "provide either anchor or paragraph_index"},
+		})
+		return
+	}
+
+	start, end, err := helpers.FindSection(blog.Content, req.Anchor, req.ParagraphIndex)
+	if err != nil {
+		errs := map[string]string{"section": err.Error()}
+		if anchors := helpers.SectionAnchors(blog.Content); req.Anchor != "" && len(anchors) > 0 {
+			errs["anchors"] = strings.Join(anchors, ", ")
+		}
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Section not found",
+			Errors:  errs,
+		})
+		return
+	}
+	original := blog.Content[start:end]
+
+	// Instruksi bawaan diganti kalimat lengkap, selain itu dipakai apa adanya
+	instruction := strings.TrimSpace(req.Instruction)
+	if preset, ok := helpers.SectionInstructions[instruction]; ok {
+		instruction = preset
+	}
+
+	data := helpers.NewPromptData()
+	data.Title = blog.Title
+	data.Content = strings.TrimSpace(original)
+	data.Instruction = instruction
+
+	prompt, _, err := helpers.RenderPrompt(helpers.LLMTaskSection, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to render prompt",
+			Errors:  map[string]string{"prompt": err.Error()},
+		})
+		return
+	}
+
+	response, err := helpers.AskLLM(helpers.LLMTaskSection, prompt, helpers.LLMUsageRef{BlogId: &blog.Id})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to rewrite section",
+			Errors:  map[string]string{"llm": err.Error()},
+		})
+		return
+	}
+
+	var sourceCount int64
+	database.DB.Model(&models.BlogSource{}).Where("blog_id = ?", blog.Id).Count(&sourceCount)
+
+	proposed := helpers.LinkCitations(helpers.CleanAIOutput(response), int(sourceCount))
+	if !hasHtmlElement(proposed) {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "LLM returned invalid section",
+			Errors:  map[string]string{"proposed": "must be an HTML fragment"},
+		})
+		return
+	}
+
+	// Spasi/baris baru di sekitar bagian asli dipertahankan supaya sisa artikel tidak bergeser
+	trimmed := strings.TrimSpace(original)
+	lead := original[:strings.Index(original, trimmed)]
+	trail := original[len(lead)+len(trimmed):]
+	proposed = lead + proposed + trail
+
+	rewrite := models.BlogRewrite{
+		BlogId:         blog.Id,
+		Anchor:         req.Anchor,
+		ParagraphIndex: req.ParagraphIndex,
+		Instruction:    req.Instruction,
+		Original:       original,
+		Proposed:       proposed,
+		BaseHash:       helpers.ContentHash(blog.Content),
+		Status:         "pending",
+	}
+	if err := database.DB.Create(&rewrite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to save rewrite",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, structs.SuccessResponse{
+		Success: true,
+		Message: "Rewrite proposed",
+		Data: map[string]any{
+			"rewrite": rewrite,
+			"diff":    helpers.DiffHtmlBlocks(original, proposed),
+		},
+	})
+}
+
+// POST /api/blog-rewrites/:id/accept — terapkan usulan ke konten blog; status blog tidak berubah.
+// Kalau artikel sudah diedit sejak usulan dibuat, bagian asli harus masih ada persis satu kali (auth)
+func AcceptBlogRewrite(c *gin.Context) {
+
+	rewrite, ok := findPendingBlogRewrite(c)
+	if !ok {
+		return
+	}
+
+	var blog models.Blog
+	if err := database.DB.First(&blog, rewrite.BlogId).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	content, err := applyBlogRewrite(blog.Content, rewrite)
+	if err != nil {
+		c.JSON(http.StatusConflict, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog content has changed",
+			Errors:  map[string]string{"content": err.Error()},
+		})
+		return
+	}
+
+	blog.Content = content
+	blog.SimilaritySignature = ""
+	rewrite.Status = "accepted"
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&blog).Select("content", "similarity_signature").Updates(&blog).Error; err != nil {
+			return err
+		}
+		return tx.Model(&rewrite).Update("status", rewrite.Status).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to apply rewrite",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	go helpers.RevalidateFrontend("blog", blog.Slug)
+	helpers.QueueEmbedding("blog", blog.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Rewrite accepted",
+		Data: map[string]any{
+			"rewrite": rewrite,
+			"blog":    blog,
+		},
+	})
+}
+
+// POST /api/blog-rewrites/:id/discard — buang usulan tanpa mengubah blog (auth)
+func DiscardBlogRewrite(c *gin.Context) {
+
+	rewrite, ok := findPendingBlogRewrite(c)
+	if !ok {
+		return
+	}
+
+	rewrite.Status = "discarded"
+	if err := database.DB.Model(&rewrite).Update("status", rewrite.Status).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to discard rewrite",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Rewrite discarded",
+		Data:    rewrite,
+	})
+}
+
+func findPendingBlogRewrite(c *gin.Context) (models.BlogRewrite, bool) {
+	var rewrite models.BlogRewrite
+	if err := database.DB.First(&rewrite, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Rewrite not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return rewrite, false
+	}
+
+	if rewrite.Status != "pending" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Rewrite is no longer pending",
+			Errors:  map[string]string{"status": "rewrite is already " + rewrite.Status},
+		})
+		return rewrite, false
+	}
+	return rewrite, true
+}
+
+// applyBlogRewrite ganti bagian asli dengan usulan. Konten yang belum berubah dicari ulang lewat
+// anchor/paragraf; kalau sudah diedit, bagian asli dicari apa adanya dan harus unik.
+func applyBlogRewrite(content string, rewrite models.BlogRewrite) (string, error) {
+
+	if helpers.ContentHash(content) == rewrite.BaseHash {
+		start, end, err := helpers.FindSection(content, rewrite.Anchor, rewrite.ParagraphIndex)
+		if err == nil && content[start:end] == rewrite.Original {
+			return content[:start] + rewrite.Proposed + content[end:], nil
+		}
+	}
+
+	switch count := strings.Count(content, rewrite.Original); count {
+	case 1:
+		return strings.Replace(content, rewrite.Original, rewrite.Proposed, 1), nil
+	case 0:
+		return "", errors.New("the original section was edited after this rewrite was proposed")
+	default:
+		return "", fmt.Errorf("the original section appears %d times, cannot tell which one to replace", count)
+	}
+}
+
+// hasHtmlElement apakah teks berisi minimal satu elemen HTML (bukan teks polos)
+func hasHtmlElement(content string) bool {
+	for _, block := range helpers.ParseHtmlBlocks(content) {
+		if block.Tag != "#text" {
+			return true
+		}
+	}
+	return false
+}
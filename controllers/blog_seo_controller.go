@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/blogs/:id/seo?apply=true — minta LLM mengusulkan meta title, meta description, focus keyword & FAQ.
+// Tanpa apply hanya mengembalikan usulan untuk ditinjau editor (auth)
+func ProposeBlogSeo(c *gin.Context) {
+
+	var blog models.Blog
+
+	if err := database.DB.First(&blog, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	if helpers.HtmlToText(blog.Content) == "" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog has no content",
+			Errors:  map[string]string{"content": "is empty"},
+		})
+		return
+	}
+
+	proposal, err := helpers.ProposeSeo(&blog, helpers.LLMUsageRef{BlogId: &blog.Id})
+	if err != nil {
+		var invalid *helpers.SeoValidationError
+		if errors.As(err, &invalid) {
+			c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+				Success: false,
+				Message: "LLM returned invalid SEO metadata",
+				Errors:  invalid.Errors,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to generate SEO metadata",
+			Errors:  map[string]string{"llm": err.Error()},
+		})
+		return
+	}
+
+	applied := c.Query("apply") == "true"
+	if applied {
+		helpers.ApplySeo(&blog, proposal)
+		if err := saveBlogSeo(&blog); err != nil {
+			c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+				Success: false,
+				Message: "Failed to save SEO metadata",
+				Errors:  helpers.TranslateErrorMessage(err),
+			})
+			return
+		}
+		go helpers.RevalidateFrontend("blog", blog.Slug)
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "SEO metadata generated",
+		Data: map[string]any{
+			"blog_id":    blog.Id,
+			"applied":    applied,
+			"proposal":   proposal,
+			"faq_schema": helpers.FaqSchema(proposal.Faq),
+		},
+	})
+}
+
+// saveBlogSeo simpan kolom SEO saja; faq lewat Select+Updates supaya serializer JSON dipakai
+func saveBlogSeo(blog *models.Blog) error {
+	return database.DB.Model(blog).Select("meta_title", "meta_description", "focus_keyword", "faq").Updates(blog).Error
+}
+
+// bindBlogSeo baca field SEO dari form update blog; faq dikirim sebagai string JSON
+func bindBlogSeo(req *structs.BlogUpdateRequest) (helpers.SeoProposal, map[string]string) {
+
+	seo := helpers.SeoProposal{
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+		FocusKeyword:    req.FocusKeyword,
+	}
+	if req.Faq != "" {
+		if err := json.Unmarshal([]byte(req.Faq), &seo.Faq); err != nil {
+			return seo, map[string]string{"faq": "must be a JSON array of {question, answer}"}
+		}
+	}
+
+	if errs := helpers.ValidateSeo(seo, false); len(errs) > 0 {
+		return seo, errs
+	}
+	return seo, nil
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/blogs/:id/similarity — hitung ulang skor kemiripan blog (auth)
+func CheckBlogSimilarity(c *gin.Context) {
+
+	var blog models.Blog
+
+	if err := database.DB.First(&blog, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	sources := researchSourcesForBlog(blog.Id)
+	scores := scoreBlogSimilarity(&blog, sources)
+
+	if err := saveSimilarityScores(&blog, scores); err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to save similarity scores",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Similarity checked",
+		Data: map[string]any{
+			"blog_id":            blog.Id,
+			"threshold":          helpers.SimilarityThreshold(),
+			"source_similarity":  blog.SourceSimilarity,
+			"source_scores":      scores,
+			"blog_similarity":    blog.BlogSimilarity,
+			"similar_blog_id":    blog.SimilarBlogId,
+			"similarity_flagged": blog.SimilarityFlagged,
+		},
+	})
+}
+
+// scoreBlogSimilarity isi skor kemiripan di blog (belum disimpan) dan kembalikan skor per sumber.
+// Sumber tanpa isi (task lama) dilewati dengan skor 0.
+func scoreBlogSimilarity(blog *models.Blog, sources []helpers.ResearchSource) []float64 {
+
+	shingles := helpers.Shingles(blog.Content)
+
+	scores := make([]float64, len(sources))
+	blog.SourceSimilarity = 0
+	for i, src := range sources {
+		if src.Content == "" {
+			continue
+		}
+		scores[i] = helpers.Containment(shingles, helpers.Shingles(src.Content))
+		blog.SourceSimilarity = max(blog.SourceSimilarity, scores[i])
+	}
+
+	sig := helpers.MinHash(shingles)
+	blog.SimilaritySignature = helpers.EncodeMinHash(sig)
+	blog.BlogSimilarity = 0
+	blog.SimilarBlogId = nil
+	for id, other := range blogSignatures(blog.Id) {
+		if score := helpers.MinHashSimilarity(sig, other); score > blog.BlogSimilarity {
+			blog.BlogSimilarity = score
+			similarId := id
+			blog.SimilarBlogId = &similarId
+		}
+	}
+
+	threshold := helpers.SimilarityThreshold()
+	blog.SimilarityFlagged = blog.SourceSimilarity >= threshold || blog.BlogSimilarity >= threshold
+	now := time.Now()
+	blog.SimilarityCheckedAt = &now
+
+	return scores
+}
+
+// applySimilarityAction tolak otomatis blog yang terlalu mirip kalau similarity_action=reject
+func applySimilarityAction(blog *models.Blog) {
+	if !blog.SimilarityFlagged || helpers.SimilarityAction() != "reject" {
+		return
+	}
+	blog.Status = "rejected"
+	blog.RejectComment = similarityComment(blog)
+}
+
+// similarityComment catatan penolakan, dipakai juga sebagai instruksi saat regenerate
+func similarityComment(blog *models.Blog) string {
+	if blog.SourceSimilarity >= blog.BlogSimilarity {
+		return fmt.Sprintf("Ditolak otomatis: %.0f%% frasa sama persis dengan salah satu referensi. Tulis ulang dengan kalimat sendiri.",
+			blog.SourceSimilarity*100)
+	}
+	return fmt.Sprintf("Ditolak otomatis: %.0f%% mirip dengan artikel lain (id %d). Gunakan sudut pandang yang berbeda.",
+		blog.BlogSimilarity*100, *blog.SimilarBlogId)
+}
+
+// saveSimilarityScores simpan skor ke blog dan ke tiap blog_sources (urutan = position)
+func saveSimilarityScores(blog *models.Blog, scores []float64) error {
+	err := database.DB.Model(blog).Select(
+		"source_similarity", "blog_similarity", "similar_blog_id",
+		"similarity_flagged", "similarity_checked_at", "similarity_signature",
+	).Updates(blog).Error
+	if err != nil {
+		return err
+	}
+
+	for i, score := range scores {
+		database.DB.Model(&models.BlogSource{}).
+			Where("blog_id = ? AND position = ?", blog.Id, i+1).
+			Update("similarity", score)
+	}
+	return nil
+}
+
+// blogSignatures signature MinHash blog lain yang pending/published.
+// Blog yang belum punya signature (dibuat manual atau sudah diedit) dihitung dan disimpan sekarang.
+func blogSignatures(excludeId uint) map[uint][]uint64 {
+	var blogs []models.Blog
+	database.DB.Select("id", "similarity_signature").
+		Where("id <> ? AND status IN ?", excludeId, []string{"pending", "published"}).
+		Find(&blogs)
+
+	signatures := make(map[uint][]uint64, len(blogs))
+	for _, b := range blogs {
+		sig := helpers.DecodeMinHash(b.SimilaritySignature)
+		if sig == nil {
+			var full models.Blog
+			if database.DB.Select("id", "content").First(&full, b.Id).Error != nil {
+				continue
+			}
+			sig = helpers.MinHash(helpers.Shingles(full.Content))
+			database.DB.Model(&models.Blog{}).Where("id = ?", b.Id).
+				UpdateColumn("similarity_signature", helpers.EncodeMinHash(sig))
+		}
+		signatures[b.Id] = sig
+	}
+	return signatures
+}
+
+// researchSourcesForBlog isi referensi yang dipakai saat blog di-generate, diambil dari task job AI
+func researchSourcesForBlog(blogId uint) []helpers.ResearchSource {
+	var task models.AiJobTask
+	if err := database.DB.Where("blog_id = ?", blogId).Order("id desc").First(&task).Error; err != nil {
+		return nil
+	}
+	sources := helpers.DecodeResearchSources(task.ScrapedRefs)
+	if len(sources) == 0 {
+		log.Printf("[SIMILARITY] no stored references for blog %d", blogId)
+	}
+	return sources
+}
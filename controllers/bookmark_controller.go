@@ -5,6 +5,7 @@ import (
 	"arlchoose/backend-api/helpers"
 	"arlchoose/backend-api/models"
 	"arlchoose/backend-api/structs"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -123,6 +124,8 @@ func CreateBookmark(c *gin.Context) {
 	database.DB.Preload("Topics").First(&bookmark, bookmark.Id)
 
 	go helpers.RevalidateFrontend("bookmark", "")
+	helpers.QueueEmbedding("bookmark", bookmark.Id)
+	go fillBookmarkMetadata(bookmark.Id)
 
 	c.JSON(http.StatusCreated, structs.SuccessResponse{
 		Success: true,
@@ -185,6 +188,8 @@ func UpdateBookmark(c *gin.Context) {
 	database.DB.Preload("Topics").First(&bookmark, bookmark.Id)
 
 	go helpers.RevalidateFrontend("bookmark", "")
+	helpers.QueueEmbedding("bookmark", bookmark.Id)
+	go fillBookmarkMetadata(bookmark.Id)
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
 		Success: true,
@@ -217,6 +222,8 @@ func DeleteBookmark(c *gin.Context) {
 		return
 	}
 
+	helpers.RemoveEmbedding("bookmark", bookmark.Id)
+
 	go helpers.RevalidateFrontend("bookmark", "")
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
@@ -225,3 +232,28 @@ func DeleteBookmark(c *gin.Context) {
 		Data:    nil,
 	})
 }
+
+// fillBookmarkMetadata isi description kosong dari halaman bookmark, dijalankan di background supaya
+// host yang lambat tidak menahan request. Gagal ambil halaman (robots.txt, timeout) cukup dicatat.
+func fillBookmarkMetadata(id uint) {
+
+	var bookmark models.Bookmark
+	if database.DB.First(&bookmark, id).Error != nil || bookmark.Description != "" {
+		return
+	}
+
+	article, err := helpers.ScrapeArticle(bookmark.Url)
+	if err != nil || article.Excerpt == "" {
+		log.Printf("[BOOKMARK] no metadata for %d (%s): %v", bookmark.Id, bookmark.Url, err)
+		return
+	}
+
+	// Jangan timpa description yang diisi editor selama halaman diambil
+	res := database.DB.Model(&models.Bookmark{}).
+		Where("id = ? AND (description = '' OR description IS NULL)", bookmark.Id).
+		Update("description", article.Excerpt)
+	if res.RowsAffected == 1 {
+		helpers.RevalidateFrontend("bookmark", "")
+		helpers.QueueEmbedding("bookmark", bookmark.Id)
+	}
+}
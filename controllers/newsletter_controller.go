@@ -0,0 +1,804 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Bounce sebanyak ini berturut-turut → subscriber dinonaktifkan
+	maxSoftBounces = 3
+
+	// Lock leader digest: lease lebih panjang dari tick satu jam supaya leader tetap sama antar tick
+	newsletterSchedulerLockName = "newsletter_digest"
+	newsletterSchedulerLease    = 90 * time.Minute
+)
+
+var (
+	digestMutex    sync.Mutex
+	errNoNewBlogs  = errors.New("no new published blogs since last digest")
+	errDigestBusy  = errors.New("another digest is still being sent")
+	subscribeReply = "Please check your email to confirm your subscription"
+)
+
+// POST /api/newsletter/subscribe — daftar newsletter, kirim email konfirmasi (publik)
+func SubscribeNewsletter(c *gin.Context) {
+
+	var req structs.NewsletterSubscribeRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	var subscriber models.Subscriber
+	if err := database.DB.Where("email = ?", email).First(&subscriber).Error; err != nil {
+		subscriber = models.Subscriber{
+			Email:            email,
+			Name:             req.Name,
+			Status:           "pending",
+			UnsubscribeToken: helpers.GenerateRandomToken(),
+		}
+	}
+
+	// Subscriber aktif tidak perlu konfirmasi ulang; response sengaja sama supaya email tidak bisa ditebak
+	if subscriber.Status == "active" {
+		c.JSON(http.StatusOK, structs.SuccessResponse{
+			Success: true,
+			Message: subscribeReply,
+			Data:    nil,
+		})
+		return
+	}
+
+	subscriber.Status = "pending"
+	subscriber.ConfirmToken = helpers.GenerateRandomToken()
+	if req.Name != "" {
+		subscriber.Name = req.Name
+	}
+
+	if err := database.DB.Save(&subscriber).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to subscribe",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	var tags []models.Tag
+	if len(req.TagIds) > 0 {
+		database.DB.Where("id IN ?", req.TagIds).Find(&tags)
+	}
+	database.DB.Model(&subscriber).Association("Tags").Replace(tags)
+
+	go sendConfirmationEmail(subscriber)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: subscribeReply,
+		Data:    nil,
+	})
+}
+
+// GET /api/newsletter/confirm?token= — konfirmasi double opt-in (publik)
+func ConfirmNewsletter(c *gin.Context) {
+
+	token := c.Query("token")
+	var subscriber models.Subscriber
+
+	if token == "" || database.DB.Where("confirm_token = ? AND status = ?", token, "pending").First(&subscriber).Error != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Invalid or expired confirmation link",
+			Errors:  map[string]string{"token": "invalid token"},
+		})
+		return
+	}
+
+	now := time.Now()
+	subscriber.Status = "active"
+	subscriber.ConfirmToken = ""
+	subscriber.ConfirmedAt = &now
+	subscriber.UnsubscribedAt = nil
+	subscriber.BounceCount = 0
+
+	if err := database.DB.Save(&subscriber).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to confirm subscription",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Subscription confirmed",
+		Data:    map[string]any{"email": subscriber.Email},
+	})
+}
+
+// GET /api/newsletter/unsubscribe?token= — halaman konfirmasi berhenti langganan (publik).
+// Tidak mengubah apa pun, supaya pemindai email dan prefetch link tidak ikut meng-unsubscribe.
+func UnsubscribeNewsletterPage(c *gin.Context) {
+
+	token := c.Query("token")
+	var subscriber models.Subscriber
+	if token == "" || database.DB.Where("unsubscribe_token = ?", token).First(&subscriber).Error != nil {
+		newsletterPage(c, http.StatusNotFound, "Link tidak valid", "<p>Link berhenti langganan ini tidak valid atau sudah kedaluwarsa.</p>")
+		return
+	}
+	if subscriber.Status == "unsubscribed" {
+		newsletterPage(c, http.StatusOK, "Sudah berhenti langganan", fmt.Sprintf("<p>%s sudah tidak menerima newsletter.</p>", html.EscapeString(subscriber.Email)))
+		return
+	}
+
+	newsletterPage(c, http.StatusOK, "Berhenti langganan?", fmt.Sprintf(
+		`<p>%s tidak akan menerima newsletter lagi.</p><form method="post" action="%s"><button type="submit">Ya, berhenti langganan</button></form>`,
+		html.EscapeString(subscriber.Email), html.EscapeString(newsletterUrl("unsubscribe", url.QueryEscape(token)))))
+}
+
+// POST /api/newsletter/unsubscribe?token= — berhenti langganan (publik).
+// Dipakai tombol di halaman konfirmasi dan one-click unsubscribe dari header List-Unsubscribe-Post.
+func UnsubscribeNewsletter(c *gin.Context) {
+
+	token := c.Query("token")
+	if token == "" {
+		token = c.PostForm("token")
+	}
+	subscriber, ok := findSubscriberByToken(c, token)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	subscriber.Status = "unsubscribed"
+	subscriber.UnsubscribedAt = &now
+
+	if err := database.DB.Save(&subscriber).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to unsubscribe",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	// Dari form di halaman konfirmasi: balas halaman juga, bukan JSON
+	if strings.Contains(c.GetHeader("Accept"), "text/html") {
+		newsletterPage(c, http.StatusOK, "Berhenti langganan", fmt.Sprintf("<p>%s sudah berhenti berlangganan newsletter.</p>", html.EscapeString(subscriber.Email)))
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "You have been unsubscribed",
+		Data:    map[string]any{"email": subscriber.Email},
+	})
+}
+
+// newsletterPage halaman HTML sederhana untuk link dari email (body sudah di-escape pemanggil)
+func newsletterPage(c *gin.Context, status int, title string, body string) {
+	page := fmt.Sprintf(`<!doctype html><html lang="id"><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><meta name="robots" content="noindex"><title>%s</title></head><body><h1>%s</h1>%s</body></html>`,
+		html.EscapeString(title), html.EscapeString(title), body)
+	c.Data(status, "text/html; charset=utf-8", []byte(page))
+}
+
+// GET /api/newsletter/preferences?token= — lihat preferensi tag (publik)
+func GetNewsletterPreferences(c *gin.Context) {
+
+	subscriber, ok := findSubscriberByToken(c, c.Query("token"))
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Newsletter Preferences",
+		Data: map[string]any{
+			"email":  subscriber.Email,
+			"status": subscriber.Status,
+			"tags":   subscriber.Tags,
+		},
+	})
+}
+
+// PUT /api/newsletter/preferences — ubah preferensi tag, tag_ids kosong = semua (publik)
+func UpdateNewsletterPreferences(c *gin.Context) {
+
+	var req structs.NewsletterPreferenceRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	subscriber, ok := findSubscriberByToken(c, req.Token)
+	if !ok {
+		return
+	}
+
+	var tags []models.Tag
+	if len(req.TagIds) > 0 {
+		database.DB.Where("id IN ?", req.TagIds).Find(&tags)
+	}
+	database.DB.Model(&subscriber).Association("Tags").Replace(tags)
+	database.DB.Preload("Tags").First(&subscriber, subscriber.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Preferences saved successfully",
+		Data: map[string]any{
+			"email":  subscriber.Email,
+			"status": subscriber.Status,
+			"tags":   subscriber.Tags,
+		},
+	})
+}
+
+// findSubscriberByToken cari subscriber dari unsubscribe token, kirim 404 kalau tidak ada
+func findSubscriberByToken(c *gin.Context, token string) (models.Subscriber, bool) {
+
+	var subscriber models.Subscriber
+	if token == "" || database.DB.Preload("Tags").Where("unsubscribe_token = ?", token).First(&subscriber).Error != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Subscriber not found",
+			Errors:  map[string]string{"token": "invalid token"},
+		})
+		return subscriber, false
+	}
+	return subscriber, true
+}
+
+// POST /api/newsletter/bounces — webhook bounce dari mail provider (header X-Webhook-Secret)
+func NewsletterBounce(c *gin.Context) {
+
+	secret := os.Getenv("NEWSLETTER_WEBHOOK_SECRET")
+	if secret == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Webhook-Secret")), []byte(secret)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": "Invalid webhook secret",
+		})
+		return
+	}
+
+	var req structs.NewsletterBounceRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	var subscriber models.Subscriber
+	if err := database.DB.Where("email = ?", strings.ToLower(req.Email)).First(&subscriber).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Subscriber not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	// Tandai email terakhir ke alamat ini sebagai bounced
+	var lastSend models.NewsletterSend
+	if database.DB.Where("subscriber_id = ?", subscriber.Id).Order("id desc").First(&lastSend).Error == nil {
+		lastSend.Status = "bounced"
+		lastSend.Error = req.Reason
+		database.DB.Save(&lastSend)
+	}
+
+	recordBounce(&subscriber, req.Hard)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Bounce recorded",
+		Data:    map[string]any{"email": subscriber.Email, "status": subscriber.Status},
+	})
+}
+
+// recordBounce naikkan bounce count, nonaktifkan kalau hard bounce atau sudah terlalu sering
+func recordBounce(subscriber *models.Subscriber, hard bool) {
+	subscriber.BounceCount++
+	if hard || subscriber.BounceCount >= maxSoftBounces {
+		subscriber.Status = "bounced"
+	}
+	database.DB.Select("bounce_count", "status").Save(subscriber)
+}
+
+// GET /api/newsletter/subscribers — list subscriber dengan pagination (auth)
+func FindSubscribers(c *gin.Context) {
+
+	var subscribers []models.Subscriber
+	var total int64
+
+	status := c.Query("status")
+	search := c.Query("search")
+	pg := helpers.GetPagination(c)
+
+	query := database.DB.Model(&models.Subscriber{}).Preload("Tags")
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if search != "" {
+		query = query.Where("email LIKE ? OR name LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	query.Count(&total)
+	query.Order("created_at desc").Limit(pg.Limit).Offset(pg.Offset).Find(&subscribers)
+
+	totalPages := int(total) / pg.Limit
+	if int(total)%pg.Limit != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, structs.PaginatedResponse{
+		Success: true,
+		Message: "List Data Subscribers",
+		Data:    subscribers,
+		Meta: structs.PaginationMeta{
+			Page:       pg.Page,
+			Limit:      pg.Limit,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	})
+}
+
+// DELETE /api/newsletter/subscribers/:id — hapus subscriber (auth)
+func DeleteSubscriber(c *gin.Context) {
+
+	id := c.Param("id")
+	var subscriber models.Subscriber
+
+	if err := database.DB.First(&subscriber, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Subscriber not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	database.DB.Model(&subscriber).Association("Tags").Clear()
+
+	if err := database.DB.Delete(&subscriber).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete subscriber",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Subscriber deleted successfully",
+		Data:    nil,
+	})
+}
+
+// GET /api/newsletter/stats — statistik subscriber & pengiriman (auth)
+func NewsletterStats(c *gin.Context) {
+
+	type StatusCount struct {
+		Status string `json:"status"`
+		Count  int64  `json:"count"`
+	}
+
+	var subscriberCounts []StatusCount
+	database.DB.Model(&models.Subscriber{}).
+		Select("status, COUNT(*) as count").
+		Group("status").
+		Scan(&subscriberCounts)
+
+	subscribers := map[string]int64{
+		"total": 0, "pending": 0, "active": 0, "unsubscribed": 0, "bounced": 0,
+	}
+	for _, r := range subscriberCounts {
+		subscribers[r.Status] = r.Count
+		subscribers["total"] += r.Count
+	}
+
+	var sendCounts []StatusCount
+	database.DB.Model(&models.NewsletterSend{}).
+		Select("status, COUNT(*) as count").
+		Group("status").
+		Scan(&sendCounts)
+
+	sends := map[string]int64{"total": 0, "sent": 0, "failed": 0, "bounced": 0}
+	for _, r := range sendCounts {
+		sends[r.Status] = r.Count
+		sends["total"] += r.Count
+	}
+
+	bounceRate := 0.0
+	if sends["total"] > 0 {
+		bounceRate = float64(sends["bounced"]) / float64(sends["total"])
+	}
+
+	// Subscriber baru 30 hari terakhir (per hari)
+	type DailyCount struct {
+		Date  string `json:"date"`
+		Count int    `json:"count"`
+	}
+	var daily []DailyCount
+	database.DB.Raw(`
+		SELECT DATE(created_at) as date, COUNT(*) as count
+		FROM subscribers
+		WHERE created_at >= DATE_SUB(NOW(), INTERVAL 30 DAY)
+		GROUP BY DATE(created_at)
+		ORDER BY date ASC
+	`).Scan(&daily)
+
+	var digests []models.NewsletterDigest
+	database.DB.Order("started_at desc").Limit(10).Find(&digests)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Newsletter Stats",
+		Data: map[string]any{
+			"subscribers":     subscribers,
+			"sends":           sends,
+			"bounce_rate":     bounceRate,
+			"daily":           daily,
+			"recent_digests":  digests,
+			"next_digest_due": nextDigestDue(),
+		},
+	})
+}
+
+// POST /api/newsletter/digest/send — kirim digest sekarang tanpa menunggu jadwal (auth)
+func SendDigestNow(c *gin.Context) {
+
+	digest, err := sendDigest("manual")
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, errNoNewBlogs) || errors.Is(err, errDigestBusy) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, structs.ErrorResponse{
+			Success: false,
+			Message: "Digest not sent",
+			Errors:  map[string]string{"digest": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Digest sent",
+		Data:    digest,
+	})
+}
+
+// ==================== SCHEDULER ====================
+
+// digestInterval jarak antar digest dari env NEWSLETTER_DIGEST_DAYS (default 7 hari)
+func digestInterval() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("NEWSLETTER_DIGEST_DAYS"))
+	if err != nil || days < 1 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// digestPeriodLabel keterangan periode digest untuk isi email, contoh: 7 hari → "minggu ini"
+func digestPeriodLabel(interval time.Duration) string {
+	days := int(interval / (24 * time.Hour))
+	switch {
+	case days <= 1:
+		return "hari ini"
+	case days == 7:
+		return "minggu ini"
+	case days%7 == 0:
+		return fmt.Sprintf("%d minggu terakhir", days/7)
+	case days >= 28 && days <= 31:
+		return "bulan ini"
+	default:
+		return fmt.Sprintf("%d hari terakhir", days)
+	}
+}
+
+// lastDigestTime waktu digest terakhir, zero kalau belum pernah
+func lastDigestTime() time.Time {
+	var last models.NewsletterDigest
+	if database.DB.Order("started_at desc").First(&last).Error != nil {
+		return time.Time{}
+	}
+	return last.StartedAt
+}
+
+// nextDigestDue kapan digest terjadwal berikutnya
+func nextDigestDue() time.Time {
+	last := lastDigestTime()
+	if last.IsZero() {
+		return time.Now()
+	}
+	return last.Add(digestInterval())
+}
+
+// StartNewsletterScheduler cek tiap jam apakah digest sudah waktunya dikirim.
+// Hanya instance pemegang lock leader yang mengirim, supaya digest tidak terkirim dobel.
+func StartNewsletterScheduler() {
+	if os.Getenv("NEWSLETTER_DIGEST_DISABLED") == "true" {
+		return
+	}
+
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s-%d", host, os.Getpid())
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if !acquireSchedulerLock(newsletterSchedulerLockName, owner, newsletterSchedulerLease) {
+				continue
+			}
+			if time.Now().Before(nextDigestDue()) {
+				continue
+			}
+			if _, err := sendDigest("schedule"); err != nil && !errors.Is(err, errNoNewBlogs) {
+				log.Printf("[DIGEST ERROR] %v", err)
+			}
+		}
+	}()
+}
+
+// sendDigest kirim blog yang baru dipublish sejak digest terakhir ke semua subscriber aktif
+func sendDigest(trigger string) (*models.NewsletterDigest, error) {
+
+	if !digestMutex.TryLock() {
+		return nil, errDigestBusy
+	}
+	defer digestMutex.Unlock()
+
+	since := lastDigestTime()
+	if since.IsZero() {
+		since = time.Now().Add(-digestInterval())
+	}
+
+	var blogs []models.Blog
+	database.DB.Preload("Tags").
+		Where("status = ? AND published_at > ?", "published", since).
+		Order("published_at asc").
+		Find(&blogs)
+
+	if len(blogs) == 0 {
+		return nil, errNoNewBlogs
+	}
+
+	var subscribers []models.Subscriber
+	database.DB.Preload("Tags").Where("status = ?", "active").Find(&subscribers)
+
+	blogIds := make([]string, 0, len(blogs))
+	for _, b := range blogs {
+		blogIds = append(blogIds, strconv.FormatUint(uint64(b.Id), 10))
+	}
+
+	digest := models.NewsletterDigest{
+		BlogIds:   strings.Join(blogIds, ","),
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+	if err := database.DB.Create(&digest).Error; err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DIGEST] start: %d blogs, %d subscribers", len(blogs), len(subscribers))
+
+	mailer := helpers.GetMailer()
+	for i := range subscribers {
+		subscriber := &subscribers[i]
+
+		selected := filterBlogsForSubscriber(blogs, subscriber.Tags)
+		if len(selected) == 0 {
+			continue
+		}
+		digest.Recipients++
+
+		err := mailer.Send(buildDigestEmail(*subscriber, selected))
+		send := models.NewsletterSend{
+			DigestId:     &digest.Id,
+			SubscriberId: subscriber.Id,
+			Email:        subscriber.Email,
+			Kind:         "digest",
+			Status:       "sent",
+		}
+
+		if err != nil {
+			send.Status = "failed"
+			send.Error = err.Error()
+			digest.FailedCount++
+			if helpers.IsPermanentMailError(err) {
+				send.Status = "bounced"
+				recordBounce(subscriber, false)
+			}
+		} else {
+			digest.SentCount++
+			now := time.Now()
+			subscriber.LastSentAt = &now
+			database.DB.Select("last_sent_at").Save(subscriber)
+		}
+		database.DB.Create(&send)
+	}
+
+	finished := time.Now()
+	digest.FinishedAt = &finished
+	database.DB.Save(&digest)
+
+	log.Printf("[DIGEST] done: sent %d, failed %d", digest.SentCount, digest.FailedCount)
+
+	return &digest, nil
+}
+
+// filterBlogsForSubscriber ambil blog yang cocok dengan preferensi tag; tanpa preferensi = semua
+func filterBlogsForSubscriber(blogs []models.Blog, preferred []models.Tag) []models.Blog {
+	if len(preferred) == 0 {
+		return blogs
+	}
+
+	wanted := map[uint]bool{}
+	for _, t := range preferred {
+		wanted[t.Id] = true
+	}
+
+	var result []models.Blog
+	for _, b := range blogs {
+		for _, t := range b.Tags {
+			if wanted[t.Id] {
+				result = append(result, b)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// ==================== EMAIL ====================
+
+// newsletterUrl link API untuk token subscriber
+func newsletterUrl(path string, token string) string {
+	return helpers.GetBaseUrl() + "/api/newsletter/" + path + "?token=" + token
+}
+
+// frontendUrl link publik ke halaman frontend
+func frontendUrl(path string) string {
+	base := os.Getenv("FRONTEND_URL")
+	if base == "" {
+		base = helpers.GetBaseUrl()
+	}
+	return strings.TrimSuffix(base, "/") + path
+}
+
+// frontendBlogUrl link publik ke halaman blog
+func frontendBlogUrl(slug string) string {
+	return frontendUrl("/blog/" + slug)
+}
+
+// unsubscribeHeaders header standar supaya mail client menampilkan tombol unsubscribe
+func unsubscribeHeaders(subscriber models.Subscriber) map[string]string {
+	return map[string]string{
+		"List-Unsubscribe":      "<" + newsletterUrl("unsubscribe", subscriber.UnsubscribeToken) + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+// sendConfirmationEmail kirim link double opt-in
+func sendConfirmationEmail(subscriber models.Subscriber) {
+
+	confirmUrl := newsletterUrl("confirm", subscriber.ConfirmToken)
+
+	msg := helpers.MailMessage{
+		To:      subscriber.Email,
+		Subject: "Konfirmasi langganan newsletter",
+		Text: fmt.Sprintf("Halo%s,\n\nKlik link berikut untuk mengonfirmasi langganan newsletter:\n%s\n\nAbaikan email ini kalau kamu tidak merasa mendaftar.\n",
+			greetingName(subscriber.Name), confirmUrl),
+		Html: fmt.Sprintf(`<p>Halo%s,</p><p>Klik tombol di bawah untuk mengonfirmasi langganan newsletter:</p><p><a href="%s">Konfirmasi langganan</a></p><p>Abaikan email ini kalau kamu tidak merasa mendaftar.</p>`,
+			html.EscapeString(greetingName(subscriber.Name)), html.EscapeString(confirmUrl)),
+	}
+
+	send := models.NewsletterSend{
+		SubscriberId: subscriber.Id,
+		Email:        subscriber.Email,
+		Kind:         "confirm",
+		Status:       "sent",
+	}
+
+	if err := helpers.GetMailer().Send(msg); err != nil {
+		log.Printf("[NEWSLETTER ERROR] confirm %s: %v", subscriber.Email, err)
+		send.Status = "failed"
+		send.Error = err.Error()
+		if helpers.IsPermanentMailError(err) {
+			send.Status = "bounced"
+		}
+	}
+
+	database.DB.Create(&send)
+}
+
+// buildDigestEmail susun email digest untuk satu subscriber
+func buildDigestEmail(subscriber models.Subscriber, blogs []models.Blog) helpers.MailMessage {
+
+	var text strings.Builder
+	var body strings.Builder
+
+	period := digestPeriodLabel(digestInterval())
+	fmt.Fprintf(&text, "Halo%s,\n\nArtikel terbaru %s:\n\n", greetingName(subscriber.Name), period)
+	fmt.Fprintf(&body, "<p>Halo%s,</p><p>Artikel terbaru %s:</p><ul>", html.EscapeString(greetingName(subscriber.Name)), period)
+
+	for _, b := range blogs {
+		link := frontendBlogUrl(b.Slug)
+		fmt.Fprintf(&text, "- %s\n  %s\n", b.Title, link)
+		if b.Description != "" {
+			fmt.Fprintf(&text, "  %s\n", b.Description)
+		}
+		text.WriteString("\n")
+
+		fmt.Fprintf(&body, `<li><a href="%s"><strong>%s</strong></a>`, html.EscapeString(link), html.EscapeString(b.Title))
+		if b.Description != "" {
+			fmt.Fprintf(&body, "<br>%s", html.EscapeString(b.Description))
+		}
+		body.WriteString("</li>")
+	}
+
+	prefsUrl := newsletterUrl("preferences", subscriber.UnsubscribeToken)
+	unsubUrl := newsletterUrl("unsubscribe", subscriber.UnsubscribeToken)
+
+	fmt.Fprintf(&text, "Atur topik: %s\nBerhenti langganan: %s\n", prefsUrl, unsubUrl)
+	fmt.Fprintf(&body, `</ul><hr><p><small><a href="%s">Atur topik</a> · <a href="%s">Berhenti langganan</a></small></p>`,
+		html.EscapeString(prefsUrl), html.EscapeString(unsubUrl))
+
+	subject := fmt.Sprintf("%d artikel baru untukmu", len(blogs))
+	if len(blogs) == 1 {
+		subject = "Artikel baru: " + blogs[0].Title
+	}
+
+	return helpers.MailMessage{
+		To:      subscriber.Email,
+		Subject: subject,
+		Text:    text.String(),
+		Html:    body.String(),
+		Headers: unsubscribeHeaders(subscriber),
+	}
+}
+
+// greetingName " Nama" atau "" kalau nama kosong
+func greetingName(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " " + name
+}
@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestPeriodLabel(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		interval time.Duration
+		want     string
+	}{
+		{day, "hari ini"},
+		{3 * day, "3 hari terakhir"},
+		{7 * day, "minggu ini"},
+		{14 * day, "2 minggu terakhir"},
+		{30 * day, "bulan ini"},
+		{45 * day, "45 hari terakhir"},
+	}
+
+	for _, tt := range tests {
+		if got := digestPeriodLabel(tt.interval); got != tt.want {
+			t.Errorf("digestPeriodLabel(%v) = %q, want %q", tt.interval, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ogBranding ambil site name & warna card dari settings
+// Key yang dipakai: site_name, og_background, og_accent
+func ogBranding() helpers.OgCard {
+
+	var settings []models.Setting
+	database.DB.Where("`key` IN ?", []string{"site_name", "og_background", "og_accent"}).Find(&settings)
+
+	card := helpers.OgCard{SiteName: "Arlchoose"}
+	for _, s := range settings {
+		switch s.Key {
+		case "site_name":
+			if s.Value != "" {
+				card.SiteName = s.Value
+			}
+		case "og_background":
+			card.Background = s.Value
+		case "og_accent":
+			card.Accent = s.Value
+		}
+	}
+	return card
+}
+
+// parseOgFile pecah "my-slug.png" jadi slug dan format, return false kalau ekstensi tidak didukung
+func parseOgFile(file string) (string, string, bool) {
+	ext := strings.ToLower(filepath.Ext(file))
+	switch ext {
+	case ".png", ".jpg", ".jpeg":
+		return strings.TrimSuffix(file, filepath.Ext(file)), strings.TrimPrefix(ext, "."), true
+	}
+	return "", "", false
+}
+
+// serveOgImage render (atau ambil dari cache) lalu kirim file gambar
+func serveOgImage(c *gin.Context, card helpers.OgCard, format string) {
+
+	path, err := helpers.GetOgImage(card, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to render og image",
+			Errors:  map[string]string{"og": err.Error()},
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.File(path)
+}
+
+// GET /api/og/blogs/:file — share image blog, contoh: /api/og/blogs/my-post.png (publik)
+func BlogOgImage(c *gin.Context) {
+
+	slug, format, ok := parseOgFile(c.Param("file"))
+	if !ok {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Unsupported image format",
+			Errors:  map[string]string{"file": "use .png or .jpg"},
+		})
+		return
+	}
+
+	var blog models.Blog
+	if err := database.DB.Preload("Tags").Preload("User").
+		Where("slug = ? AND status = ?", slug, "published").First(&blog).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	card := ogBranding()
+	card.Kicker = "Blog"
+	card.Title = blog.Title
+
+	for _, tag := range blog.Tags {
+		card.Tags = append(card.Tags, tag.Slug)
+	}
+
+	if blog.Author == "aibys" {
+		card.Author = "Aibys"
+	} else if blog.User != nil {
+		card.Author = blog.User.Name
+	}
+
+	serveOgImage(c, card, format)
+}
+
+// GET /api/og/projects/:file — share image project (publik)
+func ProjectOgImage(c *gin.Context) {
+
+	slug, format, ok := parseOgFile(c.Param("file"))
+	if !ok {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Unsupported image format",
+			Errors:  map[string]string{"file": "use .png or .jpg"},
+		})
+		return
+	}
+
+	var project models.Project
+	if err := database.DB.Preload("TechStacks").Where("slug = ?", slug).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Project not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	card := ogBranding()
+	card.Kicker = "Project"
+	card.Title = project.Title
+	card.Subtitle = project.Platform
+
+	for _, tech := range project.TechStacks {
+		card.Tags = append(card.Tags, tech.Name)
+	}
+
+	var profile models.Profile
+	if database.DB.First(&profile).Error == nil {
+		card.Author = profile.Name
+	}
+
+	serveOgImage(c, card, format)
+}
+
+// GET /api/og/profile.png & /api/og/profile.jpg — share image profile (publik)
+func ProfileOgImage(c *gin.Context) {
+
+	_, format, _ := parseOgFile(filepath.Base(c.Request.URL.Path))
+
+	var profile models.Profile
+	if err := database.DB.First(&profile).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Profile not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	card := ogBranding()
+	card.Kicker = "Portfolio"
+	card.Title = profile.Name
+	card.Subtitle = profile.Tagline
+	card.Author = profile.Location
+
+	serveOgImage(c, card, format)
+}
@@ -32,6 +32,27 @@ func FindProjectBySlug(c *gin.Context) {
 	var project models.Project
 
 	if err := database.DB.Preload("TechStacks").Preload("Images").Where("slug = ?", slug).First(&project).Error; err != nil {
+
+		// Slug lama → arahkan ke slug yang sekarang
+		if projectId, ok := helpers.FindSlugRedirect("project", slug); ok {
+			var moved models.Project
+			if database.DB.Select("id", "slug").First(&moved, projectId).Error == nil {
+				location := "/api/projects/" + moved.Slug
+				c.Header("Location", location)
+				c.JSON(http.StatusMovedPermanently, structs.SuccessResponse{
+					Success: true,
+					Message: "Project moved permanently",
+					Data: structs.SlugRedirectResponse{
+						EntityType: "project",
+						OldSlug:    slug,
+						Slug:       moved.Slug,
+						Location:   location,
+					},
+				})
+				return
+			}
+		}
+
 		c.JSON(http.StatusNotFound, structs.ErrorResponse{
 			Success: false,
 			Message: "Project not found",
@@ -63,7 +84,7 @@ func CreateProject(c *gin.Context) {
 
 	project := models.Project{
 		Title:       req.Title,
-		Slug:        helpers.GenerateSlug(req.Title),
+		Slug:        helpers.UniqueSlug("project", req.Title, 0),
 		Description: req.Description,
 		Platform:    req.Platform,
 		Url:         req.Url,
@@ -107,6 +128,7 @@ func CreateProject(c *gin.Context) {
 	database.DB.Preload("TechStacks").Preload("Images").First(&project, project.Id)
 
 	go helpers.RevalidateFrontend("project", project.Slug)
+	helpers.QueueEmbedding("project", project.Id)
 
 	c.JSON(http.StatusCreated, structs.SuccessResponse{
 		Success: true,
@@ -141,8 +163,13 @@ func UpdateProject(c *gin.Context) {
 		return
 	}
 
+	oldSlug := project.Slug
+
+	// Slug hanya dibuat ulang kalau judul berubah, slug lama dicatat untuk redirect
+	if req.Title != project.Title {
+		project.Slug = helpers.UniqueSlug("project", req.Title, project.Id)
+	}
 	project.Title = req.Title
-	project.Slug = helpers.GenerateSlug(req.Title)
 	project.Description = req.Description
 	project.Platform = req.Platform
 	project.Url = req.Url
@@ -156,6 +183,8 @@ func UpdateProject(c *gin.Context) {
 		return
 	}
 
+	helpers.RecordSlugRedirect("project", project.Id, oldSlug, project.Slug)
+
 	// Reset tech stacks lama lalu simpan yang baru
 	database.DB.Where("project_id = ?", project.Id).Delete(&models.ProjectTechStack{})
 	for _, tech := range req.TechStacks {
@@ -168,6 +197,7 @@ func UpdateProject(c *gin.Context) {
 	database.DB.Preload("TechStacks").Preload("Images").First(&project, project.Id)
 
 	go helpers.RevalidateFrontend("project", project.Slug)
+	helpers.QueueEmbedding("project", project.Id)
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
 		Success: true,
@@ -205,6 +235,9 @@ func DeleteProject(c *gin.Context) {
 		return
 	}
 
+	helpers.DeleteSlugRedirects("project", project.Id)
+	helpers.RemoveEmbedding("project", project.Id)
+
 	go helpers.RevalidateFrontend("project", "")
 
 	c.JSON(http.StatusOK, structs.SuccessResponse{
@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GET /api/prompts — list semua versi prompt template, filter ?key= (auth)
+func FindPromptTemplates(c *gin.Context) {
+
+	// Pastikan versi default sudah ada supaya list tidak kosong di awal
+	for _, key := range helpers.PromptKeys {
+		helpers.ActivePromptTemplate(key)
+	}
+
+	var templates []models.PromptTemplate
+
+	query := database.DB.Model(&models.PromptTemplate{})
+	if key := c.Query("key"); key != "" {
+		query = query.Where("`key` = ?", key)
+	}
+	query.Order("`key` asc, version desc").Find(&templates)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "List Data Prompt Templates",
+		Data:    templates,
+	})
+}
+
+// GET /api/prompts/:id — detail satu versi prompt template (auth)
+func FindPromptTemplateById(c *gin.Context) {
+
+	var tmpl models.PromptTemplate
+
+	if err := database.DB.First(&tmpl, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Prompt template not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Prompt Template Found",
+		Data:    tmpl,
+	})
+}
+
+// POST /api/prompts — simpan versi baru prompt template, opsional langsung aktif (auth)
+func CreatePromptTemplate(c *gin.Context) {
+
+	var req structs.PromptTemplateCreateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	// Template harus bisa di-render dengan contoh data sebelum disimpan
+	if _, err := helpers.ExecutePrompt(req.Key, req.Body, helpers.SamplePromptData()); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"body": err.Error()},
+		})
+		return
+	}
+
+	// Seed versi default dulu supaya nomor versi baru tidak bentrok dengan v1
+	helpers.ActivePromptTemplate(req.Key)
+
+	tmpl := models.PromptTemplate{
+		Key:  req.Key,
+		Body: req.Body,
+		Note: req.Note,
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		tx.Model(&models.PromptTemplate{}).Where("`key` = ?", req.Key).Select("COALESCE(MAX(version), 0)").Scan(&latest)
+		tmpl.Version = latest + 1
+
+		if err := tx.Create(&tmpl).Error; err != nil {
+			return err
+		}
+		if req.Activate {
+			return activatePromptTemplate(tx, &tmpl)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to create prompt template",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, structs.SuccessResponse{
+		Success: true,
+		Message: "Prompt template created successfully",
+		Data:    tmpl,
+	})
+}
+
+// PUT /api/prompts/:id/activate — jadikan versi ini yang dipakai generator (auth)
+func ActivatePromptTemplate(c *gin.Context) {
+
+	var tmpl models.PromptTemplate
+
+	if err := database.DB.First(&tmpl, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Prompt template not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		return activatePromptTemplate(tx, &tmpl)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to activate prompt template",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Prompt template activated",
+		Data:    tmpl,
+	})
+}
+
+// POST /api/prompts/preview — render template dengan variabel tanpa memanggil LLM (auth)
+func PreviewPromptTemplate(c *gin.Context) {
+
+	var req structs.PromptPreviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	var tmpl models.PromptTemplate
+	switch {
+	case req.Body != "":
+		if req.Key == "" {
+			c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+				Success: false,
+				Message: "Validation Errors",
+				Errors:  map[string]string{"key": "key is required when previewing a body"},
+			})
+			return
+		}
+		tmpl = models.PromptTemplate{Key: req.Key, Body: req.Body}
+	case req.TemplateId != 0:
+		if err := database.DB.First(&tmpl, req.TemplateId).Error; err != nil {
+			c.JSON(http.StatusNotFound, structs.ErrorResponse{
+				Success: false,
+				Message: "Prompt template not found",
+				Errors:  helpers.TranslateErrorMessage(err),
+			})
+			return
+		}
+	case req.Key != "":
+		active, err := helpers.ActivePromptTemplate(req.Key)
+		if err != nil {
+			c.JSON(http.StatusNotFound, structs.ErrorResponse{
+				Success: false,
+				Message: "Prompt template not found",
+				Errors:  map[string]string{"key": err.Error()},
+			})
+			return
+		}
+		tmpl = active
+	default:
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"key": "key, template_id or body is required"},
+		})
+		return
+	}
+
+	data := promptPreviewData(req.Variables)
+	rendered, err := helpers.ExecutePrompt(tmpl.Key, tmpl.Body, data)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to render prompt template",
+			Errors:  map[string]string{"body": err.Error()},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Prompt Rendered",
+		Data: map[string]any{
+			"template_id": tmpl.Id,
+			"key":         tmpl.Key,
+			"version":     tmpl.Version,
+			"variables":   data,
+			"prompt":      rendered,
+		},
+	})
+}
+
+// activatePromptTemplate nonaktifkan versi lain dengan key yang sama lalu aktifkan tmpl
+func activatePromptTemplate(tx *gorm.DB, tmpl *models.PromptTemplate) error {
+	if err := tx.Model(&models.PromptTemplate{}).
+		Where("`key` = ? AND id <> ?", tmpl.Key, tmpl.Id).
+		Update("is_active", false).Error; err != nil {
+		return err
+	}
+	tmpl.IsActive = true
+	return tx.Model(tmpl).Update("is_active", true).Error
+}
+
+// promptPreviewData contoh data, ditimpa variabel yang dikirim
+func promptPreviewData(vars *structs.PromptVariables) helpers.PromptData {
+	data := helpers.SamplePromptData()
+	if vars == nil {
+		return data
+	}
+
+	if vars.Title != "" {
+		data.Title = vars.Title
+	}
+	if vars.Keyword != "" {
+		data.Keyword = vars.Keyword
+	}
+	if vars.Total > 0 {
+		data.Total = vars.Total
+	}
+	if vars.Sources != nil {
+		var sources []helpers.ResearchSource
+		for _, src := range vars.Sources {
+			sources = append(sources, helpers.ResearchSource{Title: src.Title, Url: src.Url, Content: src.Content})
+		}
+		data.SetSources(sources)
+	} else if vars.References != nil {
+		var sources []helpers.ResearchSource
+		for _, ref := range vars.References {
+			sources = append(sources, helpers.ResearchSource{Content: ref})
+		}
+		data.SetSources(sources)
+	}
+	if vars.Date != "" {
+		data.Date = vars.Date
+	}
+	if vars.Comment != "" {
+		data.Comment = vars.Comment
+	}
+	if vars.Tone != "" {
+		data.Tone = vars.Tone
+	}
+	if vars.Description != "" {
+		data.Description = vars.Description
+	}
+	if vars.Content != "" {
+		data.Content = vars.Content
+	}
+	return data
+}
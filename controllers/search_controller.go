@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SemanticSearchResult satu hasil pencarian beserta data ringkas kontennya
+type SemanticSearchResult struct {
+	Type        string  `json:"type"`
+	Id          uint    `json:"id"`
+	Score       float64 `json:"score"`
+	Title       string  `json:"title"`
+	Slug        string  `json:"slug,omitempty"`
+	Url         string  `json:"url,omitempty"`
+	Description string  `json:"description"`
+	Image       string  `json:"image,omitempty"`
+}
+
+// GET /api/search/semantic?q=&type=blog,project,bookmark&limit=10 — pencarian berdasarkan makna, bukan kata kunci (publik)
+func SemanticSearch(c *gin.Context) {
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"q": "is required"},
+		})
+		return
+	}
+	if len([]rune(q)) > 500 {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"q": "must be at most 500 characters"},
+		})
+		return
+	}
+
+	var types []string
+	if t := c.Query("type"); t != "" {
+		for _, name := range strings.Split(t, ",") {
+			name = strings.TrimSpace(name)
+			if !slices.Contains(helpers.SemanticEntityTypes, name) {
+				c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+					Success: false,
+					Message: "Validation Errors",
+					Errors:  map[string]string{"type": "must be one of " + strings.Join(helpers.SemanticEntityTypes, ", ")},
+				})
+				return
+			}
+			types = append(types, name)
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+	limit = min(limit, 50)
+
+	hits, err := helpers.SemanticSearch(q, types, helpers.SemanticMinScore())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, helpers.ErrSemanticDisabled) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, structs.ErrorResponse{
+			Success: false,
+			Message: "Semantic search failed",
+			Errors:  map[string]string{"search": err.Error()},
+		})
+		return
+	}
+
+	results := semanticSearchResults(hits, limit)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Semantic Search Results",
+		Data: map[string]any{
+			"query":   q,
+			"results": results,
+		},
+	})
+}
+
+// semanticSearchResults ambil data konten untuk hasil teratas. Blog yang belum publish dilewati,
+// jadi hasil diambil bertahap sampai limit terpenuhi.
+func semanticSearchResults(hits []helpers.SemanticHit, limit int) []SemanticSearchResult {
+
+	results := []SemanticSearchResult{}
+	for start := 0; start < len(hits) && len(results) < limit; start += limit {
+		batch := hits[start:min(start+limit, len(hits))]
+
+		ids := map[string][]uint{}
+		for _, hit := range batch {
+			ids[hit.EntityType] = append(ids[hit.EntityType], hit.EntityId)
+		}
+
+		found := map[string]SemanticSearchResult{}
+		key := func(entityType string, id uint) string { return entityType + ":" + strconv.FormatUint(uint64(id), 10) }
+
+		if len(ids["blog"]) > 0 {
+			var blogs []models.Blog
+			database.DB.Select("id", "title", "slug", "description", "cover_image").
+				Where("id IN ? AND status = ?", ids["blog"], "published").Find(&blogs)
+			for _, b := range blogs {
+				found[key("blog", b.Id)] = SemanticSearchResult{Type: "blog", Id: b.Id, Title: b.Title, Slug: b.Slug, Description: b.Description, Image: b.CoverImage}
+			}
+		}
+		if len(ids["project"]) > 0 {
+			var projects []models.Project
+			database.DB.Preload("Images").Where("id IN ?", ids["project"]).Find(&projects)
+			for _, p := range projects {
+				result := SemanticSearchResult{Type: "project", Id: p.Id, Title: p.Title, Slug: p.Slug, Url: p.Url, Description: p.Description}
+				if len(p.Images) > 0 {
+					result.Image = p.Images[0].ImageUrl
+				}
+				found[key("project", p.Id)] = result
+			}
+		}
+		if len(ids["bookmark"]) > 0 {
+			var bookmarks []models.Bookmark
+			database.DB.Where("id IN ?", ids["bookmark"]).Find(&bookmarks)
+			for _, b := range bookmarks {
+				found[key("bookmark", b.Id)] = SemanticSearchResult{Type: "bookmark", Id: b.Id, Title: b.Title, Url: b.Url, Description: b.Description}
+			}
+		}
+
+		for _, hit := range batch {
+			if result, ok := found[key(hit.EntityType, hit.EntityId)]; ok && len(results) < limit {
+				result.Score = hit.Score
+				results = append(results, result)
+			}
+		}
+	}
+	return results
+}
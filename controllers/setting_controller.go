@@ -6,6 +6,7 @@ import (
 	"arlchoose/backend-api/models"
 	"arlchoose/backend-api/structs"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -66,6 +67,32 @@ func UpsertSettings(c *gin.Context) {
 		return
 	}
 
+	// Provider LLM dan search harus dikenal, supaya generate tidak gagal belakangan
+	for key, value := range req.Settings {
+		var err error
+		if strings.HasPrefix(key, "llm_provider") && value != "" {
+			_, err = helpers.GetLLMProvider(value)
+		} else if strings.HasPrefix(key, "search_") {
+			err = helpers.ValidateSearchSetting(key, value)
+		} else if strings.HasPrefix(key, "similarity_") {
+			err = helpers.ValidateSimilaritySetting(key, value)
+		} else if key == "duplicate_title_threshold" || key == "embedding_provider" {
+			err = helpers.ValidateTopicSetting(key, value)
+		} else if strings.HasPrefix(key, "quality_") {
+			err = helpers.ValidateQualitySetting(key, value)
+		} else if strings.HasPrefix(key, "tag_") {
+			err = helpers.ValidateTagSetting(key, value)
+		}
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+				Success: false,
+				Message: "Validation Errors",
+				Errors:  map[string]string{key: err.Error()},
+			})
+			return
+		}
+	}
+
 	// Loop tiap key-value, upsert ke DB
 	for key, value := range req.Settings {
 		var setting models.Setting
@@ -5,9 +5,11 @@ import (
 	"arlchoose/backend-api/helpers"
 	"arlchoose/backend-api/models"
 	"arlchoose/backend-api/structs"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // GET /api/tags — ambil semua tag dengan pagination & search (publik)
@@ -60,7 +62,7 @@ func FindTagById(c *gin.Context) {
 	var tag models.Tag
 
 	// Cari tag berdasarkan ID
-	if err := database.DB.First(&tag, id).Error; err != nil {
+	if err := database.DB.Preload("Aliases").First(&tag, id).Error; err != nil {
 		c.JSON(http.StatusNotFound, structs.ErrorResponse{
 			Success: false,
 			Message: "Tag not found",
@@ -87,7 +89,28 @@ func FindTagBySlug(c *gin.Context) {
 	var tag models.Tag
 
 	// Cari tag berdasarkan slug
-	if err := database.DB.Where("slug = ?", slug).First(&tag).Error; err != nil {
+	if err := database.DB.Preload("Aliases").Where("slug = ?", slug).First(&tag).Error; err != nil {
+
+		// Slug lama → arahkan ke slug yang sekarang
+		if tagId, ok := helpers.FindSlugRedirect("tag", slug); ok {
+			var moved models.Tag
+			if database.DB.Select("id", "slug").First(&moved, tagId).Error == nil {
+				location := "/api/tags/slug/" + moved.Slug
+				c.Header("Location", location)
+				c.JSON(http.StatusMovedPermanently, structs.SuccessResponse{
+					Success: true,
+					Message: "Tag moved permanently",
+					Data: structs.SlugRedirectResponse{
+						EntityType: "tag",
+						OldSlug:    slug,
+						Slug:       moved.Slug,
+						Location:   location,
+					},
+				})
+				return
+			}
+		}
+
 		c.JSON(http.StatusNotFound, structs.ErrorResponse{
 			Success: false,
 			Message: "Tag not found",
@@ -121,8 +144,7 @@ func CreateTag(c *gin.Context) {
 	}
 
 	// Cek apakah tag dengan nama yang sama sudah ada
-	var existing models.Tag
-	if err := database.DB.Where("slug = ?", helpers.GenerateSlug(req.Name)).First(&existing).Error; err == nil {
+	if _, exists := findConflictingTag(req.Name, 0); exists {
 		c.JSON(http.StatusConflict, structs.ErrorResponse{
 			Success: false,
 			Message: "Tag already exists",
@@ -187,10 +209,8 @@ func UpdateTag(c *gin.Context) {
 		return
 	}
 
-	// Cek apakah slug baru sudah dipakai tag lain
-	newSlug := helpers.GenerateSlug(req.Name)
-	var existing models.Tag
-	if err := database.DB.Where("slug = ? AND id != ?", newSlug, tag.Id).First(&existing).Error; err == nil {
+	// Cek apakah nama baru sudah dipakai tag lain
+	if _, exists := findConflictingTag(req.Name, tag.Id); exists {
 		c.JSON(http.StatusConflict, structs.ErrorResponse{
 			Success: false,
 			Message: "Tag already exists",
@@ -200,8 +220,12 @@ func UpdateTag(c *gin.Context) {
 	}
 
 	// Update data tag
+	// Slug hanya dibuat ulang kalau nama berubah
+	oldSlug := tag.Slug
+	if req.Name != tag.Name {
+		tag.Slug = helpers.GenerateSlug(req.Name)
+	}
 	tag.Name = req.Name
-	tag.Slug = newSlug
 
 	// Simpan perubahan ke database
 	if err := database.DB.Save(&tag).Error; err != nil {
@@ -213,6 +237,8 @@ func UpdateTag(c *gin.Context) {
 		return
 	}
 
+	helpers.RecordSlugRedirect("tag", tag.Id, oldSlug, tag.Slug)
+
 	// Kirimkan response sukses
 	c.JSON(http.StatusOK, structs.SuccessResponse{
 		Success: true,
@@ -236,10 +262,19 @@ func DeleteTag(c *gin.Context) {
 		return
 	}
 
-	// Hapus relasi blog_tags dulu sebelum hapus tag
-	database.DB.Model(&tag).Association("Blogs").Clear()
-
-	if err := database.DB.Delete(&tag).Error; err != nil {
+	// Relasi ke blog, subscriber, dan jadwal AI serta alias dihapus bersama tag-nya
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for table := range tagJoinTables {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE tag_id = ?", table), tag.Id).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("tag_id = ?", tag.Id).Delete(&models.TagAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
 			Success: false,
 			Message: "Failed to delete tag",
@@ -248,9 +283,24 @@ func DeleteTag(c *gin.Context) {
 		return
 	}
 
+	helpers.DeleteSlugRedirects("tag", tag.Id)
+
 	c.JSON(http.StatusOK, structs.SuccessResponse{
 		Success: true,
 		Message: "Tag deleted successfully",
 		Data:    nil,
 	})
 }
+
+// findConflictingTag cari tag lain dengan slug atau TagKey yang sama dengan nama baru.
+// Tag tidak diberi slug berakhiran angka: "Go" dan "go!" tetap dianggap tag yang sama.
+func findConflictingTag(name string, exceptId uint) (models.Tag, bool) {
+	var existing models.Tag
+	if database.DB.Where("slug = ? AND id != ?", helpers.GenerateSlug(name), exceptId).First(&existing).Error == nil {
+		return existing, true
+	}
+	if existing, ok := helpers.LoadTagVocabulary().Lookup(name); ok && existing.Id != exceptId {
+		return existing, true
+	}
+	return models.Tag{}, false
+}
@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Tabel relasi many2many ke tag dan kolom pemiliknya, ikut dipindah saat merge
+var tagJoinTables = map[string]string{
+	"blog_tags":        "blog_id",
+	"subscriber_tags":  "subscriber_id",
+	"ai_schedule_tags": "ai_schedule_id",
+}
+
+// POST /api/tags/:id/aliases — tambah alias (sinonim) tag, contoh "go-lang" untuk "golang" (auth)
+func CreateTagAlias(c *gin.Context) {
+
+	var tag models.Tag
+	if err := database.DB.First(&tag, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Tag not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	var req structs.TagAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	alias := helpers.GenerateSlug(req.Alias)
+	if alias == "" {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"alias": "must contain letters or numbers"},
+		})
+		return
+	}
+
+	// Alias tidak boleh sama dengan nama, slug, atau alias tag mana pun
+	if existing, ok := helpers.LoadTagVocabulary().Lookup(alias); ok {
+		c.JSON(http.StatusConflict, structs.ErrorResponse{
+			Success: false,
+			Message: "Alias already exists",
+			Errors:  map[string]string{"alias": fmt.Sprintf("already used by tag %q", existing.Name)},
+		})
+		return
+	}
+
+	tagAlias := models.TagAlias{TagId: tag.Id, Alias: alias}
+	if err := database.DB.Create(&tagAlias).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to create alias",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, structs.SuccessResponse{
+		Success: true,
+		Message: "Alias created successfully",
+		Data:    tagAlias,
+	})
+}
+
+// DELETE /api/tags/:id/aliases/:aliasId — hapus alias tag (auth)
+func DeleteTagAlias(c *gin.Context) {
+
+	var alias models.TagAlias
+	if err := database.DB.Where("tag_id = ?", c.Param("id")).First(&alias, c.Param("aliasId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Alias not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	if err := database.DB.Delete(&alias).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete alias",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Alias deleted successfully",
+		Data:    nil,
+	})
+}
+
+// POST /api/tags/:id/merge — gabungkan tag sumber ke tag ini: relasi blog/subscriber/jadwal dipindah,
+// nama tag sumber jadi alias, slug lama diarahkan ke tag ini, lalu tag sumber dihapus (auth)
+func MergeTags(c *gin.Context) {
+
+	var target models.Tag
+	if err := database.DB.First(&target, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Tag not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	var req structs.TagMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	if slices.Contains(req.SourceIds, target.Id) {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"source_ids": "cannot contain the target tag"},
+		})
+		return
+	}
+
+	ids := slices.Compact(slices.Sorted(slices.Values(req.SourceIds)))
+	var sources []models.Tag
+	database.DB.Where("id IN ?", ids).Find(&sources)
+	if len(sources) != len(ids) {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"source_ids": "some tags do not exist"},
+		})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Relasi yang sudah punya tag tujuan diabaikan (primary key join table), sisanya dihapus
+		for table, owner := range tagJoinTables {
+			if err := tx.Exec(fmt.Sprintf("INSERT IGNORE INTO %s (%s, tag_id) SELECT DISTINCT %s, ? FROM %s WHERE tag_id IN ?", table, owner, owner, table), target.Id, ids).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE tag_id IN ?", table), ids).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.TagAlias{}).Where("tag_id IN ?", ids).Update("tag_id", target.Id).Error; err != nil {
+			return err
+		}
+		targetKey := helpers.TagKey(target.Name)
+		for _, source := range sources {
+			alias := helpers.GenerateSlug(source.Name)
+			if alias == "" || helpers.TagKey(alias) == targetKey {
+				continue
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.TagAlias{TagId: target.Id, Alias: alias}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.SlugRedirect{}).
+			Where("entity_type = ? AND entity_id IN ?", "tag", ids).
+			Update("entity_id", target.Id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, ids).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to merge tags",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	// Link lama /tags/slug/<slug sumber> diarahkan ke tag tujuan
+	for _, source := range sources {
+		helpers.RecordSlugRedirect("tag", target.Id, source.Slug, target.Slug)
+	}
+
+	database.DB.Preload("Aliases").First(&target, target.Id)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Tags merged successfully",
+		Data: map[string]any{
+			"tag":    target,
+			"merged": sources,
+		},
+	})
+}
+
+// POST /api/tags/suggest — usulan tag dari kosakata yang ada untuk tulisan manual (auth).
+// Tidak membuat tag baru; usulan di luar kosakata dikembalikan di new_suggestions.
+func SuggestTags(c *gin.Context) {
+
+	var req structs.TagSuggestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	// Tanpa deskripsi, awal konten dipakai sebagai ringkasan
+	description := req.Description
+	if description == "" && req.Content != "" {
+		description, _ = helpers.TruncateText(helpers.HtmlToText(req.Content), 500)
+	}
+
+	matches, unmatched, err := suggestTags(helpers.LoadTagVocabulary(), req.Title, description, helpers.LLMUsageRef{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to suggest tags",
+			Errors:  map[string]string{"llm": err.Error()},
+		})
+		return
+	}
+
+	if matches == nil {
+		matches = []helpers.TagMatch{}
+	}
+	if unmatched == nil {
+		unmatched = []string{}
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Tag suggestions",
+		Data: map[string]any{
+			"tags":            matches,
+			"new_suggestions": unmatched,
+		},
+	})
+}
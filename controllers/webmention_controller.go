@@ -0,0 +1,416 @@
+package controllers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
+	"arlchoose/backend-api/models"
+	"arlchoose/backend-api/structs"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/webmention — terima webmention, verifikasi dijalankan di background (publik)
+func ReceiveWebmention(c *gin.Context) {
+
+	var req structs.WebmentionRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	// Kolom target 255 karakter; dicek ulang setelah dinormalisasi supaya tidak gagal di database
+	req.Target = helpers.NormalizeMentionTarget(req.Target)
+	if len(req.Target) > 255 {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"target": "target must be at most 255 characters"},
+		})
+		return
+	}
+
+	source, err := url.Parse(req.Source)
+	if err != nil || (source.Scheme != "http" && source.Scheme != "https") || req.Source == req.Target {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Validation Errors",
+			Errors:  map[string]string{"source": "source must be an http(s) url different from target"},
+		})
+		return
+	}
+
+	blog, ok := findWebmentionTarget(req.Target)
+	if !ok {
+		c.JSON(http.StatusBadRequest, structs.ErrorResponse{
+			Success: false,
+			Message: "Target does not accept webmentions",
+			Errors:  map[string]string{"target": "target must be a published blog on this site"},
+		})
+		return
+	}
+
+	// Mention yang sama dikirim ulang = source diperbarui, verifikasi ulang
+	var mention models.Webmention
+	database.DB.Where("source = ? AND target = ?", req.Source, req.Target).First(&mention)
+
+	mention.BlogId = blog.Id
+	mention.Source = req.Source
+	mention.Target = req.Target
+	if mention.Id == 0 {
+		mention.Status = "pending"
+	}
+
+	if err := database.DB.Save(&mention).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to save webmention",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	go verifyWebmention(mention.Id)
+
+	c.JSON(http.StatusAccepted, structs.SuccessResponse{
+		Success: true,
+		Message: "Webmention accepted for processing",
+		Data:    nil,
+	})
+}
+
+// findWebmentionTarget cocokkan target dengan blog published: {FRONTEND_URL}/blog/:slug atau {APP_URL}/api/blogs/:slug
+func findWebmentionTarget(target string) (models.Blog, bool) {
+
+	var blog models.Blog
+
+	u, err := url.Parse(target)
+	if err != nil || !isOwnHost(u.Host) {
+		return blog, false
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	var slug string
+	for _, prefix := range []string{"/blog/", "/api/blogs/"} {
+		if strings.HasPrefix(path, prefix) {
+			slug = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	if slug == "" || strings.Contains(slug, "/") {
+		return blog, false
+	}
+
+	if database.DB.Where("slug = ? AND status = ?", slug, "published").First(&blog).Error == nil {
+		return blog, true
+	}
+	if id, ok := helpers.FindSlugRedirect("blog", slug); ok {
+		if database.DB.Where("status = ?", "published").First(&blog, id).Error == nil {
+			return blog, true
+		}
+	}
+	return blog, false
+}
+
+// ownHosts host milik situs ini (API dan frontend)
+func ownHosts() []string {
+	var hosts []string
+	for _, raw := range []string{helpers.GetBaseUrl(), os.Getenv("FRONTEND_URL")} {
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			hosts = append(hosts, strings.ToLower(u.Host))
+		}
+	}
+	return hosts
+}
+
+func isOwnHost(host string) bool {
+	for _, h := range ownHosts() {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyWebmention fetch source dan pastikan menautkan ke target
+func verifyWebmention(id uint) {
+
+	var mention models.Webmention
+	if database.DB.First(&mention, id).Error != nil {
+		return
+	}
+
+	result, err := helpers.VerifyWebmentionSource(mention.Source, mention.Target)
+	if errors.Is(err, helpers.ErrSourceGone) {
+		// Source dihapus → mention juga dihapus
+		database.DB.Delete(&mention)
+		return
+	}
+	if err != nil {
+		mention.Status = "invalid"
+		mention.Error = err.Error()
+		database.DB.Save(&mention)
+		return
+	}
+
+	now := time.Now()
+	mention.Type = result.Type
+	mention.Title = result.Title
+	mention.Excerpt = result.Excerpt
+	mention.AuthorName = result.AuthorName
+	mention.AuthorUrl = result.AuthorUrl
+	mention.AuthorPhoto = result.AuthorPhoto
+	mention.PublishedAt = result.PublishedAt
+	mention.VerifiedAt = &now
+	mention.Error = ""
+
+	// Mention yang sudah dimoderasi tetap di status moderasinya
+	if mention.Status != "approved" && mention.Status != "rejected" {
+		mention.Status = "unmoderated"
+	}
+
+	database.DB.Save(&mention)
+}
+
+// SendBlogWebmentions kirim webmention ke semua link keluar di blog.
+// Target yang pernah dikirimi tapi sudah tidak ada di konten juga dikirimi ulang, sesuai spesifikasi.
+func SendBlogWebmentions(blog models.Blog) {
+
+	if os.Getenv("WEBMENTION_DISABLED") == "true" {
+		return
+	}
+
+	source := frontendBlogUrl(blog.Slug)
+	targets := helpers.ExtractOutboundLinks(blog.Content, ownHosts()...)
+
+	var previous []models.WebmentionSend
+	database.DB.Where("blog_id = ?", blog.Id).Find(&previous)
+
+	seen := map[string]bool{}
+	for _, t := range targets {
+		seen[t] = true
+	}
+	for _, p := range previous {
+		if !seen[p.Target] {
+			targets = append(targets, p.Target)
+		}
+	}
+
+	for _, target := range targets {
+		if len(target) > 500 {
+			continue
+		}
+
+		var send models.WebmentionSend
+		database.DB.Where("blog_id = ? AND target = ?", blog.Id, target).First(&send)
+		send.BlogId = blog.Id
+		send.Target = target
+		send.StatusCode = 0
+		send.Error = ""
+
+		endpoint, err := helpers.DiscoverWebmentionEndpoint(target)
+		switch {
+		case err != nil:
+			send.Status = "failed"
+			send.Error = err.Error()
+		case endpoint == "":
+			send.Status = "no_endpoint"
+		default:
+			send.Endpoint = endpoint
+			send.Status = "sent"
+			send.StatusCode, err = helpers.SendWebmention(endpoint, source, target)
+			if err != nil {
+				send.Status = "failed"
+				send.Error = err.Error()
+				log.Printf("[WEBMENTION ERROR] %s → %s: %v", source, target, err)
+			}
+		}
+
+		database.DB.Save(&send)
+	}
+}
+
+// GET /api/blogs/:slug/webmentions — mention yang sudah disetujui untuk satu blog (publik)
+func FindBlogWebmentions(c *gin.Context) {
+
+	var blog models.Blog
+	if err := database.DB.Where("slug = ? AND status = ?", c.Param("slug"), "published").First(&blog).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Blog not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	var mentions []models.Webmention
+	database.DB.Where("blog_id = ? AND status = ?", blog.Id, "approved").
+		Order("COALESCE(published_at, created_at) asc").
+		Find(&mentions)
+
+	counts := map[string]int{"mention": 0, "reply": 0, "like": 0, "repost": 0}
+	for _, m := range mentions {
+		counts[m.Type]++
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "List Data Webmentions",
+		Data: map[string]any{
+			"counts":      counts,
+			"webmentions": mentions,
+		},
+	})
+}
+
+// GET /api/webmentions — list webmention masuk untuk moderasi (auth)
+func FindWebmentions(c *gin.Context) {
+
+	var mentions []models.Webmention
+	var total int64
+
+	status := c.Query("status")
+	blogId := c.Query("blog_id")
+	pg := helpers.GetPagination(c)
+
+	query := database.DB.Model(&models.Webmention{}).Preload("Blog")
+
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if blogId != "" {
+		query = query.Where("blog_id = ?", blogId)
+	}
+
+	query.Count(&total)
+	query.Order("created_at desc").Limit(pg.Limit).Offset(pg.Offset).Find(&mentions)
+
+	totalPages := int(total) / pg.Limit
+	if int(total)%pg.Limit != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, structs.PaginatedResponse{
+		Success: true,
+		Message: "List Data Webmentions",
+		Data:    mentions,
+		Meta: structs.PaginationMeta{
+			Page:       pg.Page,
+			Limit:      pg.Limit,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	})
+}
+
+// GET /api/webmentions/sent?blog_id= — log webmention keluar (auth)
+func FindSentWebmentions(c *gin.Context) {
+
+	var sends []models.WebmentionSend
+
+	query := database.DB.Order("updated_at desc")
+	if blogId := c.Query("blog_id"); blogId != "" {
+		query = query.Where("blog_id = ?", blogId)
+	}
+	query.Limit(200).Find(&sends)
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "List Data Sent Webmentions",
+		Data:    sends,
+	})
+}
+
+// PUT /api/webmentions/:id/approve — tampilkan mention di halaman blog (auth)
+func ApproveWebmention(c *gin.Context) {
+	moderateWebmention(c, "approved")
+}
+
+// PUT /api/webmentions/:id/reject — sembunyikan mention (auth)
+func RejectWebmention(c *gin.Context) {
+	moderateWebmention(c, "rejected")
+}
+
+func moderateWebmention(c *gin.Context, status string) {
+
+	var mention models.Webmention
+	if err := database.DB.Preload("Blog").First(&mention, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Webmention not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	// Mention yang belum/tidak terverifikasi tidak boleh ditampilkan
+	if status == "approved" && (mention.Status == "pending" || mention.Status == "invalid") {
+		c.JSON(http.StatusUnprocessableEntity, structs.ErrorResponse{
+			Success: false,
+			Message: "Webmention is not verified",
+			Errors:  map[string]string{"status": "only verified webmentions can be approved"},
+		})
+		return
+	}
+
+	mention.Status = status
+	if err := database.DB.Save(&mention).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to update webmention",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	if mention.Blog != nil {
+		go helpers.RevalidateFrontend("blog", mention.Blog.Slug)
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Webmention " + status,
+		Data:    mention,
+	})
+}
+
+// DELETE /api/webmentions/:id — hapus webmention (auth)
+func DeleteWebmention(c *gin.Context) {
+
+	var mention models.Webmention
+	if err := database.DB.First(&mention, c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, structs.ErrorResponse{
+			Success: false,
+			Message: "Webmention not found",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	if err := database.DB.Delete(&mention).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, structs.ErrorResponse{
+			Success: false,
+			Message: "Failed to delete webmention",
+			Errors:  helpers.TranslateErrorMessage(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, structs.SuccessResponse{
+		Success: true,
+		Message: "Webmention deleted successfully",
+		Data:    nil,
+	})
+}
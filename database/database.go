@@ -49,15 +49,42 @@ func InitDB() {
 		&models.ProjectTechStack{},
 		&models.ProjectImage{},
 		&models.Tag{},
+		&models.TagAlias{},
+		&models.PromptTemplate{},
 		&models.Blog{},
+		&models.BlogSource{},
+		&models.BlogRewrite{},
 		&models.Bookmark{},
 		&models.BookmarkTopic{},
 		&models.Tool{},
 		&models.ToolUsage{},
+		&models.SlugRedirect{},
+		&models.BlogLegacyUrl{},
+		&models.Subscriber{},
+		&models.NewsletterDigest{},
+		&models.NewsletterSend{},
+		&models.ApFollower{},
+		&models.ApDelivery{},
+		&models.Webmention{},
+		&models.WebmentionSend{},
+		&models.AiJob{},
+		&models.AiJobTask{},
+		&models.AiSchedule{},
+		&models.AiScheduleRun{},
+		&models.SchedulerLock{},
+		&models.LlmUsage{},
+		&models.ContentEmbedding{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// Blog yang terbit sebelum kolom published_at ada: pakai created_at supaya ikut terhitung di digest dan feed
+	if err := DB.Model(&models.Blog{}).
+		Where("status = ? AND published_at IS NULL", "published").
+		Update("published_at", gorm.Expr("created_at")).Error; err != nil {
+		log.Println("Failed to backfill blogs.published_at:", err)
+	}
+
 	fmt.Println("Database migrated successfully!")
 }
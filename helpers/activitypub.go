@@ -0,0 +1,379 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ActivityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	ActivityStreamsPublic  = "https://www.w3.org/ns/activitystreams#Public"
+	ActivityContentType    = "application/activity+json"
+
+	maxActivityBody = 1 << 20
+	// Toleransi selisih jam antar server saat verifikasi header Date
+	maxSignatureSkew = 12 * time.Hour
+)
+
+var (
+	apKeyOnce sync.Once
+	apKey     *rsa.PrivateKey
+	apKeyErr  error
+
+	// Actor & inbox berasal dari request anonim, jadi alamat internal ditolak seperti fetch biasa
+	apClient = &http.Client{Timeout: 15 * time.Second, Transport: fetchTransport}
+)
+
+// ApActor dokumen actor remote, hanya field yang dipakai
+type ApActor struct {
+	Id                string `json:"id"`
+	Type              string `json:"type"`
+	PreferredUsername string `json:"preferredUsername"`
+	Inbox             string `json:"inbox"`
+	Endpoints         struct {
+		SharedInbox string `json:"sharedInbox"`
+	} `json:"endpoints"`
+	PublicKey struct {
+		Id           string `json:"id"`
+		Owner        string `json:"owner"`
+		PublicKeyPem string `json:"publicKeyPem"`
+	} `json:"publicKey"`
+}
+
+// ApUsername username actor dari env AP_USERNAME (default "blog")
+func ApUsername() string {
+	return getEnvDefault("AP_USERNAME", "blog")
+}
+
+// ApDomain host dari APP_URL, dipakai untuk acct:user@domain
+func ApDomain() string {
+	u, err := url.Parse(GetBaseUrl())
+	if err != nil || u.Host == "" {
+		return "localhost"
+	}
+	return u.Host
+}
+
+// ApActorUrl id actor milik situs ini
+func ApActorUrl() string {
+	return strings.TrimSuffix(GetBaseUrl(), "/") + "/api/ap/actor"
+}
+
+// ApKeyId id public key untuk header Signature
+func ApKeyId() string {
+	return ApActorUrl() + "#main-key"
+}
+
+// ApPrivateKey load RSA key dari AP_KEY_FILE, generate sekali kalau belum ada
+func ApPrivateKey() (*rsa.PrivateKey, error) {
+	apKeyOnce.Do(func() {
+		keyFile := getEnvDefault("AP_KEY_FILE", "storage/activitypub/private.pem")
+
+		if raw, err := os.ReadFile(keyFile); err == nil {
+			block, _ := pem.Decode(raw)
+			if block == nil {
+				apKeyErr = fmt.Errorf("invalid key file %s", keyFile)
+				return
+			}
+			apKey, apKeyErr = x509.ParsePKCS1PrivateKey(block.Bytes)
+			return
+		}
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			apKeyErr = err
+			return
+		}
+		if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+			apKeyErr = err
+			return
+		}
+		data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		if err := os.WriteFile(keyFile, data, 0600); err != nil {
+			apKeyErr = err
+			return
+		}
+		apKey = key
+	})
+	return apKey, apKeyErr
+}
+
+// ApPublicKeyPem public key dalam format PEM untuk dokumen actor
+func ApPublicKeyPem() (string, error) {
+	key, err := ApPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// bodyDigest nilai header Digest: SHA-256=<base64>
+func bodyDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// signingString susun string yang ditandatangani sesuai daftar header
+func signingString(req *http.Request, headers []string) (string, error) {
+	lines := make([]string, 0, len(headers))
+	for _, h := range headers {
+		switch h {
+		case "(request-target)":
+			lines = append(lines, fmt.Sprintf("(request-target): %s %s", strings.ToLower(req.Method), req.URL.RequestURI()))
+		case "host":
+			host := req.Host
+			if host == "" {
+				host = req.URL.Host
+			}
+			lines = append(lines, "host: "+host)
+		default:
+			value := req.Header.Get(h)
+			if value == "" {
+				return "", fmt.Errorf("missing signed header %s", h)
+			}
+			lines = append(lines, h+": "+value)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// SignRequest tanda tangani request dengan HTTP Signatures (rsa-sha256, format yang dipakai Mastodon)
+func SignRequest(req *http.Request, body []byte) error {
+	key, err := ApPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	headers := []string{"(request-target)", "host", "date"}
+	if body != nil {
+		req.Header.Set("Digest", bodyDigest(body))
+		headers = append(headers, "digest")
+	}
+
+	toSign, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+	hashed := sha256.Sum256([]byte(toSign))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		ApKeyId(), strings.Join(headers, " "), base64.StdEncoding.EncodeToString(sig)))
+	return nil
+}
+
+// parseSignatureHeader pecah header Signature jadi map keyId/headers/signature
+func parseSignatureHeader(header string) map[string]string {
+	params := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[k] = strings.Trim(v, `"`)
+	}
+	return params
+}
+
+// VerifyRequest cek HTTP Signature request masuk, return actor pengirim
+func VerifyRequest(req *http.Request, body []byte) (*ApActor, error) {
+	params := parseSignatureHeader(req.Header.Get("Signature"))
+	keyId := params["keyId"]
+	if keyId == "" || params["signature"] == "" {
+		return nil, errors.New("missing signature")
+	}
+
+	headers := strings.Fields(params["headers"])
+	if len(headers) == 0 {
+		headers = []string{"date"}
+	}
+	required := map[string]bool{"(request-target)": false, "host": false, "date": false, "digest": body == nil}
+	for _, h := range headers {
+		if _, ok := required[h]; ok {
+			required[h] = true
+		}
+	}
+	for h, signed := range required {
+		if !signed {
+			return nil, fmt.Errorf("header %s must be signed", h)
+		}
+	}
+
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return nil, errors.New("invalid date header")
+	}
+	if skew := time.Since(date); skew > maxSignatureSkew || skew < -maxSignatureSkew {
+		return nil, errors.New("date header is out of range")
+	}
+	if body != nil && req.Header.Get("Digest") != bodyDigest(body) {
+		return nil, errors.New("digest mismatch")
+	}
+
+	// Dokumen di URL keyId harus mengaku sebagai dirinya sendiri; kalau tidak, server lain bisa
+	// menyamar sebagai actor mana pun dengan id palsu + public key miliknya
+	actorUrl := strings.SplitN(keyId, "#", 2)[0]
+	actor, err := FetchActor(actorUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch actor: %v", err)
+	}
+	if actor.Id != actorUrl {
+		return nil, errors.New("actor id does not match key id")
+	}
+	if actor.PublicKey.Id != keyId || (actor.PublicKey.Owner != "" && actor.PublicKey.Owner != actor.Id) {
+		return nil, errors.New("key id does not belong to actor")
+	}
+
+	pub, err := parsePublicKeyPem(actor.PublicKey.PublicKeyPem)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil {
+		return nil, errors.New("invalid signature encoding")
+	}
+	toVerify, err := signingString(req, headers)
+	if err != nil {
+		return nil, err
+	}
+	hashed := sha256.Sum256([]byte(toVerify))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], sig); err != nil {
+		return nil, errors.New("signature verification failed")
+	}
+
+	return actor, nil
+}
+
+// parsePublicKeyPem terima format PKIX maupun PKCS1
+func parsePublicKeyPem(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid public key")
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+		return nil, errors.New("unsupported public key type")
+	}
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
+
+// FetchActor ambil dokumen actor remote (signed GET, untuk server yang mewajibkan authorized fetch)
+func FetchActor(actorUrl string) (*ApActor, error) {
+	req, err := http.NewRequest(http.MethodGet, actorUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", ActivityContentType)
+	req.Header.Set("User-Agent", apUserAgent())
+	if err := SignRequest(req, nil); err != nil {
+		return nil, err
+	}
+
+	resp, err := apClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("actor responded %d", resp.StatusCode)
+	}
+
+	var actor ApActor
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxActivityBody)).Decode(&actor); err != nil {
+		return nil, err
+	}
+	if actor.Id == "" || actor.Inbox == "" {
+		return nil, errors.New("actor document is incomplete")
+	}
+	// Inbox di host lain berarti activity kita bisa dibelokkan ke server pihak ketiga
+	if !sameHost(actor.Id, actor.Inbox) || (actor.Endpoints.SharedInbox != "" && !sameHost(actor.Id, actor.Endpoints.SharedInbox)) {
+		return nil, errors.New("actor inbox is on a different host")
+	}
+	return &actor, nil
+}
+
+// sameHost apakah dua URL http(s) berada di scheme + host yang sama
+func sameHost(a string, b string) bool {
+	ua, errA := url.Parse(a)
+	ub, errB := url.Parse(b)
+	if errA != nil || errB != nil || ua.Host == "" {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host) && ua.Scheme == ub.Scheme
+}
+
+// DeliverActivity POST activity yang sudah ditandatangani ke inbox, return status code
+func DeliverActivity(inbox string, activity any) (int, error) {
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", ActivityContentType)
+	req.Header.Set("Accept", ActivityContentType)
+	req.Header.Set("User-Agent", apUserAgent())
+	if err := SignRequest(req, body); err != nil {
+		return 0, err
+	}
+
+	resp, err := apClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxActivityBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("inbox responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// ReadActivityBody baca body request masuk dengan batas ukuran
+func ReadActivityBody(r io.Reader) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, maxActivityBody+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxActivityBody {
+		return nil, errors.New("activity is too large")
+	}
+	return body, nil
+}
+
+func apUserAgent() string {
+	return "Arlchoose/1.0 (+" + GetBaseUrl() + ")"
+}
@@ -0,0 +1,187 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+var keyIdPattern = regexp.MustCompile(`keyId="[^"]*"`)
+
+// apTestServer server actor palsu; semua actor memakai public key situs ini supaya request bisa ditandatangani di test
+func apTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	t.Setenv("AP_KEY_FILE", filepath.Join(t.TempDir(), "private.pem"))
+	t.Setenv("FETCH_ALLOW_PRIVATE", "true")
+
+	pub, err := ApPublicKeyPem()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var srv *httptest.Server
+	actor := func(id, inbox, keyId string) map[string]any {
+		return map[string]any{
+			"id":    id,
+			"type":  "Person",
+			"inbox": inbox,
+			"publicKey": map[string]any{
+				"id":           keyId,
+				"owner":        id,
+				"publicKeyPem": pub,
+			},
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/alice", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(actor(srv.URL+"/users/alice", srv.URL+"/users/alice/inbox", srv.URL+"/users/alice#main-key"))
+	})
+	// Mengaku sebagai actor di server lain dengan key miliknya sendiri
+	mux.HandleFunc("/users/mallory", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(actor("https://victim.example/users/x", "https://victim.example/inbox", srv.URL+"/users/mallory#main-key"))
+	})
+	// Inbox diarahkan ke host lain
+	mux.HandleFunc("/users/eve", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(actor(srv.URL+"/users/eve", "https://other.example/inbox", srv.URL+"/users/eve#main-key"))
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// signedInboxRequest POST ke inbox yang ditandatangani dengan key situs ini tapi keyId milik actor lain
+func signedInboxRequest(t *testing.T, keyId string, body []byte) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "https://blog.example/api/ap/inbox", bytes.NewReader(body))
+	if err := SignRequest(req, body); err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Signature", keyIdPattern.ReplaceAllString(req.Header.Get("Signature"), `keyId="`+keyId+`"`))
+	return req
+}
+
+func TestVerifyRequest(t *testing.T) {
+	srv := apTestServer(t)
+	body := []byte(`{"type":"Follow"}`)
+
+	tests := []struct {
+		name    string
+		req     func() (*http.Request, []byte)
+		wantId  string
+		wantErr bool
+	}{
+		{
+			name: "valid signature",
+			req: func() (*http.Request, []byte) {
+				return signedInboxRequest(t, srv.URL+"/users/alice#main-key", body), body
+			},
+			wantId: srv.URL + "/users/alice",
+		},
+		{
+			name: "actor document claims another id",
+			req: func() (*http.Request, []byte) {
+				return signedInboxRequest(t, srv.URL+"/users/mallory#main-key", body), body
+			},
+			wantErr: true,
+		},
+		{
+			name: "inbox on another host",
+			req: func() (*http.Request, []byte) {
+				return signedInboxRequest(t, srv.URL+"/users/eve#main-key", body), body
+			},
+			wantErr: true,
+		},
+		{
+			name: "key id not in actor document",
+			req: func() (*http.Request, []byte) {
+				return signedInboxRequest(t, srv.URL+"/users/alice#other-key", body), body
+			},
+			wantErr: true,
+		},
+		{
+			name: "body changed after signing",
+			req: func() (*http.Request, []byte) {
+				return signedInboxRequest(t, srv.URL+"/users/alice#main-key", body), []byte(`{"type":"Delete"}`)
+			},
+			wantErr: true,
+		},
+		{
+			name: "date changed after signing",
+			req: func() (*http.Request, []byte) {
+				req := signedInboxRequest(t, srv.URL+"/users/alice#main-key", body)
+				req.Header.Set("Date", "Mon, 01 Jan 2001 00:00:00 GMT")
+				return req, body
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing signature",
+			req: func() (*http.Request, []byte) {
+				return httptest.NewRequest(http.MethodPost, "https://blog.example/api/ap/inbox", bytes.NewReader(body)), body
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		req, reqBody := tt.req()
+		actor, err := VerifyRequest(req, reqBody)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got actor %s", tt.name, actor.Id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if actor.Id != tt.wantId {
+			t.Errorf("%s: actor id = %s, want %s", tt.name, actor.Id, tt.wantId)
+		}
+	}
+}
+
+func TestDeliverActivityToFakeInbox(t *testing.T) {
+	t.Setenv("AP_KEY_FILE", filepath.Join(t.TempDir(), "private.pem"))
+	t.Setenv("FETCH_ALLOW_PRIVATE", "true")
+
+	var got *http.Request
+	var gotBody []byte
+	inbox := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer inbox.Close()
+
+	status, err := DeliverActivity(inbox.URL+"/inbox", map[string]any{"type": "Create", "id": "https://blog.example/1"})
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("DeliverActivity = (%d, %v)", status, err)
+	}
+	if got.Header.Get("Content-Type") != ActivityContentType {
+		t.Errorf("content type = %q", got.Header.Get("Content-Type"))
+	}
+	if got.Header.Get("Digest") != bodyDigest(gotBody) {
+		t.Error("digest does not match delivered body")
+	}
+	params := parseSignatureHeader(got.Header.Get("Signature"))
+	if params["keyId"] != ApKeyId() || params["headers"] != "(request-target) host date digest" {
+		t.Errorf("unexpected signature params %v", params)
+	}
+
+	// Inbox yang menolak dilaporkan sebagai error beserta status code-nya
+	reject := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer reject.Close()
+	if status, err := DeliverActivity(reject.URL, map[string]any{"type": "Create"}); err == nil || status != http.StatusUnauthorized {
+		t.Errorf("expected 401 error, got (%d, %v)", status, err)
+	}
+}
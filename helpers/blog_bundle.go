@@ -0,0 +1,260 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// BlogFrontMatter metadata blog yang ditulis sebagai YAML front matter di file Markdown
+type BlogFrontMatter struct {
+	Title       string     `yaml:"title"`
+	Slug        string     `yaml:"slug"`
+	Description string     `yaml:"description,omitempty"`
+	Status      string     `yaml:"status"`
+	Author      string     `yaml:"author,omitempty"`
+	Tags        []string   `yaml:"tags,omitempty"`
+	CoverImage  string     `yaml:"cover_image,omitempty"`
+	PublishedAt *time.Time `yaml:"published_at,omitempty"`
+	CreatedAt   time.Time  `yaml:"created_at"`
+	UpdatedAt   time.Time  `yaml:"updated_at"`
+}
+
+// BundlePost satu post di dalam bundle: front matter + isi konten (HTML, di file ditulis sebagai Markdown)
+type BundlePost struct {
+	File        string
+	FrontMatter BlogFrontMatter
+	Content     string
+}
+
+// Bundle isi zip hasil parse: post Markdown + file upload yang direferensikan
+type Bundle struct {
+	Posts   []BundlePost
+	Uploads map[string][]byte // key: path lokal, contoh "uploads/blogs/123.jpg"
+}
+
+const (
+	bundlePostDir    = "posts/"
+	maxBundleEntries = 5000
+	maxBundleFile    = 50 << 20
+	// Total isi zip setelah diekstrak; ukuran per entry di header zip bisa dipalsukan, jadi dihitung dari byte yang benar-benar dibaca
+	maxBundleTotal = 200 << 20
+)
+
+var uploadRefPattern = regexp.MustCompile(`/uploads/[A-Za-z0-9_\-./]+`)
+
+// FindUploadRefs cari semua path "uploads/..." yang direferensikan di teks (cover, konten HTML)
+func FindUploadRefs(texts ...string) []string {
+	seen := map[string]bool{}
+	var refs []string
+	for _, text := range texts {
+		for _, match := range uploadRefPattern.FindAllString(text, -1) {
+			ref := strings.TrimPrefix(match, "/")
+			if strings.Contains(ref, "..") || seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// RenderMarkdownPost gabungkan front matter YAML dan konten jadi satu file Markdown.
+// Konten HTML dikonversi ke Markdown; bagian tanpa padanan Markdown tetap HTML inline.
+func RenderMarkdownPost(fm BlogFrontMatter, content string) ([]byte, error) {
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal front matter: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n")
+	buf.WriteString(HtmlToMarkdown(content))
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// ParseMarkdownPost pisahkan front matter YAML dari konten, lalu konversi konten Markdown ke HTML.
+// Bundle lama yang isinya HTML tetap terbaca karena HTML mentah diteruskan apa adanya.
+func ParseMarkdownPost(data []byte) (BlogFrontMatter, string, error) {
+	var fm BlogFrontMatter
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return fm, "", fmt.Errorf("missing front matter")
+	}
+
+	rest := text[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	if end == -1 {
+		return fm, "", fmt.Errorf("unterminated front matter")
+	}
+
+	if err := yaml.Unmarshal([]byte(rest[:end+1]), &fm); err != nil {
+		return fm, "", fmt.Errorf("invalid front matter: %v", err)
+	}
+
+	return fm, MarkdownToHtml(strings.TrimSpace(rest[end+len("\n---\n"):])), nil
+}
+
+// WriteBlogBundle tulis post dan file upload yang direferensikan ke zip
+func WriteBlogBundle(w io.Writer, posts []BundlePost) error {
+	zw := zip.NewWriter(w)
+
+	written := map[string]bool{}
+	for _, post := range posts {
+		data, err := RenderMarkdownPost(post.FrontMatter, post.Content)
+		if err != nil {
+			return err
+		}
+
+		f, err := zw.Create(bundlePostDir + post.FrontMatter.Slug + ".md")
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+
+		// Ikutkan file upload; file yang sudah tidak ada di disk dilewati
+		for _, ref := range FindUploadRefs(post.FrontMatter.CoverImage, post.Content) {
+			if written[ref] {
+				continue
+			}
+			raw, err := os.ReadFile(ref)
+			if err != nil {
+				continue
+			}
+			f, err := zw.Create(ref)
+			if err != nil {
+				return err
+			}
+			if _, err := f.Write(raw); err != nil {
+				return err
+			}
+			written[ref] = true
+		}
+	}
+
+	return zw.Close()
+}
+
+// ReadBlogBundle baca zip bundle dari memory
+func ReadBlogBundle(data []byte) (*Bundle, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip file: %v", err)
+	}
+	if len(zr.File) > maxBundleEntries {
+		return nil, fmt.Errorf("bundle has too many files (max %d)", maxBundleEntries)
+	}
+
+	bundle := &Bundle{Uploads: map[string][]byte{}}
+	budget := int64(maxBundleTotal)
+
+	for _, file := range zr.File {
+		name := path.Clean(file.Name)
+		if file.FileInfo().IsDir() || strings.Contains(file.Name, "..") || strings.HasPrefix(name, "/") {
+			continue
+		}
+		if file.UncompressedSize64 > maxBundleFile {
+			return nil, fmt.Errorf("%s is too large", name)
+		}
+
+		isPost := strings.HasPrefix(name, bundlePostDir) && strings.HasSuffix(name, ".md")
+		isUpload := strings.HasPrefix(name, "uploads/")
+		if !isPost && !isUpload {
+			continue
+		}
+
+		raw, err := readZipEntry(file, &budget)
+		if err != nil {
+			return nil, err
+		}
+
+		if isUpload {
+			bundle.Uploads[name] = raw
+			continue
+		}
+
+		fm, content, err := ParseMarkdownPost(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		bundle.Posts = append(bundle.Posts, BundlePost{File: name, FrontMatter: fm, Content: content})
+	}
+
+	return bundle, nil
+}
+
+// readZipEntry baca isi satu entry zip, dibatasi maxBundleFile per file dan sisa budget total.
+// Entry yang melebihi batas ditolak, bukan dipotong.
+func readZipEntry(file *zip.File, budget *int64) ([]byte, error) {
+	limit := min(int64(maxBundleFile), *budget)
+
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > limit {
+		if limit == *budget {
+			return nil, fmt.Errorf("archive is too large (max %d MB uncompressed)", maxBundleTotal>>20)
+		}
+		return nil, fmt.Errorf("%s is too large", file.Name)
+	}
+
+	*budget -= int64(len(raw))
+	return raw, nil
+}
+
+// PlanBundleUpload tentukan path tujuan file upload dari bundle tanpa menulis ke disk.
+// Kalau path yang sama sudah berisi file identik, path lama dipakai lagi.
+// Kalau bentrok dengan file lain, pakai nama baru supaya file lama tidak tertimpa.
+func PlanBundleUpload(ref string, data []byte) string {
+	if existing, err := os.ReadFile(ref); err == nil && !bytes.Equal(existing, data) {
+		return fmt.Sprintf("%s/%d%s", path.Dir(ref), time.Now().UnixNano(), path.Ext(ref))
+	}
+	return ref
+}
+
+// SaveBundleUpload tulis file upload ke path hasil PlanBundleUpload; file identik yang sudah ada dibiarkan
+func SaveBundleUpload(target string, data []byte) error {
+	if existing, err := os.ReadFile(target); err == nil {
+		if bytes.Equal(existing, data) {
+			return nil
+		}
+		return fmt.Errorf("%s already exists", target)
+	}
+
+	if err := os.MkdirAll(path.Dir(target), os.ModePerm); err != nil {
+		return err
+	}
+	return writeFileAtomic(target, data, 0644)
+}
+
+// RewriteUploadRefs ganti path upload lama ke path baru di teks
+func RewriteUploadRefs(text string, mapping map[string]string) string {
+	return uploadRefPattern.ReplaceAllStringFunc(text, func(match string) string {
+		if newRef, ok := mapping[strings.TrimPrefix(match, "/")]; ok {
+			return "/" + newRef
+		}
+		return match
+	})
+}
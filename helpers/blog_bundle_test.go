@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlogBundleWritesMarkdown(t *testing.T) {
+	content := `<h2 id="mulai">Mulai</h2><p>Belajar <strong>Go</strong> dengan <a href="https://go.dev">tur resmi</a>.</p><ul><li>Satu</li><li>Dua</li></ul>`
+	post := BundlePost{
+		FrontMatter: BlogFrontMatter{Title: "Belajar Go", Slug: "belajar-go", Status: "published", Tags: []string{"Go"}, CreatedAt: time.Unix(0, 0).UTC()},
+		Content:     content,
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBlogBundle(&buf, []BundlePost{post}); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := zr.Open("posts/belajar-go.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := io.ReadAll(f)
+	if !strings.Contains(string(raw), "## Mulai {#mulai}\n\nBelajar **Go** dengan [tur resmi](https://go.dev).\n\n- Satu\n- Dua\n") {
+		t.Errorf("post file is not Markdown:\n%s", raw)
+	}
+
+	bundle, err := ReadBlogBundle(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundle.Posts) != 1 || bundle.Posts[0].FrontMatter.Title != "Belajar Go" {
+		t.Fatalf("unexpected posts %+v", bundle.Posts)
+	}
+	if got := normalizeHtml(bundle.Posts[0].Content); got != normalizeHtml(content) {
+		t.Errorf("content = %s, want %s", got, content)
+	}
+}
+
+func TestParseMarkdownPostKeepsLegacyHtml(t *testing.T) {
+	legacy := "---\ntitle: Lama\nslug: lama\nstatus: draft\ncreated_at: 2024-01-01T00:00:00Z\nupdated_at: 2024-01-01T00:00:00Z\n---\n\n<h2>Bagian</h2>\n<p>Isi <em>lama</em>.</p>\n"
+
+	fm, content, err := ParseMarkdownPost([]byte(legacy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fm.Slug != "lama" {
+		t.Errorf("slug = %q", fm.Slug)
+	}
+	if content != "<h2>Bagian</h2>\n<p>Isi <em>lama</em>.</p>" {
+		t.Errorf("content = %q", content)
+	}
+}
@@ -6,17 +6,10 @@ import (
 	"log"
 	"net/http"
 	"net/url"
-	"os"
+	"strconv"
 	"strings"
-	"time"
 )
 
-type BraveSearchResult struct {
-	Title       string `json:"title"`
-	Url         string `json:"url"`
-	Description string `json:"description"`
-}
-
 type BraveSearchResponse struct {
 	Web struct {
 		Results []struct {
@@ -27,54 +20,41 @@ type BraveSearchResponse struct {
 	} `json:"web"`
 }
 
-// cleanQuery hanya bersihkan karakter bermasalah, TIDAK memotong kata
-func cleanQuery(title string) string {
-	replacer := strings.NewReplacer(
-		"\"", "", "'", "", "\\", "", "#", "",
-		":", "", "?", "", "!", "",
-	)
-	clean := strings.TrimSpace(replacer.Replace(title))
-
-	// Batasi maksimal 100 karakter untuk hindari 422
-	if len(clean) > 100 {
-		// Potong di kata terakhir yang masuk dalam 100 karakter
-		words := strings.Fields(clean)
-		result := ""
-		for _, w := range words {
-			if len(result)+len(w)+1 > 100 {
-				break
-			}
-			if result != "" {
-				result += " "
-			}
-			result += w
-		}
-		return result
-	}
-	return clean
+// BraveProvider Brave Search API, butuh BRAVE_API_KEY
+type BraveProvider struct {
+	ApiKey string
 }
 
-// SearchBrave mencari artikel menggunakan Brave Search API
-func SearchBrave(query string) ([]BraveSearchResult, error) {
+func (p *BraveProvider) Name() string     { return "brave" }
+func (p *BraveProvider) Configured() bool { return p.ApiKey != "" }
 
-	apiKey := os.Getenv("BRAVE_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("BRAVE_API_KEY is not set")
-	}
+// braveLangs search_lang yang diterima Brave; bahasa lain dikirim tanpa search_lang
+// (Brave belum punya "id", hasil tetap diarahkan lewat country)
+var braveLangs = map[string]bool{
+	"ar": true, "bn": true, "de": true, "en": true, "es": true, "fr": true, "hi": true, "it": true,
+	"jp": true, "ko": true, "ms": true, "nl": true, "pl": true, "pt-br": true, "ru": true, "th": true,
+	"tr": true, "vi": true, "zh-hans": true, "zh-hant": true,
+}
 
-	// Bersihkan query tapi JANGAN dipotong
-	cleanedQuery := cleanQuery(query)
-	log.Printf("[BRAVE QUERY] %s", cleanedQuery)
+var braveFreshness = map[string]string{"day": "pd", "week": "pw", "month": "pm", "year": "py"}
 
-	// Delay hindari rate limit
-	time.Sleep(1 * time.Second)
+// Search mencari artikel menggunakan Brave Search API
+func (p *BraveProvider) Search(query string, opts SearchOptions) ([]SearchResult, error) {
 
-	// count=2 untuk dapat lebih banyak referensi
-	searchUrl := fmt.Sprintf(
-		"https://api.search.brave.com/res/v1/web/search?q=%s&count=2&search_lang=ms",
-		url.QueryEscape(cleanedQuery),
-	)
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("count", strconv.Itoa(opts.Count))
+	if lang := strings.ToLower(opts.Lang); braveLangs[lang] {
+		params.Set("search_lang", lang)
+	}
+	if opts.Country != "" {
+		params.Set("country", opts.Country)
+	}
+	if freshness, ok := braveFreshness[opts.Freshness]; ok {
+		params.Set("freshness", freshness)
+	}
 
+	searchUrl := "https://api.search.brave.com/res/v1/web/search?" + params.Encode()
 	log.Printf("[BRAVE URL] %s", searchUrl)
 
 	req, err := http.NewRequest("GET", searchUrl, nil)
@@ -83,25 +63,16 @@ func SearchBrave(query string) ([]BraveSearchResult, error) {
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("X-Subscription-Token", apiKey)
+	req.Header.Set("X-Subscription-Token", p.ApiKey)
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := searchClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search brave: %v", err)
 	}
 	defer resp.Body.Close()
 
-	log.Printf("[BRAVE STATUS] %d", resp.StatusCode)
-
-	if resp.StatusCode == 422 {
-		return nil, fmt.Errorf("brave api 422 - invalid request")
-	}
-	if resp.StatusCode == 429 {
-		return nil, fmt.Errorf("brave api 429 - rate limit exceeded")
-	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("brave api returned status %d", resp.StatusCode)
+		return nil, &SearchStatusError{Provider: p.Name(), Code: resp.StatusCode}
 	}
 
 	var braveResp BraveSearchResponse
@@ -109,16 +80,14 @@ func SearchBrave(query string) ([]BraveSearchResult, error) {
 		return nil, fmt.Errorf("failed to decode brave response: %v", err)
 	}
 
-	var results []BraveSearchResult
+	var results []SearchResult
 	for _, r := range braveResp.Web.Results {
-		results = append(results, BraveSearchResult{
+		results = append(results, SearchResult{
 			Title:       r.Title,
 			Url:         r.Url,
 			Description: r.Description,
 		})
 	}
 
-	log.Printf("[BRAVE RESULTS] found %d results", len(results))
-
 	return results, nil
 }
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"arlchoose/backend-api/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResearchSource satu sumber hasil scrape, disimpan di task sampai blog dibuat
+type ResearchSource struct {
+	Title       string    `json:"title"`
+	Url         string    `json:"url"`
+	Snippet     string    `json:"snippet"`
+	Content     string    `json:"content"`
+	ContentHash string    `json:"content_hash"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// ContentHash sha256 hex dari isi sumber, untuk tahu kalau sumbernya berubah
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// DecodeResearchSources baca ScrapedRefs; task lama menyimpan []string tanpa URL
+func DecodeResearchSources(raw string) []ResearchSource {
+	var sources []ResearchSource
+	if err := json.Unmarshal([]byte(raw), &sources); err == nil {
+		return sources
+	}
+
+	var refs []string
+	json.Unmarshal([]byte(raw), &refs)
+	sources = nil
+	for _, ref := range refs {
+		sources = append(sources, ResearchSource{Content: ref})
+	}
+	return sources
+}
+
+var citationPattern = regexp.MustCompile(`\[(\d{1,2})\]`)
+
+// LinkCitations ubah sitasi [n] di konten jadi link ke daftar referensi.
+// Sitasi yang sudah jadi link atau nomornya di luar jumlah sumber dibiarkan.
+func LinkCitations(content string, total int) string {
+	if total == 0 {
+		return content
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range citationPattern.FindAllStringSubmatchIndex(content, -1) {
+		n, _ := strconv.Atoi(content[m[2]:m[3]])
+		if n < 1 || n > total || (m[0] > 0 && content[m[0]-1] == '>') {
+			continue
+		}
+		b.WriteString(content[last:m[0]])
+		fmt.Fprintf(&b, `<sup><a href="#ref-%d">[%d]</a></sup>`, n, n)
+		last = m[1]
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
+
+// RenderReferencesHtml bagian "Referensi" di akhir artikel
+func RenderReferencesHtml(sources []models.BlogSource) string {
+	if len(sources) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<h2>Referensi</h2>\n<ol>\n")
+	for _, s := range sources {
+		title := s.Title
+		if title == "" {
+			title = s.Url
+		}
+		fmt.Fprintf(&b, `<li id="ref-%d"><a href="%s" target="_blank" rel="noopener nofollow">%s</a></li>`+"\n",
+			s.Position, html.EscapeString(s.Url), html.EscapeString(title))
+	}
+	b.WriteString("</ol>")
+	return b.String()
+}
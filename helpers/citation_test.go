@@ -0,0 +1,62 @@
+package helpers
+
+import "testing"
+
+func TestLinkCitations(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		total   int
+		want    string
+	}{
+		{
+			name:    "no sources",
+			content: "<p>Teks [1].</p>",
+			total:   0,
+			want:    "<p>Teks [1].</p>",
+		},
+		{
+			name:    "single citation",
+			content: "<p>Teks [1].</p>",
+			total:   2,
+			want:    `<p>Teks <sup><a href="#ref-1">[1]</a></sup>.</p>`,
+		},
+		{
+			name:    "multiple citations",
+			content: "<p>Satu [1] dua [2].</p>",
+			total:   2,
+			want:    `<p>Satu <sup><a href="#ref-1">[1]</a></sup> dua <sup><a href="#ref-2">[2]</a></sup>.</p>`,
+		},
+		{
+			name:    "number out of range",
+			content: "<p>Teks [0] dan [3].</p>",
+			total:   2,
+			want:    "<p>Teks [0] dan [3].</p>",
+		},
+		{
+			name:    "already linked",
+			content: `<p>Teks <sup><a href="#ref-1">[1]</a></sup>.</p>`,
+			total:   1,
+			want:    `<p>Teks <sup><a href="#ref-1">[1]</a></sup>.</p>`,
+		},
+		{
+			name:    "three digits are not citations",
+			content: "<p>arr[100]</p>",
+			total:   5,
+			want:    "<p>arr[100]</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := LinkCitations(tt.content, tt.total); got != tt.want {
+			t.Errorf("%s: LinkCitations() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinkCitationsIsIdempotent(t *testing.T) {
+	once := LinkCitations("<p>Satu [1] dua [2].</p>", 2)
+	if twice := LinkCitations(once, 2); twice != once {
+		t.Errorf("second pass changed content:\n%s\n%s", once, twice)
+	}
+}
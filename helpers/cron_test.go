@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronRejectsInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"abc * * * *",
+		"* * * foo *",
+	}
+	for _, expr := range tests {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) expected error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	loc := time.UTC
+	// Senin, 5 Januari 2026 10:07
+	base := time.Date(2026, time.January, 5, 10, 7, 30, 0, loc)
+
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"* * * * *", base, time.Date(2026, 1, 5, 10, 8, 0, 0, loc)},
+		{"*/15 * * * *", base, time.Date(2026, 1, 5, 10, 15, 0, 0, loc)},
+		{"0 * * * *", base, time.Date(2026, 1, 5, 11, 0, 0, 0, loc)},
+		{"@hourly", base, time.Date(2026, 1, 5, 11, 0, 0, 0, loc)},
+		{"@daily", base, time.Date(2026, 1, 6, 0, 0, 0, 0, loc)},
+		{"30 9 * * *", base, time.Date(2026, 1, 6, 9, 30, 0, 0, loc)},
+		{"0 9 * * mon-fri", time.Date(2026, 1, 9, 12, 0, 0, 0, loc), time.Date(2026, 1, 12, 9, 0, 0, 0, loc)},
+		{"0 0 * * 7", base, time.Date(2026, 1, 11, 0, 0, 0, 0, loc)},
+		{"0 0 * * sun", base, time.Date(2026, 1, 11, 0, 0, 0, 0, loc)},
+		{"@weekly", base, time.Date(2026, 1, 11, 0, 0, 0, 0, loc)},
+		{"@monthly", base, time.Date(2026, 2, 1, 0, 0, 0, 0, loc)},
+		{"0 0 1 jan *", base, time.Date(2027, 1, 1, 0, 0, 0, 0, loc)},
+		{"5/20 * * * *", base, time.Date(2026, 1, 5, 10, 25, 0, 0, loc)},
+		{"0,30 8-9 * * *", base, time.Date(2026, 1, 6, 8, 0, 0, 0, loc)},
+		{"0 0 29 2 *", base, time.Date(2028, 2, 29, 0, 0, 0, 0, loc)},
+		// Tanggal dan hari-minggu sama-sama dibatasi: cukup salah satu cocok (tanggal 15 atau hari Jumat)
+		{"0 0 15 * fri", base, time.Date(2026, 1, 9, 0, 0, 0, 0, loc)},
+		// Waktu yang persis cocok dilewati, hasil selalu setelah after
+		{"0 10 * * *", time.Date(2026, 1, 5, 10, 0, 0, 0, loc), time.Date(2026, 1, 6, 10, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCron(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(tt.after); !got.Equal(tt.want) {
+			t.Errorf("%q.Next(%s) = %s, want %s", tt.expr, tt.after.Format(time.RFC3339), got.Format(time.RFC3339), tt.want.Format(time.RFC3339))
+		}
+	}
+}
+
+func TestCronNextImpossible(t *testing.T) {
+	s, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Errorf("expected zero time for 30 February, got %s", got)
+	}
+}
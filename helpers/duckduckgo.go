@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DuckDuckGoProvider scraping html.duckduckgo.com, tanpa API key.
+// Dipakai sebagai cadangan terakhir karena paling mudah kena blokir.
+type DuckDuckGoProvider struct{}
+
+func (p *DuckDuckGoProvider) Name() string     { return "duckduckgo" }
+func (p *DuckDuckGoProvider) Configured() bool { return true }
+
+var ddgFreshness = map[string]string{"day": "d", "week": "w", "month": "m", "year": "y"}
+
+// ddgRegions region DuckDuckGo untuk negara yang kodenya tidak mengikuti pola negara-bahasa
+var ddgRegions = map[string]string{"ID": "id-en", "MY": "my-en", "SG": "sg-en", "PH": "ph-en", "US": "us-en", "GB": "uk-en"}
+
+func (p *DuckDuckGoProvider) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+
+	params := url.Values{}
+	params.Set("q", query)
+	if region, ok := ddgRegions[opts.Country]; ok {
+		params.Set("kl", region)
+	} else if opts.Country != "" && opts.Lang != "" {
+		params.Set("kl", strings.ToLower(opts.Country)+"-"+strings.ToLower(opts.Lang))
+	}
+	if freshness, ok := ddgFreshness[opts.Freshness]; ok {
+		params.Set("df", freshness)
+	}
+
+	req, err := http.NewRequest("GET", "https://html.duckduckgo.com/html/?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("User-Agent", FetchUserAgent())
+
+	resp, err := searchClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search duckduckgo: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// DuckDuckGo membalas 202 dengan halaman captcha kalau terlalu sering, anggap rate limit
+	if resp.StatusCode == http.StatusAccepted {
+		return nil, &SearchStatusError{Provider: p.Name(), Code: http.StatusTooManyRequests}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &SearchStatusError{Provider: p.Name(), Code: resp.StatusCode}
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duckduckgo response: %v", err)
+	}
+
+	var results []SearchResult
+	doc.Find(".result").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		// Iklan ditandai result--ad
+		if s.HasClass("result--ad") {
+			return true
+		}
+
+		link := s.Find("a.result__a").First()
+		href := ddgResultUrl(link.AttrOr("href", ""))
+		if href == "" {
+			return true
+		}
+
+		results = append(results, SearchResult{
+			Title:       strings.TrimSpace(link.Text()),
+			Url:         href,
+			Description: strings.TrimSpace(s.Find(".result__snippet").First().Text()),
+		})
+		return len(results) < opts.Count
+	})
+
+	return results, nil
+}
+
+// ddgResultUrl ambil URL asli dari link redirect //duckduckgo.com/l/?uddg=...
+func ddgResultUrl(href string) string {
+	if href == "" {
+		return ""
+	}
+	if strings.HasPrefix(href, "//") {
+		href = "https:" + href
+	}
+
+	u, err := url.Parse(href)
+	if err != nil {
+		return ""
+	}
+	if strings.HasSuffix(u.Host, "duckduckgo.com") {
+		target := u.Query().Get("uddg")
+		if target == "" {
+			return ""
+		}
+		return target
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return href
+}
@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+type ollamaEmbedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type ollamaEmbedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+var embedClient = &http.Client{Timeout: 2 * time.Minute}
+
+// Cache embedding per isi teks, supaya judul blog lama tidak di-embed ulang setiap generate.
+// Dibatasi maxEmbedCache entri; entri paling lama dibuang dulu (FIFO).
+const maxEmbedCache = 5000
+
+var (
+	embedCacheMu   sync.Mutex
+	embedCache     = map[string][]float64{}
+	embedCacheKeys []string
+)
+
+// EmbeddingProvider dari setting embedding_provider / env EMBEDDING_PROVIDER:
+// "ollama" (default) atau "tfidf" untuk tidak memakai model embedding sama sekali
+func EmbeddingProvider() string {
+	if GetSetting("embedding_provider", os.Getenv("EMBEDDING_PROVIDER")) == "tfidf" {
+		return "tfidf"
+	}
+	return "ollama"
+}
+
+// EmbeddingModel model embedding Ollama, default nomic-embed-text
+func EmbeddingModel() string {
+	return GetSetting("embedding_model", getEnvDefault("OLLAMA_EMBED_MODEL", "nomic-embed-text"))
+}
+
+// EmbedTexts minta vektor embedding ke Ollama (/api/embed). Hasil urut sesuai texts.
+func EmbedTexts(texts []string) ([][]float64, error) {
+
+	model := EmbeddingModel()
+	vectors := make([][]float64, len(texts))
+
+	var missing []string
+	var missingIdx []int
+	embedCacheMu.Lock()
+	for i, text := range texts {
+		if v, ok := embedCache[model+"\x00"+text]; ok {
+			vectors[i] = v
+		} else {
+			missing = append(missing, text)
+			missingIdx = append(missingIdx, i)
+		}
+	}
+	embedCacheMu.Unlock()
+
+	if len(missing) == 0 {
+		return vectors, nil
+	}
+
+	embeddings, err := requestEmbeddings(model, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	embedCacheMu.Lock()
+	for j, v := range embeddings {
+		vectors[missingIdx[j]] = v
+		putEmbedCache(model+"\x00"+missing[j], v)
+	}
+	embedCacheMu.Unlock()
+
+	return vectors, nil
+}
+
+// putEmbedCache simpan satu vektor dan buang entri terlama kalau cache penuh; embedCacheMu harus sudah dikunci
+func putEmbedCache(key string, vector []float64) {
+	if _, ok := embedCache[key]; !ok {
+		embedCacheKeys = append(embedCacheKeys, key)
+	}
+	embedCache[key] = vector
+
+	if over := len(embedCacheKeys) - maxEmbedCache; over > 0 {
+		for _, old := range embedCacheKeys[:over] {
+			delete(embedCache, old)
+		}
+		embedCacheKeys = append([]string(nil), embedCacheKeys[over:]...)
+	}
+}
+
+// requestEmbeddings panggil /api/embed Ollama tanpa cache; dipakai langsung untuk teks panjang
+// atau sekali pakai (isi dokumen, query pencarian) supaya cache tidak membengkak
+func requestEmbeddings(model string, texts []string) ([][]float64, error) {
+
+	jsonBody, err := json.Marshal(ollamaEmbedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := embedClient.Post(getOllamaUrl()+"/api/embed", "application/json", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama embed returned status %d", resp.StatusCode)
+	}
+
+	var embedResp ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode ollama embed response: %v", err)
+	}
+	if len(embedResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d inputs", len(embedResp.Embeddings), len(texts))
+	}
+	return embedResp.Embeddings, nil
+}
+
+// CosineSimilarity dua vektor dense
+func CosineSimilarity(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// tfidfStopwords kata umum Indonesia/Inggris yang tidak membedakan topik
+var tfidfStopwords = map[string]bool{
+	"dan": true, "di": true, "ke": true, "dari": true, "yang": true, "untuk": true, "dengan": true,
+	"pada": true, "dalam": true, "ini": true, "itu": true, "atau": true, "cara": true, "apa": true,
+	"adalah": true, "sebagai": true, "lebih": true, "anda": true, "kamu": true, "tahun": true,
+	"the": true, "a": true, "an": true, "and": true, "of": true, "to": true, "in": true, "for": true,
+	"on": true, "with": true, "how": true, "what": true, "is": true,
+}
+
+// TfidfVectors vektor TF-IDF sparse untuk sekumpulan dokumen, IDF dihitung dari dokumen itu sendiri
+func TfidfVectors(docs []string) []map[string]float64 {
+
+	tokenized := make([][]string, len(docs))
+	df := map[string]int{}
+	for i, doc := range docs {
+		for _, w := range similarityWords(doc) {
+			if !tfidfStopwords[w] {
+				tokenized[i] = append(tokenized[i], w)
+			}
+		}
+		seen := map[string]bool{}
+		for _, w := range tokenized[i] {
+			if !seen[w] {
+				seen[w] = true
+				df[w]++
+			}
+		}
+	}
+
+	n := float64(len(docs))
+	vectors := make([]map[string]float64, len(docs))
+	for i, words := range tokenized {
+		tf := map[string]float64{}
+		for _, w := range words {
+			tf[w]++
+		}
+		vec := map[string]float64{}
+		for w, count := range tf {
+			// IDF halus supaya kata yang muncul di semua dokumen tidak jadi nol
+			idf := math.Log((1+n)/(1+float64(df[w]))) + 1
+			vec[w] = (count / float64(len(words))) * idf
+		}
+		vectors[i] = vec
+	}
+	return vectors
+}
+
+// SparseCosine cosine similarity untuk vektor TF-IDF
+func SparseCosine(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for k, v := range a {
+		na += v * v
+		if w, ok := b[k]; ok {
+			dot += v * w
+		}
+	}
+	for _, w := range b {
+		nb += w * w
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
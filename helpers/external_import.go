@@ -0,0 +1,321 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ExternalPost post hasil parse dari export platform lain (WordPress / Medium)
+type ExternalPost struct {
+	Title       string
+	Slug        string
+	Description string
+	Content     string // HTML
+	Status      string // pending | published
+	Tags        []string
+	CoverImage  string // URL remote, di-download saat import
+	PublishedAt time.Time
+	SourceUrl   string
+	Skipped     string // alasan kalau post tidak perlu di-import (trash, halaman, dll)
+}
+
+// ==================== WORDPRESS WXR ====================
+
+type wxrRss struct {
+	Channel struct {
+		Items []wxrItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrItem struct {
+	Title     string       `xml:"title"`
+	Link      string       `xml:"link"`
+	PubDate   string       `xml:"pubDate"`
+	Encoded   []wxrEncoded `xml:"encoded"`
+	PostId    string       `xml:"post_id"`
+	PostName  string       `xml:"post_name"`
+	PostType  string       `xml:"post_type"`
+	Status    string       `xml:"status"`
+	DateGmt   string       `xml:"post_date_gmt"`
+	Date      string       `xml:"post_date"`
+	AttachUrl string       `xml:"attachment_url"`
+	Category  []struct {
+		Domain   string `xml:"domain,attr"`
+		Nicename string `xml:"nicename,attr"`
+		Name     string `xml:",chardata"`
+	} `xml:"category"`
+	PostMeta []struct {
+		Key   string `xml:"meta_key"`
+		Value string `xml:"meta_value"`
+	} `xml:"postmeta"`
+}
+
+// content:encoded dan excerpt:encoded punya nama lokal sama, dibedakan dari namespace
+type wxrEncoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// ParseWordpressWXR parse file export WordPress (WXR) jadi daftar post
+func ParseWordpressWXR(data []byte) ([]ExternalPost, error) {
+
+	var rss wxrRss
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	if err := decoder.Decode(&rss); err != nil {
+		return nil, fmt.Errorf("invalid WXR file: %v", err)
+	}
+
+	// Kumpulkan URL attachment untuk featured image (_thumbnail_id → attachment_url)
+	attachments := map[string]string{}
+	for _, item := range rss.Channel.Items {
+		if item.PostType == "attachment" && item.AttachUrl != "" {
+			attachments[item.PostId] = item.AttachUrl
+		}
+	}
+
+	var posts []ExternalPost
+	for _, item := range rss.Channel.Items {
+		if item.PostType != "post" {
+			continue
+		}
+
+		post := ExternalPost{
+			Title:     strings.TrimSpace(item.Title),
+			Slug:      item.PostName,
+			SourceUrl: strings.TrimSpace(item.Link),
+		}
+
+		for _, enc := range item.Encoded {
+			if strings.Contains(enc.XMLName.Space, "excerpt") {
+				post.Description = strings.TrimSpace(enc.Value)
+			} else {
+				post.Content = wpAutoParagraph(enc.Value)
+			}
+		}
+
+		switch item.Status {
+		case "publish":
+			post.Status = "published"
+		case "trash":
+			post.Skipped = "post is in trash"
+		default:
+			// draft, pending, future, private → masuk antrian review
+			post.Status = "pending"
+		}
+
+		post.PublishedAt = parseWxrDate(item.DateGmt, item.Date, item.PubDate)
+
+		for _, cat := range item.Category {
+			if cat.Domain == "category" || cat.Domain == "post_tag" {
+				name := strings.TrimSpace(cat.Name)
+				if name != "" && !strings.EqualFold(name, "Uncategorized") {
+					post.Tags = append(post.Tags, name)
+				}
+			}
+		}
+
+		for _, meta := range item.PostMeta {
+			if meta.Key == "_thumbnail_id" {
+				post.CoverImage = attachments[meta.Value]
+			}
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+// parseWxrDate coba post_date_gmt dulu, lalu post_date, lalu pubDate RSS
+func parseWxrDate(gmt string, local string, pubDate string) time.Time {
+	if gmt != "" && gmt != "0000-00-00 00:00:00" {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", gmt, time.UTC); err == nil {
+			return t
+		}
+	}
+	if local != "" && local != "0000-00-00 00:00:00" {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", local, time.Local); err == nil {
+			return t
+		}
+	}
+	if t, err := time.Parse(time.RFC1123Z, strings.TrimSpace(pubDate)); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+var wpBlockTag = regexp.MustCompile(`^<(h[1-6]|p|ul|ol|li|pre|blockquote|table|figure|div|hr|img|iframe|!--)`)
+
+// wpAutoParagraph versi sederhana dari wpautop: konten WordPress klasik tidak pakai <p>,
+// paragraf dipisah baris kosong
+func wpAutoParagraph(content string) string {
+	content = strings.TrimSpace(strings.ReplaceAll(content, "\r\n", "\n"))
+	if content == "" || strings.Contains(content, "<p") || strings.Contains(content, "<!-- wp:") {
+		return content
+	}
+
+	var blocks []string
+	for _, block := range regexp.MustCompile(`\n\s*\n`).Split(content, -1) {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+		if wpBlockTag.MatchString(block) {
+			blocks = append(blocks, block)
+			continue
+		}
+		blocks = append(blocks, "<p>"+strings.ReplaceAll(block, "\n", "<br>\n")+"</p>")
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// ==================== MEDIUM ====================
+
+// ParseMediumExport parse zip export Medium (folder posts/*.html) jadi daftar post
+func ParseMediumExport(data []byte) ([]ExternalPost, error) {
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip file: %v", err)
+	}
+
+	var posts []ExternalPost
+	budget := int64(maxBundleTotal)
+	for _, file := range zr.File {
+		name := path.Base(file.Name)
+		if !strings.HasPrefix(file.Name, "posts/") || !strings.HasSuffix(name, ".html") {
+			continue
+		}
+
+		raw, err := readZipEntry(file, &budget)
+		if err != nil {
+			return nil, err
+		}
+
+		post, err := parseMediumPost(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+
+		// Draft di export Medium diawali "draft_"
+		if strings.HasPrefix(name, "draft_") {
+			post.Status = "pending"
+		}
+		if post.Slug == "" {
+			post.Slug = GenerateSlug(post.Title)
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+// parseMediumPost ambil judul, subtitle, isi, tanggal dan canonical URL dari satu file HTML Medium
+func parseMediumPost(raw []byte) (ExternalPost, error) {
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(raw))
+	if err != nil {
+		return ExternalPost{}, fmt.Errorf("failed to parse html: %v", err)
+	}
+
+	post := ExternalPost{Status: "published"}
+
+	post.Title = strings.TrimSpace(doc.Find("h1.p-name").First().Text())
+	if post.Title == "" {
+		post.Title = strings.TrimSpace(doc.Find("title").First().Text())
+	}
+
+	post.Description = strings.TrimSpace(doc.Find(`section[data-field="subtitle"]`).First().Text())
+
+	body := doc.Find(`section[data-field="body"]`).First()
+	// Medium mengulang judul & subtitle sebagai h3/h4 pertama di body
+	body.Find("h3.graf--title, h4.graf--subtitle").Remove()
+	// Buang class/atribut bawaan Medium supaya HTML-nya bersih
+	body.Find("*").Each(func(_ int, s *goquery.Selection) {
+		s.RemoveAttr("class")
+		s.RemoveAttr("name")
+		s.RemoveAttr("id")
+		s.RemoveAttr("data-image-id")
+		s.RemoveAttr("data-width")
+		s.RemoveAttr("data-height")
+	})
+	content, _ := body.Html()
+	post.Content = strings.TrimSpace(content)
+
+	if dt, ok := doc.Find("time.dt-published").First().Attr("datetime"); ok {
+		if t, err := time.Parse(time.RFC3339, dt); err == nil {
+			post.PublishedAt = t
+		}
+	}
+
+	if href, ok := doc.Find("a.p-canonical").First().Attr("href"); ok {
+		post.SourceUrl = href
+		// Slug Medium: "judul-artikel-1a2b3c4d5e6f" → buang hash di akhir
+		if u, err := url.Parse(href); err == nil {
+			slug := path.Base(u.Path)
+			if idx := strings.LastIndex(slug, "-"); idx > 0 && len(slug)-idx-1 >= 10 {
+				slug = slug[:idx]
+			}
+			post.Slug = GenerateSlug(slug)
+		}
+	}
+
+	if post.Title == "" {
+		return post, fmt.Errorf("post has no title")
+	}
+
+	return post, nil
+}
+
+// LocalizeImages download semua <img> remote di konten HTML ke uploads/, lalu ganti src-nya
+// Return konten baru dan jumlah gambar yang berhasil di-download
+func LocalizeImages(content string, folder string) (string, int) {
+
+	if !strings.Contains(content, "<img") {
+		return content, 0
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<body>" + content + "</body>"))
+	if err != nil {
+		return content, 0
+	}
+
+	downloaded := 0
+	doc.Find("img").Each(func(_ int, s *goquery.Selection) {
+		src, _ := s.Attr("src")
+		if !strings.HasPrefix(src, "http") {
+			return
+		}
+		filePath, err := DownloadImage(src, folder)
+		if err != nil {
+			return
+		}
+		s.SetAttr("src", GetFileUrl(filePath))
+		// srcset masih menunjuk ke server lama
+		s.RemoveAttr("srcset")
+		s.RemoveAttr("sizes")
+		downloaded++
+	})
+
+	html, err := doc.Find("body").Html()
+	if err != nil {
+		return content, 0
+	}
+	return strings.TrimSpace(html), downloaded
+}
+
+// CountRemoteImages hitung <img> remote di konten (untuk laporan dry run)
+func CountRemoteImages(content string) int {
+	return len(regexp.MustCompile(`<img[^>]+src=["']https?://`).FindAllString(content, -1))
+}
@@ -0,0 +1,293 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/transform"
+)
+
+// FetchOptions pengaturan per request; nilai kosong pakai default dari env
+type FetchOptions struct {
+	Accept   string
+	MaxBytes int64
+	Timeout  time.Duration
+	// SkipRobots untuk request atas nama user (bukan crawling), misal verifikasi webmention
+	SkipRobots bool
+	// NoCache selalu ambil dari jaringan dan tidak menyimpan ke cache
+	NoCache bool
+}
+
+// FetchResponse hasil Fetch. Body sudah dikonversi ke UTF-8 kalau kontennya teks.
+type FetchResponse struct {
+	Url         string
+	StatusCode  int
+	Header      http.Header
+	ContentType string
+	Body        []byte
+	FromCache   bool
+}
+
+// ErrRobotsDisallowed url dilarang oleh robots.txt situsnya
+var ErrRobotsDisallowed = errors.New("blocked by robots.txt")
+
+// FetchTooLargeError response melebihi batas ukuran
+type FetchTooLargeError struct {
+	Limit int64
+}
+
+func (e *FetchTooLargeError) Error() string {
+	return fmt.Sprintf("response is larger than %d bytes", e.Limit)
+}
+
+const (
+	defaultFetchMaxBytes = 5 << 20
+	defaultFetchTimeout  = 15 * time.Second
+	maxFetchRedirects    = 10
+)
+
+// FetchUserAgent user agent jujur (FETCH_USER_AGENT), default ArlchooseBot dengan URL info
+func FetchUserAgent() string {
+	if ua := os.Getenv("FETCH_USER_AGENT"); ua != "" {
+		return ua
+	}
+	return "ArlchooseBot/1.0 (+" + GetBaseUrl() + ")"
+}
+
+// fetchBotName token user agent untuk dicocokkan dengan grup User-agent di robots.txt
+func fetchBotName() string {
+	name, _, _ := strings.Cut(FetchUserAgent(), "/")
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Fetch GET url dengan robots.txt, batas per host, batas ukuran, deteksi charset dan cache ETag/Last-Modified
+func Fetch(rawUrl string, opts FetchOptions) (*FetchResponse, error) {
+
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid url: %s", rawUrl)
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = fetchMaxBytes()
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultFetchTimeout
+	}
+
+	if !opts.SkipRobots {
+		if err := checkRobots(u); err != nil {
+			return nil, err
+		}
+	}
+
+	var cached *fetchCacheEntry
+	if !opts.NoCache {
+		cached = loadFetchCache(rawUrl)
+		if cached != nil && int64(len(cached.Body)) > opts.MaxBytes {
+			return nil, &FetchTooLargeError{Limit: opts.MaxBytes}
+		}
+		if cached != nil && cached.Fresh() {
+			return cached.Response(), nil
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("User-Agent", FetchUserAgent())
+	req.Header.Set("Accept", firstNonEmpty(opts.Accept, "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"))
+	req.Header.Set("Accept-Language", "id-ID,id;q=0.9,en-US;q=0.8,en;q=0.7")
+	if cached != nil {
+		if etag := cached.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	// Slot host dipegang sampai body selesai dibaca. Saat redirect, slot host sebelumnya dilepas lalu
+	// host tujuan ikut antre, jadi hanya satu slot yang dipegang sekaligus.
+	release := acquireHost(u.Host)
+	defer func() { release() }()
+
+	client := &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: fetchTransport,
+		CheckRedirect: func(next *http.Request, via []*http.Request) error {
+			if len(via) >= maxFetchRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxFetchRedirects)
+			}
+			if !opts.SkipRobots {
+				if err := checkRobots(next.URL); err != nil {
+					return err
+				}
+			}
+			release()
+			release = acquireHost(next.URL.Host)
+			return nil
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrRobotsDisallowed) {
+			return nil, ErrRobotsDisallowed
+		}
+		return nil, fmt.Errorf("failed to fetch url: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		cached.Revalidated(resp.Header)
+		storeFetchCache(cached)
+		return cached.Response(), nil
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, opts.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	if int64(len(raw)) > opts.MaxBytes {
+		return nil, &FetchTooLargeError{Limit: opts.MaxBytes}
+	}
+
+	entry := &fetchCacheEntry{
+		RequestUrl: rawUrl,
+		Url:        resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		StoredAt:   time.Now(),
+		Body:       raw,
+	}
+	if !opts.NoCache && resp.StatusCode == http.StatusOK && cacheable(resp.Header) {
+		storeFetchCache(entry)
+	}
+
+	return entry.Response(), nil
+}
+
+// decodeBody ubah body teks ke UTF-8 berdasarkan header Content-Type atau meta charset.
+// Body selain teks (gambar, pdf, dll) dikembalikan apa adanya.
+func decodeBody(raw []byte, contentType string) []byte {
+	// Tanpa header, jenis konten ditebak dari isinya; hasil tebakan hanya dipakai untuk memutuskan,
+	// charset tetap dideteksi dari body
+	sniffed := contentType
+	if sniffed == "" {
+		sniffed = http.DetectContentType(raw)
+	}
+	if !isTextContentType(sniffed) {
+		return raw
+	}
+
+	enc, name, _ := charset.DetermineEncoding(raw, contentType)
+	if name == "utf-8" {
+		return raw
+	}
+	decoded, _, err := transform.Bytes(enc.NewDecoder(), raw)
+	if err != nil {
+		return raw
+	}
+	return decoded
+}
+
+// isTextContentType text/*, HTML, XML dan JSON
+func isTextContentType(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	return strings.HasPrefix(ct, "text/") || strings.Contains(ct, "html") || strings.Contains(ct, "xml") || strings.Contains(ct, "json")
+}
+
+func cacheable(header http.Header) bool {
+	cc := strings.ToLower(header.Get("Cache-Control"))
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
+
+func fetchMaxBytes() int64 {
+	if n, err := strconv.ParseInt(os.Getenv("FETCH_MAX_BYTES"), 10, 64); err == nil && n > 0 {
+		return n
+	}
+	return defaultFetchMaxBytes
+}
+
+// hostLimiter batasi jumlah request bersamaan dan jarak antar request ke satu host
+type hostLimiter struct {
+	sem  chan struct{}
+	mu   sync.Mutex
+	next time.Time
+}
+
+var (
+	hostLimitersMu sync.Mutex
+	hostLimiters   = map[string]*hostLimiter{}
+)
+
+// fetchHostDelay jarak minimum antar request ke host yang sama (FETCH_HOST_DELAY_MS, default 1000),
+// diperbesar kalau robots.txt minta Crawl-delay
+func fetchHostDelay(host string) time.Duration {
+	delay := time.Second
+	if ms, err := strconv.Atoi(os.Getenv("FETCH_HOST_DELAY_MS")); err == nil && ms >= 0 {
+		delay = time.Duration(ms) * time.Millisecond
+	}
+	if crawlDelay := robotsCrawlDelay(host); crawlDelay > delay {
+		delay = crawlDelay
+	}
+	return delay
+}
+
+// acquireHost tunggu giliran untuk host, kembalikan fungsi untuk melepas slot
+func acquireHost(host string) func() {
+	hostLimitersMu.Lock()
+	l, ok := hostLimiters[host]
+	if !ok {
+		concurrency := 2
+		if n, err := strconv.Atoi(os.Getenv("FETCH_HOST_CONCURRENCY")); err == nil && n > 0 {
+			concurrency = n
+		}
+		l = &hostLimiter{sem: make(chan struct{}, concurrency)}
+		hostLimiters[host] = l
+	}
+	hostLimitersMu.Unlock()
+
+	l.sem <- struct{}{}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(fetchHostDelay(host))
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(slot))
+	return func() { <-l.sem }
+}
+
+// Response salinan entry sebagai FetchResponse dengan body sudah di-decode
+func (e *fetchCacheEntry) Response() *FetchResponse {
+	contentType := e.Header.Get("Content-Type")
+	return &FetchResponse{
+		Url:         e.Url,
+		StatusCode:  e.StatusCode,
+		Header:      e.Header,
+		ContentType: contentType,
+		Body:        decodeBody(e.Body, contentType),
+		FromCache:   e.fromCache,
+	}
+}
+
+// Reader body sebagai io.Reader
+func (r *FetchResponse) Reader() io.Reader {
+	return bytes.NewReader(r.Body)
+}
@@ -0,0 +1,208 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fetchCacheEntry satu response di cache disk: <dir>/<2 huruf hash>/<hash>.json (+ .body)
+type fetchCacheEntry struct {
+	RequestUrl string      `json:"request_url"`
+	Url        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	StoredAt   time.Time   `json:"stored_at"`
+	// BodyHash sha256 body, supaya .json dan .body dari dua response berbeda tidak dipakai berpasangan
+	BodyHash  string `json:"body_hash"`
+	Body      []byte `json:"-"`
+	fromCache bool
+}
+
+func fetchCacheDir() string {
+	return getEnvDefault("FETCH_CACHE_DIR", "storage/fetch-cache")
+}
+
+func fetchCacheEnabled() bool {
+	return os.Getenv("FETCH_CACHE_DISABLED") != "true"
+}
+
+func fetchCachePath(rawUrl string) string {
+	sum := sha256.Sum256([]byte(rawUrl))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(fetchCacheDir(), key[:2], key)
+}
+
+func loadFetchCache(rawUrl string) *fetchCacheEntry {
+	if !fetchCacheEnabled() {
+		return nil
+	}
+
+	path := fetchCachePath(rawUrl)
+	meta, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return nil
+	}
+	var entry fetchCacheEntry
+	if json.Unmarshal(meta, &entry) != nil || entry.RequestUrl != rawUrl {
+		return nil
+	}
+	body, err := os.ReadFile(path + ".body")
+	if err != nil || entry.BodyHash != fetchBodyHash(body) {
+		return nil
+	}
+	entry.Body = body
+	entry.fromCache = true
+	return &entry
+}
+
+func storeFetchCache(entry *fetchCacheEntry) {
+	if !fetchCacheEnabled() {
+		return
+	}
+
+	path := fetchCachePath(entry.RequestUrl)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	entry.BodyHash = fetchBodyHash(entry.Body)
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Fetch paralel untuk url yang sama bisa menulis bergantian; pasangan yang tertukar ditolak saat load
+	if writeFileAtomic(path+".body", entry.Body, 0644) != nil {
+		return
+	}
+	writeFileAtomic(path+".json", meta, 0644)
+}
+
+func fetchBodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// StartFetchCacheSweeper bersihkan cache fetch di disk setiap jam supaya folder cache tidak tumbuh terus
+func StartFetchCacheSweeper() {
+	if !fetchCacheEnabled() {
+		return
+	}
+	go func() {
+		for {
+			if removed := SweepFetchCache(); removed > 0 {
+				log.Printf("[FETCH CACHE] removed %d entries", removed)
+			}
+			time.Sleep(time.Hour)
+		}
+	}()
+}
+
+// SweepFetchCache hapus entry yang lebih tua dari FETCH_CACHE_MAX_AGE_HOURS (default 168), lalu entry
+// tertua sampai total ukuran body di bawah FETCH_CACHE_MAX_MB (default 500). Umur dari waktu file ditulis.
+func SweepFetchCache() int {
+
+	maxAge := 7 * 24 * time.Hour
+	if hours, err := strconv.Atoi(os.Getenv("FETCH_CACHE_MAX_AGE_HOURS")); err == nil && hours > 0 {
+		maxAge = time.Duration(hours) * time.Hour
+	}
+	maxBytes := int64(500 << 20)
+	if mb, err := strconv.ParseInt(os.Getenv("FETCH_CACHE_MAX_MB"), 10, 64); err == nil && mb > 0 {
+		maxBytes = mb << 20
+	}
+
+	type cacheFile struct {
+		base    string
+		size    int64
+		written time.Time
+	}
+	var files []cacheFile
+	var total int64
+	filepath.WalkDir(fetchCacheDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		// File sementara sisa proses yang mati di tengah penulisan
+		if strings.HasSuffix(path, ".tmp") {
+			if info, err := d.Info(); err == nil && time.Since(info.ModTime()) > time.Hour {
+				os.Remove(path)
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".body") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			files = append(files, cacheFile{strings.TrimSuffix(path, ".body"), info.Size(), info.ModTime()})
+			total += info.Size()
+		}
+		return nil
+	})
+
+	// Tertua dulu: berhenti begitu entry masih muda dan total sudah di bawah batas
+	sort.Slice(files, func(i, j int) bool { return files[i].written.Before(files[j].written) })
+	removed := 0
+	for _, f := range files {
+		if time.Since(f.written) < maxAge && total <= maxBytes {
+			break
+		}
+		os.Remove(f.base + ".json")
+		os.Remove(f.base + ".body")
+		total -= f.size
+		removed++
+	}
+	return removed
+}
+
+// Fresh true kalau entry masih boleh dipakai tanpa bertanya ke server.
+// Urutan: Cache-Control max-age, Expires, heuristik 10% umur Last-Modified,
+// lalu FETCH_CACHE_TTL (detik, default 3600) untuk halaman tanpa validator sama sekali.
+func (e *fetchCacheEntry) Fresh() bool {
+	age := time.Since(e.StoredAt)
+	cc := strings.ToLower(e.Header.Get("Cache-Control"))
+
+	if strings.Contains(cc, "no-cache") {
+		return false
+	}
+	for _, directive := range strings.Split(cc, ",") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age="); ok {
+			if seconds, err := strconv.Atoi(value); err == nil {
+				return age < time.Duration(seconds)*time.Second
+			}
+		}
+	}
+	if expires, err := http.ParseTime(e.Header.Get("Expires")); err == nil {
+		return time.Now().Before(expires)
+	}
+	if modified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil {
+		return age < min(e.StoredAt.Sub(modified)/10, 24*time.Hour)
+	}
+	if e.Header.Get("ETag") != "" {
+		return false
+	}
+
+	ttl := time.Hour
+	if seconds, err := strconv.Atoi(os.Getenv("FETCH_CACHE_TTL")); err == nil && seconds >= 0 {
+		ttl = time.Duration(seconds) * time.Second
+	}
+	return age < ttl
+}
+
+// Revalidated server membalas 304: perbarui header validator dan waktu simpan
+func (e *fetchCacheEntry) Revalidated(header http.Header) {
+	for _, key := range []string{"ETag", "Last-Modified", "Cache-Control", "Expires", "Date"} {
+		if value := header.Get(key); value != "" {
+			e.Header.Set(key, value)
+		}
+	}
+	e.StoredAt = time.Now()
+}
@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress url mengarah ke alamat internal (loopback, jaringan privat, link-local, metadata cloud)
+var ErrPrivateAddress = errors.New("address is not publicly routable")
+
+// Rentang khusus yang tidak tercakup netip.Addr.IsPrivate/IsLoopback
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// fetchTransport transport untuk semua request ke URL dari luar (fetch, robots.txt, webmention).
+// Alamat dicek di level dial setelah DNS di-resolve, jadi redirect & DNS rebinding ikut tertahan.
+// Proxy sengaja tidak dipakai karena koneksi lewat proxy melewati cek ini.
+var fetchTransport = &http.Transport{
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   denyPrivateAddress,
+	}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+// denyPrivateAddress Control func net.Dialer; FETCH_ALLOW_PRIVATE=true mematikan cek (development lokal)
+func denyPrivateAddress(network string, address string, _ syscall.RawConn) error {
+	if os.Getenv("FETCH_ALLOW_PRIVATE") == "true" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	if !IsPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, addr)
+	}
+	return nil
+}
+
+// IsPublicAddr apakah alamat IP boleh dihubungi atas nama input dari luar
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
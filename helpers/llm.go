@@ -0,0 +1,578 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task LLM — provider & model bisa diatur per task lewat settings
+const (
+	LLMTaskTitles     = "titles"
+	LLMTaskContent    = "content"
+	LLMTaskTags       = "tags"
+	LLMTaskRegenerate = "regenerate"
+	LLMTaskSeo        = "seo"
+	LLMTaskAsk        = "ask"
+	LLMTaskSection    = "section"
+)
+
+// LLMRequest satu prompt ke LLM
+type LLMRequest struct {
+	Task   string
+	Model  string
+	Prompt string
+	// Context membatalkan request ke provider, misal saat client SSE menutup koneksi; nil = tanpa batas
+	Context context.Context
+}
+
+func (r LLMRequest) context() context.Context {
+	if r.Context == nil {
+		return context.Background()
+	}
+	return r.Context
+}
+
+// LLMResponse hasil generate beserta jumlah token dan durasi kalau provider melaporkannya
+type LLMResponse struct {
+	Text             string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	Duration         time.Duration
+}
+
+// LLMProvider backend LLM (Ollama, server OpenAI-compatible, fake)
+type LLMProvider interface {
+	Name() string
+	DefaultModel() string
+	Generate(req LLMRequest) (LLMResponse, error)
+}
+
+// LLMStreamer provider yang bisa mengirim jawaban sedikit demi sedikit
+type LLMStreamer interface {
+	GenerateStream(req LLMRequest, onChunk func(chunk string)) (LLMResponse, error)
+}
+
+// llmClient timeout panjang karena model lokal bisa butuh beberapa menit untuk artikel panjang
+var llmClient = &http.Client{Timeout: 10 * time.Minute}
+
+// GetLLMProvider buat provider berdasarkan nama: "ollama", "openai", atau "fake"
+func GetLLMProvider(name string) (LLMProvider, error) {
+	switch name {
+	case "", "ollama":
+		return &OllamaProvider{BaseUrl: getOllamaUrl(), Model: getOllamaModel()}, nil
+	case "openai":
+		return &OpenAIProvider{
+			BaseUrl: getEnvDefault("OPENAI_BASE_URL", "http://localhost:8080/v1"),
+			ApiKey:  os.Getenv("OPENAI_API_KEY"),
+			Model:   os.Getenv("OPENAI_MODEL"),
+		}, nil
+	case "fake":
+		return &FakeProvider{}, nil
+	}
+	return nil, fmt.Errorf("unknown llm provider: %s", name)
+}
+
+// ResolveLLM pilih provider & model untuk task.
+// Urutan: setting llm_provider_<task> / llm_model_<task>, lalu llm_provider / llm_model, lalu env LLM_PROVIDER.
+func ResolveLLM(task string) (LLMProvider, string, error) {
+	providerName := GetSetting("llm_provider_"+task, GetSetting("llm_provider", os.Getenv("LLM_PROVIDER")))
+	provider, err := GetLLMProvider(providerName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	model := GetSetting("llm_model_"+task, GetSetting("llm_model", ""))
+	if model == "" {
+		model = provider.DefaultModel()
+	}
+	return provider, model, nil
+}
+
+// AskLLM kirim prompt untuk task tertentu ke provider yang dikonfigurasi.
+// Token dan durasi setiap panggilan dicatat ke llm_usages dengan ref sebagai tautannya.
+func AskLLM(task string, prompt string, ref LLMUsageRef) (string, error) {
+	provider, model, err := ResolveLLM(task)
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	resp, err := provider.Generate(LLMRequest{Task: task, Model: model, Prompt: prompt})
+	measure(&resp, start)
+	recordLLMUsage(task, provider.Name(), model, resp, err, ref)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", provider.Name(), err)
+	}
+	return resp.Text, nil
+}
+
+// AskLLMStream sama seperti AskLLM tapi memanggil onChunk untuk setiap potongan jawaban.
+// Provider yang tidak mendukung streaming memanggil onChunk sekali dengan jawaban lengkap.
+// ctx dibatalkan = request ke provider dihentikan.
+func AskLLMStream(ctx context.Context, task string, prompt string, ref LLMUsageRef, onChunk func(chunk string)) (string, error) {
+	provider, model, err := ResolveLLM(task)
+	if err != nil {
+		return "", err
+	}
+
+	req := LLMRequest{Task: task, Model: model, Prompt: prompt, Context: ctx}
+
+	start := time.Now()
+	var resp LLMResponse
+	if streamer, ok := provider.(LLMStreamer); ok {
+		resp, err = streamer.GenerateStream(req, onChunk)
+	} else {
+		resp, err = provider.Generate(req)
+		if err == nil {
+			onChunk(resp.Text)
+		}
+	}
+	measure(&resp, start)
+	recordLLMUsage(task, provider.Name(), model, resp, err, ref)
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", provider.Name(), err)
+	}
+	return resp.Text, nil
+}
+
+// newLineScanner scanner baris dengan buffer besar, satu baris NDJSON/SSE bisa panjang
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	return scanner
+}
+
+// ==================== OLLAMA ====================
+
+// OllamaProvider pakai endpoint /api/generate milik Ollama
+type OllamaProvider struct {
+	BaseUrl string
+	Model   string
+}
+
+func (p *OllamaProvider) Name() string         { return "ollama" }
+func (p *OllamaProvider) DefaultModel() string { return p.Model }
+
+func (p *OllamaProvider) Generate(req LLMRequest) (LLMResponse, error) {
+
+	reqBody := OllamaRequest{
+		Model:  req.Model,
+		Prompt: req.Prompt,
+		Stream: false,
+	}
+
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return LLMResponse{}, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := p.post(req.context(), jsonBody)
+	if err != nil {
+		return LLMResponse{}, fmt.Errorf("failed to connect to ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return LLMResponse{}, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	var ollamaResp OllamaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
+		return LLMResponse{}, fmt.Errorf("failed to decode ollama response: %v", err)
+	}
+
+	return LLMResponse{
+		Text:             ollamaResp.Response,
+		Model:            req.Model,
+		PromptTokens:     ollamaResp.PromptEvalCount,
+		CompletionTokens: ollamaResp.EvalCount,
+		Duration:         time.Duration(ollamaResp.TotalDuration),
+	}, nil
+}
+
+func (p *OllamaProvider) post(ctx context.Context, jsonBody []byte) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseUrl+"/api/generate", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	return llmClient.Do(httpReq)
+}
+
+// GenerateStream baca response NDJSON Ollama (stream: true), satu objek JSON per baris
+func (p *OllamaProvider) GenerateStream(req LLMRequest, onChunk func(chunk string)) (LLMResponse, error) {
+
+	jsonBody, err := json.Marshal(OllamaRequest{
+		Model:  req.Model,
+		Prompt: req.Prompt,
+		Stream: true,
+	})
+	if err != nil {
+		return LLMResponse{}, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	resp, err := p.post(req.context(), jsonBody)
+	if err != nil {
+		return LLMResponse{}, fmt.Errorf("failed to connect to ollama: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return LLMResponse{}, fmt.Errorf("ollama returned status %d", resp.StatusCode)
+	}
+
+	result := LLMResponse{Model: req.Model}
+	var text strings.Builder
+
+	scanner := newLineScanner(resp.Body)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var chunk OllamaStreamChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return result, fmt.Errorf("failed to decode ollama stream: %v", err)
+		}
+		if chunk.Error != "" {
+			return result, fmt.Errorf("ollama error: %s", chunk.Error)
+		}
+
+		if chunk.Response != "" {
+			text.WriteString(chunk.Response)
+			onChunk(chunk.Response)
+		}
+		if chunk.Done {
+			result.PromptTokens = chunk.PromptEvalCount
+			result.CompletionTokens = chunk.EvalCount
+			result.Duration = time.Duration(chunk.TotalDuration)
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("ollama stream interrupted: %v", err)
+	}
+
+	result.Text = text.String()
+	return result, nil
+}
+
+// ==================== OPENAI-COMPATIBLE ====================
+
+// OpenAIProvider untuk server chat-completions yang kompatibel OpenAI (llama.cpp server, vLLM, LM Studio, dll.)
+type OpenAIProvider struct {
+	BaseUrl string
+	ApiKey  string
+	Model   string
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model         string               `json:"model"`
+	Messages      []openAIChatMessage  `json:"messages"`
+	Stream        bool                 `json:"stream"`
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type openAIStreamChunk struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *OpenAIProvider) Name() string         { return "openai" }
+func (p *OpenAIProvider) DefaultModel() string { return p.Model }
+
+// chatUrl terima base URL dengan atau tanpa /v1
+func (p *OpenAIProvider) chatUrl() string {
+	base := strings.TrimSuffix(p.BaseUrl, "/")
+	if !strings.HasSuffix(base, "/v1") {
+		base += "/v1"
+	}
+	return base + "/chat/completions"
+}
+
+// post kirim chat request ke server
+func (p *OpenAIProvider) post(ctx context.Context, body openAIChatRequest) (*http.Response, error) {
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.chatUrl(), bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.ApiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.ApiKey)
+	}
+
+	resp, err := llmClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to llm server: %v", err)
+	}
+	return resp, nil
+}
+
+func (p *OpenAIProvider) Generate(req LLMRequest) (LLMResponse, error) {
+
+	resp, err := p.post(req.context(), openAIChatRequest{
+		Model:    req.Model,
+		Messages: []openAIChatMessage{{Role: "user", Content: req.Prompt}},
+	})
+	if err != nil {
+		return LLMResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var chatResp openAIChatResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&chatResp)
+
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && chatResp.Error != nil {
+			return LLMResponse{}, fmt.Errorf("llm server returned status %d: %s", resp.StatusCode, chatResp.Error.Message)
+		}
+		return LLMResponse{}, fmt.Errorf("llm server returned status %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return LLMResponse{}, fmt.Errorf("failed to decode llm response: %v", decodeErr)
+	}
+	if len(chatResp.Choices) == 0 {
+		return LLMResponse{}, fmt.Errorf("llm server returned no choices")
+	}
+
+	model := chatResp.Model
+	if model == "" {
+		model = req.Model
+	}
+
+	return LLMResponse{
+		Text:             chatResp.Choices[0].Message.Content,
+		Model:            model,
+		PromptTokens:     chatResp.Usage.PromptTokens,
+		CompletionTokens: chatResp.Usage.CompletionTokens,
+	}, nil
+}
+
+// GenerateStream baca server-sent events "data: {...}" sampai "data: [DONE]"
+func (p *OpenAIProvider) GenerateStream(req LLMRequest, onChunk func(chunk string)) (LLMResponse, error) {
+
+	resp, err := p.post(req.context(), openAIChatRequest{
+		Model:         req.Model,
+		Messages:      []openAIChatMessage{{Role: "user", Content: req.Prompt}},
+		Stream:        true,
+		StreamOptions: &openAIStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return LLMResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return LLMResponse{}, fmt.Errorf("llm server returned status %d", resp.StatusCode)
+	}
+
+	result := LLMResponse{Model: req.Model}
+	var text strings.Builder
+
+	scanner := newLineScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return result, fmt.Errorf("failed to decode llm stream: %v", err)
+		}
+		if chunk.Model != "" {
+			result.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			result.PromptTokens = chunk.Usage.PromptTokens
+			result.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				text.WriteString(choice.Delta.Content)
+				onChunk(choice.Delta.Content)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("llm stream interrupted: %v", err)
+	}
+
+	result.Text = text.String()
+	return result, nil
+}
+
+// ==================== FAKE ====================
+
+// FakeProvider jawaban deterministik per task, tanpa jaringan — untuk development dan testing.
+// Responses bisa diisi untuk memaksa jawaban tertentu per task.
+type FakeProvider struct {
+	Responses map[string]string
+}
+
+var fakeTotalPattern = regexp.MustCompile(`Buat (\d+) judul`)
+
+func (p *FakeProvider) Name() string         { return "fake" }
+func (p *FakeProvider) DefaultModel() string { return "fake" }
+
+func (p *FakeProvider) Generate(req LLMRequest) (LLMResponse, error) {
+	text, ok := p.Responses[req.Task]
+	if !ok {
+		text = fakeResponse(req)
+	}
+	return LLMResponse{
+		Text:             text,
+		Model:            "fake",
+		PromptTokens:     len(strings.Fields(req.Prompt)),
+		CompletionTokens: len(strings.Fields(text)),
+	}, nil
+}
+
+// GenerateStream kirim jawaban fake per kata
+func (p *FakeProvider) GenerateStream(req LLMRequest, onChunk func(chunk string)) (LLMResponse, error) {
+	resp, err := p.Generate(req)
+	if err != nil {
+		return resp, err
+	}
+	for _, word := range strings.SplitAfter(resp.Text, " ") {
+		if err := req.context().Err(); err != nil {
+			return resp, err
+		}
+		onChunk(word)
+	}
+	return resp, nil
+}
+
+// fakeArticleSections isi artikel provider fake: heading lalu paragraf. Cukup panjang dan hanya memakai
+// tag yang diizinkan supaya lolos quality gate dengan setting default (minimal 400 kata).
+var fakeArticleSections = [][]string{
+	{
+		"Pendahuluan",
+		"Artikel contoh {seed} ini ditulis oleh provider fake supaya seluruh alur pembuatan blog bisa diuji tanpa model bahasa sungguhan. Isinya sengaja panjang dan rapi, karena draft yang terlalu pendek akan ditolak oleh pemeriksaan kualitas sebelum disimpan sebagai blog baru yang menunggu review.",
+		"Setiap prompt yang sama akan selalu menghasilkan jawaban yang sama. Dengan begitu hasil pengujian bisa dibandingkan dari waktu ke waktu, dan perubahan pada parser, pemeriksaan kualitas, atau penyimpanan sumber langsung terlihat kalau ada yang berbeda dari biasanya.",
+	},
+	{
+		"Konsep Dasar",
+		"Sebelum mulai, ada baiknya kita memahami bagaimana sebuah artikel dibuat di sistem ini. Judul dipilih lebih dulu, lalu sumber referensi dikumpulkan dari mesin pencari, kemudian model diminta menulis artikel dalam HTML dengan sitasi bernomor yang merujuk ke sumber tersebut [1].",
+		"Setelah draft selesai, sistem akan memeriksa apakah deskripsi tersedia, apakah konten memakai tag yang diizinkan, apakah tidak ada sisa markdown, dan apakah panjang serta bahasanya sudah sesuai. Draft yang lolos akan disimpan, sedangkan draft yang gagal akan ditulis ulang dengan instruksi koreksi.",
+	},
+	{
+		"Langkah Praktis",
+		"Langkah pertama adalah menyiapkan konfigurasi provider. Untuk pengujian lokal cukup isi variabel lingkungan dengan nilai fake, sehingga tidak ada permintaan ke server model bahasa dan tidak ada biaya yang muncul. Semua panggilan tetap dicatat di tabel pemakaian seperti provider lainnya.",
+		"Langkah kedua adalah menjalankan job pembuatan artikel seperti biasa dari dashboard. Worker akan mengambil job, membuat judul, mencari referensi, menulis artikel, lalu memberi tag dan metadata SEO. Kita bisa memantau setiap langkah lewat stream yang dikirim ke browser secara langsung.",
+		"Langkah ketiga adalah memeriksa hasilnya. Blog yang dibuat oleh provider fake akan berstatus pending, sama seperti blog dari model sungguhan, sehingga proses review, publikasi, dan penolakan bisa dicoba tanpa takut mengganggu konten yang sudah ada di situs.",
+	},
+	{
+		"Memantau Hasil",
+		"Selama job berjalan, setiap potongan teks dikirim ke dashboard sehingga penulis bisa melihat artikel terbentuk sedikit demi sedikit. Kalau koneksi terputus, draft sementara tetap tersimpan di database dan bisa dilanjutkan tanpa harus mengulang dari awal.",
+		"Statistik pemakaian juga bisa dibuka untuk melihat berapa kali setiap tugas dipanggil, berapa lama waktunya, dan berapa banyak yang gagal. Untuk provider fake jumlah token dihitung dari jumlah kata, jadi angka yang muncul memang hanya perkiraan kasar.",
+	},
+	{
+		"Kesalahan Umum",
+		"Kesalahan yang sering terjadi adalah lupa mengganti provider kembali setelah pengujian selesai. Akibatnya artikel yang dibuat di server produksi hanya berisi teks contoh. Pastikan konfigurasi di setiap lingkungan sudah benar sebelum menjadwalkan pembuatan artikel secara otomatis.",
+		"Kesalahan lain adalah mengubah batas minimal kata menjadi terlalu tinggi. Provider fake hanya menulis sekitar lima ratus kata, jadi batas yang lebih tinggi dari itu akan membuat setiap percobaan gagal di langkah penulisan. Sesuaikan batas tersebut dengan kebutuhan pengujian yang sedang dilakukan.",
+	},
+	{
+		"Kesimpulan",
+		"Provider fake membantu kita menguji seluruh pipeline dengan cepat dan murah. Hasilnya deterministik, lolos pemeriksaan kualitas dengan pengaturan bawaan, dan tetap melewati langkah yang sama dengan model sungguhan. Dengan begitu setiap perubahan pada sistem bisa diuji lebih dulu sebelum dipakai untuk menulis artikel yang akan dibaca oleh pengunjung.",
+	},
+}
+
+// fakeResponse jawaban dengan format yang sama seperti yang diharapkan parser tiap task
+func fakeResponse(req LLMRequest) string {
+	sum := sha256.Sum256([]byte(req.Prompt))
+	seed := hex.EncodeToString(sum[:])[:8]
+
+	switch req.Task {
+	case LLMTaskTitles:
+		total := 3
+		if m := fakeTotalPattern.FindStringSubmatch(req.Prompt); m != nil {
+			total, _ = strconv.Atoi(m[1])
+		}
+		var lines []string
+		for i := 1; i <= total; i++ {
+			lines = append(lines, fmt.Sprintf("Panduan Praktis Topik %s Bagian %d", seed, i))
+		}
+		return strings.Join(lines, "\n")
+
+	case LLMTaskTags:
+		return "teknologi\ntutorial\nfake-" + seed[:4]
+
+	case LLMTaskAsk:
+		return fmt.Sprintf("Ini jawaban contoh %s dari provider fake berdasarkan sumber portfolio [1].", seed)
+
+	case LLMTaskSection:
+		return fmt.Sprintf("<p>Bagian ini ditulis ulang oleh provider fake %s sesuai instruksi.</p>", seed)
+
+	case LLMTaskSeo:
+		return fmt.Sprintf(`{"meta_title": "Panduan Contoh %s", "meta_description": "Ringkasan artikel contoh %s yang dibuat oleh provider fake untuk pengujian metadata SEO.", "focus_keyword": "artikel contoh", "faq": [{"question": "Apa itu artikel contoh %s?", "answer": "Artikel deterministik dari provider fake."}]}`, seed, seed, seed)
+
+	case LLMTaskContent, LLMTaskRegenerate:
+		var b strings.Builder
+		fmt.Fprintf(&b, "---DESCRIPTION---\nArtikel contoh %s yang dibuat oleh provider fake untuk pengujian pipeline.\n---CONTENT---\n", seed)
+		for _, section := range fakeArticleSections {
+			fmt.Fprintf(&b, "<h2>%s</h2>\n", section[0])
+			for _, paragraph := range section[1:] {
+				fmt.Fprintf(&b, "<p>%s</p>\n", strings.ReplaceAll(paragraph, "{seed}", seed))
+			}
+		}
+		return strings.TrimSpace(b.String())
+	}
+
+	return "fake response " + seed
+}
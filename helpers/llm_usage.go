@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"log"
+	"time"
+
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/models"
+)
+
+// LLMUsageRef job/task/blog yang memicu panggilan LLM, untuk metering (semua boleh nil)
+type LLMUsageRef struct {
+	JobId  *uint
+	TaskId *uint
+	BlogId *uint
+}
+
+// recordLLMUsage simpan metrik satu panggilan LLM, termasuk yang gagal
+func recordLLMUsage(task string, provider string, model string, resp LLMResponse, err error, ref LLMUsageRef) {
+
+	usage := models.LlmUsage{
+		Task:             task,
+		Provider:         provider,
+		Model:            firstNonEmpty(resp.Model, model),
+		PromptTokens:     resp.PromptTokens,
+		CompletionTokens: resp.CompletionTokens,
+		DurationMs:       resp.Duration.Milliseconds(),
+		Success:          err == nil,
+		JobId:            ref.JobId,
+		TaskId:           ref.TaskId,
+		BlogId:           ref.BlogId,
+	}
+	if err != nil {
+		usage.Error = err.Error()
+	}
+
+	if dbErr := database.DB.Create(&usage).Error; dbErr != nil {
+		log.Printf("[LLM USAGE] failed to record %s call: %v", task, dbErr)
+	}
+}
+
+// LinkLLMUsageToBlog tautkan pemakaian LLM sebuah task ke blog yang akhirnya dibuat dari task itu
+func LinkLLMUsageToBlog(taskId uint, blogId uint) {
+	database.DB.Model(&models.LlmUsage{}).
+		Where("task_id = ? AND blog_id IS NULL", taskId).
+		Update("blog_id", blogId)
+}
+
+// measure isi Duration dengan waktu nyata kalau provider tidak melaporkan durasinya
+func measure(resp *LLMResponse, start time.Time) {
+	if resp.Duration == 0 {
+		resp.Duration = time.Since(start)
+	}
+}
@@ -0,0 +1,193 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MailMessage satu email yang akan dikirim
+type MailMessage struct {
+	To      string
+	Subject string
+	Text    string
+	Html    string
+	Headers map[string]string
+}
+
+// Mailer transport pengiriman email
+type Mailer interface {
+	Send(msg MailMessage) error
+}
+
+// GetMailer pilih transport dari env MAIL_DRIVER: "smtp" atau "file" (default)
+func GetMailer() Mailer {
+	switch os.Getenv("MAIL_DRIVER") {
+	case "smtp":
+		return &SmtpMailer{
+			Host:     getEnvDefault("MAIL_HOST", "localhost"),
+			Port:     getEnvDefault("MAIL_PORT", "1025"),
+			Username: os.Getenv("MAIL_USERNAME"),
+			Password: os.Getenv("MAIL_PASSWORD"),
+			From:     getMailFrom(),
+		}
+	default:
+		return &FileMailer{
+			Dir:  getEnvDefault("MAIL_FILE_DIR", "storage/mail"),
+			From: getMailFrom(),
+		}
+	}
+}
+
+// getEnvDefault ambil env dengan nilai default
+func getEnvDefault(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getMailFrom alamat pengirim dari env MAIL_FROM
+func getMailFrom() string {
+	return getEnvDefault("MAIL_FROM", "Arlchoose <no-reply@localhost>")
+}
+
+// SmtpMailer kirim lewat SMTP — tanpa username bisa dipakai ke SMTP lokal seperti MailHog/Mailpit
+type SmtpMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (m *SmtpMailer) Send(msg MailMessage) error {
+	raw, err := buildMimeMessage(m.From, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	return smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, extractAddress(m.From), []string{msg.To}, raw)
+}
+
+// FileMailer simpan email sebagai file .eml, untuk development dan testing
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+func (m *FileMailer) Send(msg MailMessage) error {
+	raw, err := buildMimeMessage(m.From, msg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, os.ModePerm); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), GenerateSlug(msg.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), raw, 0644)
+}
+
+// IsPermanentMailError cek apakah error SMTP adalah kegagalan permanen (5xx) → dianggap bounce
+func IsPermanentMailError(err error) bool {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 500 && protoErr.Code < 600
+	}
+	return false
+}
+
+// GenerateRandomToken generate token acak 64 karakter hex (untuk link konfirmasi/unsubscribe)
+func GenerateRandomToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// extractAddress ambil "a@b.c" dari "Nama <a@b.c>"
+func extractAddress(from string) string {
+	if start := strings.LastIndex(from, "<"); start != -1 {
+		if end := strings.LastIndex(from, ">"); end > start {
+			return from[start+1 : end]
+		}
+	}
+	return strings.TrimSpace(from)
+}
+
+// buildMimeMessage susun email multipart/alternative (text + html)
+func buildMimeMessage(from string, msg MailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	boundary := "b-" + GenerateRandomToken()[:24]
+
+	headers := map[string]string{
+		"From":         from,
+		"To":           msg.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"Message-ID":   fmt.Sprintf("<%s@%s>", GenerateRandomToken()[:32], mailDomain(from)),
+		"MIME-Version": "1.0",
+		"Content-Type": fmt.Sprintf(`multipart/alternative; boundary="%s"`, boundary),
+	}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	for k, v := range headers {
+		if strings.ContainsAny(k+v, "\r\n") {
+			return nil, fmt.Errorf("invalid mail header %s", k)
+		}
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, v)
+	}
+	buf.WriteString("\r\n")
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain", msg.Text},
+		{"text/html", msg.Html},
+	}
+	for _, part := range parts {
+		if part.body == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		fmt.Fprintf(&buf, "Content-Type: %s; charset=utf-8\r\n", part.contentType)
+		buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		qp := quotedprintable.NewWriter(&buf)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		qp.Close()
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	return buf.Bytes(), nil
+}
+
+// mailDomain ambil domain dari alamat pengirim untuk Message-ID
+func mailDomain(from string) string {
+	addr := extractAddress(from)
+	if at := strings.LastIndex(addr, "@"); at != -1 {
+		return addr[at+1:]
+	}
+	return "localhost"
+}
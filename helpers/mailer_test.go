@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"bufio"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// fakeSmtpServer SMTP minimal untuk test: menyimpan DATA terakhir, RCPT ke rejectRcpt dijawab 550
+func fakeSmtpServer(t *testing.T, rejectRcpt string) (string, string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 fake ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				tp.PrintfLine("250 fake")
+			case strings.HasPrefix(cmd, "RCPT") && rejectRcpt != "" && strings.Contains(line, rejectRcpt):
+				tp.PrintfLine("550 5.1.1 mailbox unavailable")
+			case strings.HasPrefix(cmd, "MAIL"), strings.HasPrefix(cmd, "RCPT"), strings.HasPrefix(cmd, "RSET"):
+				tp.PrintfLine("250 ok")
+			case cmd == "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, err := tp.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				messages <- string(data)
+				tp.PrintfLine("250 queued")
+			case cmd == "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	return host, port, messages
+}
+
+func TestSmtpMailerSend(t *testing.T) {
+	host, port, messages := fakeSmtpServer(t, "")
+	mailer := &SmtpMailer{Host: host, Port: port, From: "Arlchoose <no-reply@arlchoose.test>"}
+
+	err := mailer.Send(MailMessage{
+		To:      "pembaca@example.com",
+		Subject: "Konfirmasi langganan",
+		Text:    "Klik link berikut.",
+		Html:    "<p>Klik link berikut.</p>",
+		Headers: map[string]string{"List-Unsubscribe": "<https://arlchoose.test/unsubscribe?token=abc>"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(<-messages))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"From":             "Arlchoose <no-reply@arlchoose.test>",
+		"To":               "pembaca@example.com",
+		"List-Unsubscribe": "<https://arlchoose.test/unsubscribe?token=abc>",
+	}
+	for key, want := range tests {
+		if got := msg.Get(key); got != want {
+			t.Errorf("header %s = %q, want %q", key, got, want)
+		}
+	}
+	if !strings.HasPrefix(msg.Get("Content-Type"), "multipart/alternative") {
+		t.Errorf("content type = %q", msg.Get("Content-Type"))
+	}
+}
+
+func TestSmtpMailerPermanentFailure(t *testing.T) {
+	host, port, _ := fakeSmtpServer(t, "hilang@example.com")
+	mailer := &SmtpMailer{Host: host, Port: port, From: "no-reply@arlchoose.test"}
+
+	err := mailer.Send(MailMessage{To: "hilang@example.com", Subject: "Digest", Text: "Isi."})
+	if err == nil {
+		t.Fatal("expected error for rejected recipient")
+	}
+	if !IsPermanentMailError(err) {
+		t.Errorf("550 should be a permanent error, got %v", err)
+	}
+}
+
+func TestBuildMimeMessageRejectsHeaderInjection(t *testing.T) {
+	_, err := buildMimeMessage("no-reply@arlchoose.test", MailMessage{
+		To:      "a@example.com\r\nBcc: b@example.com",
+		Subject: "Halo",
+		Text:    "Isi.",
+	})
+	if err == nil {
+		t.Error("expected error for header with CRLF")
+	}
+}
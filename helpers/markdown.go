@@ -0,0 +1,975 @@
+package helpers
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Konversi konten blog (HTML) ke Markdown untuk bundle export, dan kebalikannya saat import.
+// Elemen tanpa padanan Markdown (tabel, elemen dengan atribut tambahan) ditulis apa adanya sebagai HTML,
+// yang memang diizinkan Markdown, jadi konten tidak hilang saat export lalu import lagi.
+
+// Tag yang di Markdown diperlakukan sebagai blok HTML mentah kalau muncul di awal baris
+var mdBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "audio": true, "blockquote": true, "canvas": true,
+	"details": true, "div": true, "dl": true, "fieldset": true, "figcaption": true, "figure": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "iframe": true, "li": true, "main": true, "nav": true, "noscript": true,
+	"ol": true, "p": true, "pre": true, "script": true, "section": true, "style": true, "table": true,
+	"tbody": true, "td": true, "tfoot": true, "th": true, "thead": true, "tr": true, "ul": true, "video": true,
+}
+
+var (
+	mdSpacePattern      = regexp.MustCompile(`\s+`)
+	mdBlankLinesPattern = regexp.MustCompile(`\n\s*\n`)
+	mdHeadingIdPattern  = regexp.MustCompile(`^[A-Za-z0-9_\-:.]+$`)
+
+	mdFencePattern    = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+	mdHeadingPattern  = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?[ \t]*$`)
+	mdHeadingEnd      = regexp.MustCompile(`(?:^|[ \t]+)#+$`)
+	mdHeadingAttr     = regexp.MustCompile(`[ \t]*\{#([A-Za-z0-9_\-:.]+)\}$`)
+	mdListItemPattern = regexp.MustCompile(`^( {0,3})([-*+]|\d{1,9}[.)])([ \t]+|$)(.*)$`)
+	mdHtmlBlockStart  = regexp.MustCompile(`^ {0,3}<(?:!--|/?([A-Za-z][A-Za-z0-9]*)(?:[\s/>]|$))`)
+
+	mdInlineHtmlPattern = regexp.MustCompile(`^(?:<[A-Za-z][A-Za-z0-9-]*(?:\s+[A-Za-z_:][A-Za-z0-9_.:-]*(?:\s*=\s*(?:"[^"]*"|'[^']*'|[^\s"'=<>` + "`" + `]+))?)*\s*/?>|</[A-Za-z][A-Za-z0-9-]*\s*>|<!--[\s\S]*?-->)`)
+	mdAutolinkPattern   = regexp.MustCompile(`^<(https?://[^\s<>]+)>`)
+	mdEntityPattern     = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+)
+
+const mdEscapable = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// Escape isi teks; atribut tetap memakai html.EscapeString yang juga meng-escape tanda kutip
+var mdTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// ==================== HTML → MARKDOWN ====================
+
+// HtmlToMarkdown ubah konten HTML blog jadi Markdown
+func HtmlToMarkdown(content string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return content
+	}
+	return strings.Join(mdBlocks(nodes), "\n\n")
+}
+
+// mdBlocks render node sejajar jadi blok Markdown; node inline yang berurutan digabung jadi satu paragraf
+func mdBlocks(nodes []*html.Node) []string {
+	var blocks []string
+	var inline []*html.Node
+
+	flush := func() {
+		if text := mdEscapeLineStarts(strings.TrimSpace(mdInline(inline))); text != "" {
+			blocks = append(blocks, text)
+		}
+		inline = nil
+	}
+
+	for _, n := range nodes {
+		switch {
+		case n.Type == html.ElementNode && mdBlockTags[n.Data]:
+			flush()
+			if block := mdBlock(n); block != "" {
+				blocks = append(blocks, block)
+			}
+		case n.Type == html.CommentNode:
+			flush()
+			blocks = append(blocks, "<!--"+n.Data+"-->")
+		default:
+			inline = append(inline, n)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+func mdBlock(n *html.Node) string {
+	switch n.Data {
+	case "p":
+		if !mdAttrsAllowed(n) {
+			return mdRaw(n)
+		}
+		return mdEscapeLineStarts(strings.TrimSpace(mdInline(mdChildren(n))))
+
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		id := mdAttr(n, "id")
+		text := strings.TrimSpace(mdInline(mdChildren(n)))
+		if !mdAttrsAllowed(n, "id") || strings.Contains(text, "\n") || (id != "" && !mdHeadingIdPattern.MatchString(id)) {
+			return mdRaw(n)
+		}
+		line := strings.Repeat("#", int(n.Data[1]-'0')) + " " + text
+		if id != "" {
+			line += " {#" + id + "}"
+		}
+		return line
+
+	case "ul", "ol":
+		return mdList(n)
+
+	case "blockquote":
+		if !mdAttrsAllowed(n) {
+			return mdRaw(n)
+		}
+		lines := strings.Split(strings.Join(mdBlocks(mdChildren(n)), "\n\n"), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return strings.Join(lines, "\n")
+
+	case "pre":
+		return mdCodeBlock(n)
+
+	case "hr":
+		return "---"
+	}
+
+	return mdRaw(n)
+}
+
+// mdList list bertingkat; list dianggap "loose" (item berisi paragraf) kalau ada item yang memuat elemen blok
+func mdList(n *html.Node) string {
+	ordered := n.Data == "ol"
+	if (ordered && !mdAttrsAllowed(n, "start")) || (!ordered && !mdAttrsAllowed(n)) {
+		return mdRaw(n)
+	}
+
+	number := 1
+	if start, err := strconv.Atoi(mdAttr(n, "start")); err == nil && start >= 0 {
+		number = start
+	}
+
+	var items []*html.Node
+	loose := false
+	for _, li := range mdChildren(n) {
+		if li.Type == html.TextNode && strings.TrimSpace(li.Data) == "" {
+			continue
+		}
+		if li.Type != html.ElementNode || li.Data != "li" || !mdAttrsAllowed(li) {
+			return mdRaw(n)
+		}
+		for _, child := range mdChildren(li) {
+			if child.Type == html.ElementNode && mdBlockTags[child.Data] && child.Data != "ul" && child.Data != "ol" {
+				loose = true
+			}
+		}
+		items = append(items, li)
+	}
+
+	separator := "\n"
+	if loose {
+		separator = "\n\n"
+	}
+
+	rendered := make([]string, 0, len(items))
+	for _, li := range items {
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(strings.Join(mdBlocks(mdChildren(li)), separator), "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		rendered = append(rendered, strings.TrimRight(marker+strings.Join(lines, "\n"), " "))
+	}
+
+	return strings.Join(rendered, separator)
+}
+
+// mdCodeBlock <pre><code class="language-x"> jadi fenced code block
+func mdCodeBlock(pre *html.Node) string {
+	children := mdChildren(pre)
+	if !mdAttrsAllowed(pre) || len(children) != 1 || children[0].Type != html.ElementNode || children[0].Data != "code" {
+		return mdRaw(pre)
+	}
+	code := children[0]
+	class := mdAttr(code, "class")
+	if !mdAttrsAllowed(code, "class") || (class != "" && !strings.HasPrefix(class, "language-")) || strings.ContainsAny(class, " `") {
+		return mdRaw(pre)
+	}
+	for _, child := range mdChildren(code) {
+		if child.Type != html.TextNode {
+			return mdRaw(pre)
+		}
+	}
+
+	text := strings.TrimSuffix(mdTextContent(code), "\n")
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	return fence + strings.TrimPrefix(class, "language-") + "\n" + text + "\n" + fence
+}
+
+func mdInline(nodes []*html.Node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		mdInlineNode(&b, n)
+	}
+	return b.String()
+}
+
+func mdInlineNode(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(mdEscapeText(mdSpacePattern.ReplaceAllString(n.Data, " ")))
+		return
+	case html.CommentNode:
+		b.WriteString("<!--" + n.Data + "-->")
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch {
+	case n.Data == "br" && mdAttrsAllowed(n):
+		b.WriteString("\\\n")
+
+	case (n.Data == "strong" || n.Data == "b") && mdAttrsAllowed(n):
+		b.WriteString(mdWrap("**", mdInline(mdChildren(n))))
+
+	case (n.Data == "em" || n.Data == "i") && mdAttrsAllowed(n):
+		b.WriteString(mdWrap("*", mdInline(mdChildren(n))))
+
+	case n.Data == "code" && mdAttrsAllowed(n) && mdTextOnly(n):
+		text := strings.ReplaceAll(mdTextContent(n), "\n", " ")
+		if strings.Contains(text, "`") {
+			b.WriteString("`` " + text + " ``")
+		} else {
+			b.WriteString("`" + text + "`")
+		}
+
+	case n.Data == "a" && mdAttrsAllowed(n, "href", "title") && mdHasAttr(n, "href") && mdDestinationOk(mdAttr(n, "href")):
+		b.WriteString("[" + mdInline(mdChildren(n)) + "](" + mdDestination(mdAttr(n, "href")) + mdTitle(n) + ")")
+
+	case n.Data == "img" && mdAttrsAllowed(n, "src", "alt", "title") && mdDestinationOk(mdAttr(n, "src")):
+		b.WriteString("![" + mdEscapeText(mdAttr(n, "alt")) + "](" + mdDestination(mdAttr(n, "src")) + mdTitle(n) + ")")
+
+	default:
+		// Elemen inline lain (sup, span, ...) tetap HTML, isinya tetap dikonversi
+		var tag strings.Builder
+		html.Render(&tag, &html.Node{Type: html.ElementNode, Data: n.Data, Attr: n.Attr})
+		open := tag.String()
+		if voidElements[n.Data] {
+			b.WriteString(strings.TrimSuffix(open, "</"+n.Data+">"))
+			return
+		}
+		b.WriteString(strings.TrimSuffix(open, "</"+n.Data+">"))
+		b.WriteString(mdInline(mdChildren(n)))
+		b.WriteString("</" + n.Data + ">")
+	}
+}
+
+// mdWrap bungkus teks dengan penanda; spasi di tepi dipindah ke luar penanda supaya tetap valid Markdown
+func mdWrap(marker string, text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:len(text)-len(strings.TrimLeft(text, " \n"))]
+	trail := text[len(strings.TrimRight(text, " \n")):]
+	return lead + marker + trimmed + marker + trail
+}
+
+func mdDestinationOk(dest string) bool {
+	return !strings.ContainsAny(dest, "<>\n")
+}
+
+func mdDestination(dest string) string {
+	if dest == "" || strings.ContainsAny(dest, " ()") {
+		return "<" + dest + ">"
+	}
+	return dest
+}
+
+func mdTitle(n *html.Node) string {
+	if !mdHasAttr(n, "title") {
+		return ""
+	}
+	title := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(mdAttr(n, "title"))
+	return ` "` + title + `"`
+}
+
+// mdEscapeText escape karakter yang punya arti di Markdown
+func mdEscapeText(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case strings.IndexByte("\\`*_[]<", c) >= 0:
+			b.WriteByte('\\')
+		case c == '&' && mdEntityPattern.MatchString(text[i:]):
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// mdEscapeLineStarts escape awal baris paragraf yang bisa terbaca sebagai heading, list, atau quote
+func mdEscapeLineStarts(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		if strings.IndexByte("#>-+~=", line[0]) >= 0 {
+			lines[i] = "\\" + line
+			continue
+		}
+		digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+		if digits > 0 && digits < len(line) && (line[digits] == '.' || line[digits] == ')') {
+			lines[i] = line[:digits] + "\\" + line[digits:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// mdRaw tulis elemen apa adanya; baris kosong dibuang karena baris kosong menutup blok HTML di Markdown
+func mdRaw(n *html.Node) string {
+	var b strings.Builder
+	html.Render(&b, n)
+	return mdBlankLinesPattern.ReplaceAllString(b.String(), "\n")
+}
+
+func mdChildren(n *html.Node) []*html.Node {
+	var children []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		children = append(children, c)
+	}
+	return children
+}
+
+func mdTextContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(mdTextContent(c))
+	}
+	return b.String()
+}
+
+func mdTextOnly(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.TextNode {
+			return false
+		}
+	}
+	return true
+}
+
+func mdAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func mdHasAttr(n *html.Node, key string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// mdAttrsAllowed true kalau semua atribut elemen punya padanan di Markdown
+func mdAttrsAllowed(n *html.Node, allowed ...string) bool {
+	for _, attr := range n.Attr {
+		ok := false
+		for _, key := range allowed {
+			if attr.Namespace == "" && attr.Key == key {
+				ok = true
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ==================== MARKDOWN → HTML ====================
+
+// MarkdownToHtml ubah Markdown jadi HTML konten blog. Yang didukung: heading (dengan {#id}), paragraf,
+// list, blockquote, fenced code, garis, gambar, link, tebal/miring, kode inline, dan HTML mentah.
+func MarkdownToHtml(markdown string) string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	return strings.Join(mdRenderBlocks(mdParseBlocks(lines), false), "\n")
+}
+
+// mdParsed satu blok hasil parse; paragraf disimpan tanpa <p> supaya bisa dibuka di list yang rapat
+type mdParsed struct {
+	paragraph bool
+	html      string
+}
+
+func mdRenderBlocks(blocks []mdParsed, tight bool) []string {
+	out := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if block.paragraph && !tight {
+			out = append(out, "<p>"+block.html+"</p>")
+			continue
+		}
+		out = append(out, block.html)
+	}
+	return out
+}
+
+func mdParseBlocks(lines []string) []mdParsed {
+	var blocks []mdParsed
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if strings.TrimSpace(line) == "" {
+			i++
+			continue
+		}
+
+		if m := mdFencePattern.FindStringSubmatch(line); m != nil {
+			indent := mdIndent(line)
+			var code []string
+			i++
+			for i < len(lines) {
+				closing := strings.TrimSpace(lines[i])
+				if mdIndent(lines[i]) < 4 && strings.HasPrefix(closing, m[1]) && strings.Trim(closing, m[1][:1]) == "" {
+					i++
+					break
+				}
+				code = append(code, mdStripIndent(lines[i], indent))
+				i++
+			}
+			open := "<pre><code>"
+			if m[2] != "" {
+				open = `<pre><code class="language-` + html.EscapeString(m[2]) + `">`
+			}
+			blocks = append(blocks, mdParsed{html: open + mdTextEscaper.Replace(strings.Join(code, "\n")) + "</code></pre>"})
+			continue
+		}
+
+		if m := mdHeadingPattern.FindStringSubmatch(line); m != nil {
+			text := mdHeadingEnd.ReplaceAllString(m[2], "")
+			attr := ""
+			if id := mdHeadingAttr.FindStringSubmatch(text); id != nil {
+				text = strings.TrimSuffix(text, id[0])
+				attr = ` id="` + id[1] + `"`
+			}
+			level := strconv.Itoa(len(m[1]))
+			blocks = append(blocks, mdParsed{html: "<h" + level + attr + ">" + mdInlineToHtml(strings.TrimSpace(text)) + "</h" + level + ">"})
+			i++
+			continue
+		}
+
+		if mdIsRule(line) {
+			blocks = append(blocks, mdParsed{html: "<hr>"})
+			i++
+			continue
+		}
+
+		if mdIsQuote(line) {
+			var quoted []string
+			for i < len(lines) && mdIsQuote(lines[i]) {
+				rest := strings.TrimLeft(lines[i], " ")[1:]
+				quoted = append(quoted, strings.TrimPrefix(rest, " "))
+				i++
+			}
+			inner := strings.Join(mdRenderBlocks(mdParseBlocks(quoted), false), "\n")
+			blocks = append(blocks, mdParsed{html: "<blockquote>\n" + inner + "\n</blockquote>"})
+			continue
+		}
+
+		if mdListItemPattern.MatchString(line) {
+			list, next := mdParseList(lines, i)
+			blocks = append(blocks, mdParsed{html: list})
+			i = next
+			continue
+		}
+
+		if mdIsHtmlBlock(line) {
+			var raw []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" {
+				raw = append(raw, lines[i])
+				i++
+			}
+			blocks = append(blocks, mdParsed{html: strings.Join(raw, "\n")})
+			continue
+		}
+
+		// Paragraf: sampai baris kosong atau baris yang memulai blok lain
+		para := []string{strings.TrimLeft(line, " \t")}
+		i++
+		for i < len(lines) && strings.TrimSpace(lines[i]) != "" && !mdInterruptsParagraph(lines[i]) {
+			para = append(para, strings.TrimLeft(lines[i], " \t"))
+			i++
+		}
+		text := strings.TrimRight(strings.Join(para, "\n"), " \t")
+		blocks = append(blocks, mdParsed{paragraph: true, html: mdInlineToHtml(text)})
+	}
+
+	return blocks
+}
+
+// mdParseList parse list mulai dari baris i, kembalikan HTML dan indeks baris setelah list
+func mdParseList(lines []string, i int) (string, int) {
+	first := mdListItemPattern.FindStringSubmatch(lines[i])
+	ordered := first[2][0] >= '0' && first[2][0] <= '9'
+	kind := first[2][len(first[2])-1:]
+
+	var items [][]string
+	loose := false
+
+	for i < len(lines) {
+		m := mdListItemPattern.FindStringSubmatch(lines[i])
+		if m == nil || m[2][len(m[2])-1:] != kind || (m[2][0] >= '0' && m[2][0] <= '9') != ordered {
+			break
+		}
+
+		contentIndent := len(m[1]) + len(m[2]) + len(m[3])
+		if m[3] == "" || len(m[3]) > 4 {
+			contentIndent = len(m[1]) + len(m[2]) + 1
+		}
+		item := []string{m[4]}
+		i++
+
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// Baris kosong masih bagian item kalau baris berikutnya menjorok
+				j := i
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+				if j < len(lines) && mdIndent(lines[j]) >= contentIndent {
+					for ; i < j; i++ {
+						item = append(item, "")
+					}
+					continue
+				}
+				break
+			}
+			if mdIndent(line) >= contentIndent {
+				item = append(item, mdStripIndent(line, contentIndent))
+				i++
+				continue
+			}
+			// Lanjutan paragraf tanpa indentasi
+			if item[len(item)-1] == "" || mdInterruptsParagraph(line) || mdListItemPattern.MatchString(line) {
+				break
+			}
+			item = append(item, strings.TrimLeft(line, " \t"))
+			i++
+		}
+
+		for _, line := range item {
+			if line == "" {
+				loose = true
+			}
+		}
+		items = append(items, item)
+
+		// Baris kosong di antara item membuat list jadi loose
+		j := i
+		for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+			j++
+		}
+		if j == i {
+			continue
+		}
+		if next := mdListItemPattern.FindStringSubmatch(safeLine(lines, j)); next == nil || next[2][len(next[2])-1:] != kind {
+			break
+		}
+		loose = true
+		i = j
+	}
+
+	tag := "ul"
+	open := "<ul>"
+	if ordered {
+		tag = "ol"
+		open = "<ol>"
+		if start, _ := strconv.Atoi(first[2][:len(first[2])-1]); start != 1 {
+			open = `<ol start="` + strconv.Itoa(start) + `">`
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(open + "\n")
+	for _, item := range items {
+		parts := mdRenderBlocks(mdParseBlocks(item), !loose)
+		b.WriteString("<li>" + strings.Join(parts, "\n") + "</li>\n")
+	}
+	b.WriteString("</" + tag + ">")
+
+	return b.String(), i
+}
+
+func safeLine(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+// mdInterruptsParagraph baris yang langsung memulai blok baru walau tanpa baris kosong sebelumnya
+func mdInterruptsParagraph(line string) bool {
+	if mdFencePattern.MatchString(line) || mdHeadingPattern.MatchString(line) || mdIsRule(line) || mdIsQuote(line) || mdIsHtmlBlock(line) {
+		return true
+	}
+	// Sama seperti CommonMark: list bernomor hanya memotong paragraf kalau mulai dari 1
+	if m := mdListItemPattern.FindStringSubmatch(line); m != nil && strings.TrimSpace(m[4]) != "" {
+		return !(m[2][0] >= '0' && m[2][0] <= '9') || m[2][:len(m[2])-1] == "1"
+	}
+	return false
+}
+
+func mdIsRule(line string) bool {
+	if mdIndent(line) > 3 {
+		return false
+	}
+	compact := strings.Join(strings.Fields(line), "")
+	if len(compact) < 3 || strings.IndexByte("-*_", compact[0]) < 0 {
+		return false
+	}
+	return strings.Trim(compact, compact[:1]) == ""
+}
+
+func mdIsQuote(line string) bool {
+	return mdIndent(line) <= 3 && strings.HasPrefix(strings.TrimLeft(line, " "), ">")
+}
+
+func mdIsHtmlBlock(line string) bool {
+	m := mdHtmlBlockStart.FindStringSubmatch(line)
+	return m != nil && (m[1] == "" || mdBlockTags[strings.ToLower(m[1])])
+}
+
+// mdIndent lebar indentasi di awal baris, tab dihitung 4 kolom
+func mdIndent(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// mdStripIndent buang indentasi sampai n kolom tanpa menyentuh tab di dalam isi baris
+func mdStripIndent(line string, n int) string {
+	width := 0
+	for i, c := range line {
+		if width >= n {
+			return line[i:]
+		}
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+			if width > n {
+				return strings.Repeat(" ", width-n) + line[i+1:]
+			}
+		default:
+			return line[i:]
+		}
+	}
+	return ""
+}
+
+// mdInlineToHtml konversi elemen inline: escape, kode, gambar, link, tebal/miring, HTML mentah, line break
+func mdInlineToHtml(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == '\n':
+			b.WriteString("<br>\n")
+			i += 2
+
+		case c == '\\' && i+1 < len(s) && strings.IndexByte(mdEscapable, s[i+1]) >= 0:
+			b.WriteString(mdTextEscaper.Replace(s[i+1 : i+2]))
+			i += 2
+
+		case c == '`':
+			run := mdRunLength(s, i, '`')
+			end := mdFindCodeEnd(s, i+run, run)
+			if end < 0 {
+				b.WriteString(s[i : i+run])
+				i += run
+				continue
+			}
+			code := strings.ReplaceAll(s[i+run:end], "\n", " ")
+			if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+				code = code[1 : len(code)-1]
+			}
+			b.WriteString("<code>" + mdTextEscaper.Replace(code) + "</code>")
+			i = end + run
+
+		case c == '!' && i+1 < len(s) && s[i+1] == '[':
+			text, dest, title, end, ok := mdParseLink(s, i+1)
+			if !ok {
+				b.WriteByte('!')
+				i++
+				continue
+			}
+			b.WriteString(`<img src="` + html.EscapeString(dest) + `" alt="` + html.EscapeString(mdUnescape(text)) + `"` + mdTitleAttr(title) + `>`)
+			i = end
+
+		case c == '[':
+			text, dest, title, end, ok := mdParseLink(s, i)
+			if !ok {
+				b.WriteByte('[')
+				i++
+				continue
+			}
+			b.WriteString(`<a href="` + html.EscapeString(dest) + `"` + mdTitleAttr(title) + `>` + mdInlineToHtml(text) + `</a>`)
+			i = end
+
+		case c == '*' || c == '_':
+			n := min(mdRunLength(s, i, c), 2)
+			delim := s[i : i+n]
+			opens := i+n < len(s) && !mdIsSpace(s[i+n]) && (c == '*' || i == 0 || !mdIsWord(s[i-1]))
+			if end := mdFindEmphasisEnd(s, i+n, delim); opens && end > i+n {
+				tag := "em"
+				if n == 2 {
+					tag = "strong"
+				}
+				b.WriteString("<" + tag + ">" + mdInlineToHtml(s[i+n:end]) + "</" + tag + ">")
+				i = end + n
+				continue
+			}
+			b.WriteString(delim)
+			i += n
+
+		case c == '<':
+			if raw := mdInlineHtmlPattern.FindString(s[i:]); raw != "" {
+				b.WriteString(raw)
+				i += len(raw)
+				continue
+			}
+			if m := mdAutolinkPattern.FindStringSubmatch(s[i:]); m != nil {
+				b.WriteString(`<a href="` + html.EscapeString(m[1]) + `">` + mdTextEscaper.Replace(m[1]) + `</a>`)
+				i += len(m[0])
+				continue
+			}
+			b.WriteString("&lt;")
+			i++
+
+		case c == '&':
+			if entity := mdEntityPattern.FindString(s[i:]); entity != "" {
+				b.WriteString(entity)
+				i += len(entity)
+				continue
+			}
+			b.WriteString("&amp;")
+			i++
+
+		case c == '>':
+			b.WriteString("&gt;")
+			i++
+
+		case c == ' ' && strings.HasPrefix(strings.TrimLeft(s[i:], " "), "\n") && mdRunLength(s, i, ' ') >= 2:
+			b.WriteString("<br>")
+			i += mdRunLength(s, i, ' ')
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// mdParseLink parse [teks](tujuan "judul") mulai dari '[' di posisi i
+func mdParseLink(s string, i int) (string, string, string, int, bool) {
+	depth := 0
+	close := -1
+	for j := i; j < len(s) && close < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			run := mdRunLength(s, j, '`')
+			if end := mdFindCodeEnd(s, j+run, run); end >= 0 {
+				j = end + run - 1
+			} else {
+				j += run - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				close = j
+			}
+		}
+	}
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return "", "", "", 0, false
+	}
+
+	j := close + 2
+	for j < len(s) && mdIsSpace(s[j]) {
+		j++
+	}
+
+	var dest string
+	if j < len(s) && s[j] == '<' {
+		end := strings.IndexAny(s[j+1:], ">\n")
+		if end < 0 || s[j+1+end] != '>' {
+			return "", "", "", 0, false
+		}
+		dest = s[j+1 : j+1+end]
+		j += end + 2
+	} else {
+		start, parens := j, 0
+		for ; j < len(s) && !mdIsSpace(s[j]); j++ {
+			if s[j] == '\\' {
+				j++
+				continue
+			}
+			if s[j] == '(' {
+				parens++
+			}
+			if s[j] == ')' {
+				if parens == 0 {
+					break
+				}
+				parens--
+			}
+		}
+		dest = s[start:min(j, len(s))]
+	}
+
+	for j < len(s) && mdIsSpace(s[j]) {
+		j++
+	}
+	title := ""
+	if j < len(s) && (s[j] == '"' || s[j] == '\'') {
+		quote := s[j]
+		end := j + 1
+		for ; end < len(s) && s[end] != quote; end++ {
+			if s[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(s) {
+			return "", "", "", 0, false
+		}
+		title = mdUnescape(s[j+1 : end])
+		j = end + 1
+		for j < len(s) && mdIsSpace(s[j]) {
+			j++
+		}
+	}
+	if j >= len(s) || s[j] != ')' {
+		return "", "", "", 0, false
+	}
+
+	return s[i+1 : close], mdUnescape(dest), title, j + 1, true
+}
+
+func mdTitleAttr(title string) string {
+	if title == "" {
+		return ""
+	}
+	return ` title="` + html.EscapeString(title) + `"`
+}
+
+// mdFindCodeEnd cari penutup kode inline dengan jumlah backtick yang sama persis
+func mdFindCodeEnd(s string, from int, run int) int {
+	for j := from; j < len(s); {
+		if s[j] != '`' {
+			j++
+			continue
+		}
+		n := mdRunLength(s, j, '`')
+		if n == run {
+			return j
+		}
+		j += n
+	}
+	return -1
+}
+
+// mdFindEmphasisEnd cari penutup * / ** / _ / __, melewati escape, kode inline, dan tag HTML
+func mdFindEmphasisEnd(s string, from int, delim string) int {
+	for j := from; j < len(s); {
+		switch c := s[j]; {
+		case c == '\\':
+			j += 2
+		case c == '`':
+			run := mdRunLength(s, j, '`')
+			if end := mdFindCodeEnd(s, j+run, run); end >= 0 {
+				j = end + run
+			} else {
+				j += run
+			}
+		case c == '<':
+			if raw := mdInlineHtmlPattern.FindString(s[j:]); raw != "" {
+				j += len(raw)
+			} else {
+				j++
+			}
+		case c == delim[0]:
+			n := mdRunLength(s, j, c)
+			closes := !mdIsSpace(s[j-1]) && (c == '*' || j+n >= len(s) || !mdIsWord(s[j+n]))
+			if closes && (n == len(delim) || (len(delim) == 2 && n > 2)) {
+				return j
+			}
+			// Penanda lain (misalnya ** di dalam *...*) dilewati utuh
+			j += n
+		default:
+			j++
+		}
+	}
+	return -1
+}
+
+func mdRunLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+func mdUnescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(mdEscapable, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func mdIsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func mdIsWord(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
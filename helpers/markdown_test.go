@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHtmlToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"paragraphs", "<p>Satu.</p>\n<p>Dua.</p>", "Satu.\n\nDua."},
+		{"heading with id", `<h2 id="instalasi">Instalasi Go</h2>`, "## Instalasi Go {#instalasi}"},
+		{"emphasis and code", "<p><strong>Tebal</strong>, <em>miring</em>, dan <code>go run</code>.</p>", "**Tebal**, *miring*, dan `go run`."},
+		{"link and image", `<p><a href="https://go.dev" title="Go">situs</a> <img src="/uploads/blogs/1.jpg" alt="Logo"></p>`, `[situs](https://go.dev "Go") ![Logo](/uploads/blogs/1.jpg)`},
+		{"escape markdown characters", "<p>a*b_c [x] &lt;tag&gt;</p>", `a\*b\_c \[x\] \<tag>`},
+		{"escape line start", "<p>1. bukan list<br>- juga bukan</p>", "1\\. bukan list\\\n\\- juga bukan"},
+		{"nested list", "<ul><li>Satu<ul><li>Anak</li></ul></li><li>Dua</li></ul>", "- Satu\n  - Anak\n- Dua"},
+		{"ordered list start", `<ol start="3"><li>Tiga</li><li>Empat</li></ol>`, "3. Tiga\n4. Empat"},
+		{"loose list", "<ul><li><p>Satu</p></li><li><p>Dua</p></li></ul>", "- Satu\n\n- Dua"},
+		{"blockquote", "<blockquote><p>Kutipan</p><p>Lanjut</p></blockquote>", "> Kutipan\n>\n> Lanjut"},
+		{"code block", "<pre><code class=\"language-go\">fmt.Println(\"hi\")\n</code></pre>", "```go\nfmt.Println(\"hi\")\n```"},
+		{"citation stays html", `<p>Fakta<sup><a href="#sumber-1">[1]</a></sup></p>`, `Fakta<sup>[\[1\]](#sumber-1)</sup>`},
+		{"table stays html", "<table><tbody><tr><td>a</td></tr></tbody></table>", "<table><tbody><tr><td>a</td></tr></tbody></table>"},
+		{"paragraph with class stays html", `<p class="lead">Halo</p>`, `<p class="lead">Halo</p>`},
+	}
+
+	for _, tt := range tests {
+		if got := HtmlToMarkdown(tt.html); got != tt.want {
+			t.Errorf("%s: HtmlToMarkdown() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownToHtml(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{"paragraphs", "Satu\nbaris.\n\nDua.", "<p>Satu\nbaris.</p>\n<p>Dua.</p>"},
+		{"headings", "# Judul\n## Bagian {#bagian}\n### Akhir ###", `<h1>Judul</h1>` + "\n" + `<h2 id="bagian">Bagian</h2>` + "\n" + `<h3>Akhir</h3>`},
+		{"emphasis", "**tebal** *miring* __juga__ _ini_ snake_case_name", "<p><strong>tebal</strong> <em>miring</em> <strong>juga</strong> <em>ini</em> snake_case_name</p>"},
+		{"link with title and parens", `[Go](<https://go.dev/a (b)> "Situs")`, `<p><a href="https://go.dev/a (b)" title="Situs">Go</a></p>`},
+		{"image", "![Logo *x*](/uploads/a.png)", `<p><img src="/uploads/a.png" alt="Logo *x*"></p>`},
+		{"inline code keeps markup", "pakai `a*b<c>`", "<p>pakai <code>a*b&lt;c&gt;</code></p>"},
+		{"escapes and entities", `\*bukan\* 5 > 3 & R&amp;D`, "<p>*bukan* 5 &gt; 3 &amp; R&amp;D</p>"},
+		{"hard break", "baris satu\\\nbaris dua  \nbaris tiga", "<p>baris satu<br>\nbaris dua<br>\nbaris tiga</p>"},
+		{"tight list", "- a\n- b\n  - c", "<ul>\n<li>a</li>\n<li>b\n<ul>\n<li>c</li>\n</ul></li>\n</ul>"},
+		{"loose ordered list", "2. a\n\n3. b", "<ol start=\"2\">\n<li><p>a</p></li>\n<li><p>b</p></li>\n</ol>"},
+		{"list interrupts paragraph", "Daftar:\n- a", "<p>Daftar:</p>\n<ul>\n<li>a</li>\n</ul>"},
+		{"number does not interrupt paragraph", "Tahun\n2024. baru", "<p>Tahun\n2024. baru</p>"},
+		{"fenced code", "```go\nif a < b {\n\treturn\n}\n```", "<pre><code class=\"language-go\">if a &lt; b {\n\treturn\n}</code></pre>"},
+		{"blockquote", "> kutipan\n> **tebal**", "<blockquote>\n<p>kutipan\n<strong>tebal</strong></p>\n</blockquote>"},
+		{"rule", "a\n\n---\n\nb", "<p>a</p>\n<hr>\n<p>b</p>"},
+		{"raw html block", "<table>\n<tr><td>*a*</td></tr>\n</table>", "<table>\n<tr><td>*a*</td></tr>\n</table>"},
+		{"inline html converts content", "Fakta<sup>[\\[1\\]](#sumber-1)</sup>", `<p>Fakta<sup><a href="#sumber-1">[1]</a></sup></p>`},
+		{"autolink", "lihat <https://go.dev>", `<p>lihat <a href="https://go.dev">https://go.dev</a></p>`},
+	}
+
+	for _, tt := range tests {
+		if got := MarkdownToHtml(tt.markdown); got != tt.want {
+			t.Errorf("%s: MarkdownToHtml() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	content := `<p>Pembuka dengan <strong>tebal</strong>, <em>miring</em>, <code>kode</code> dan <a href="https://go.dev">link</a>.</p>
+<h2 id="instalasi">Instalasi Go</h2>
+<p>Karakter * _ [ ] &lt; &amp; tetap aman.</p>
+<ul>
+<li>Satu
+<ul>
+<li>Anak</li>
+</ul></li>
+<li>Dua</li>
+</ul>
+<ol start="3">
+<li>Tiga</li>
+</ol>
+<blockquote>
+<p>Kutipan</p>
+</blockquote>
+<pre><code class="language-go">fmt.Println("a * b")</code></pre>
+<p><img src="/uploads/blogs/1.jpg" alt="Gambar"></p>
+<table><tbody><tr><td>sel</td></tr></tbody></table>
+<p>Fakta<sup><a href="#sumber-1">[1]</a></sup></p>`
+
+	got := MarkdownToHtml(HtmlToMarkdown(content))
+	if normalizeHtml(got) != normalizeHtml(content) {
+		t.Errorf("round trip changed content:\n got: %s\nwant: %s", got, content)
+	}
+}
+
+// normalizeHtml samakan whitespace antar tag supaya perbandingan tidak tergantung format baris
+func normalizeHtml(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer("> <", "><", " <", "<", "> ", ">").Replace(s)
+}
@@ -0,0 +1,338 @@
+package helpers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	ogWidth   = 1200
+	ogHeight  = 630
+	ogPadding = 80
+	ogFolder  = "uploads/og"
+
+	// ogVersion ikut masuk ke hash, naikkan kalau desain card berubah supaya cache lama tidak dipakai
+	ogVersion = "v1"
+)
+
+// OgCard data yang dirender ke share image
+type OgCard struct {
+	Kicker     string   // label kecil di atas judul, contoh: "BLOG"
+	Title      string   // teks utama
+	Subtitle   string   // deskripsi / tagline opsional
+	Tags       []string // tag atau tech stack, tampil sebagai chip
+	Author     string
+	SiteName   string
+	Background string // hex, contoh: "#0f172a"
+	Accent     string // hex, contoh: "#38bdf8"
+}
+
+// GetOgImage render share card ke uploads/og/ dan return path lokalnya
+// File di-cache berdasarkan hash konten, jadi card yang sama tidak dirender ulang
+func GetOgImage(card OgCard, format string) (string, error) {
+
+	ext := ".png"
+	if format == "jpg" || format == "jpeg" {
+		ext = ".jpg"
+	}
+
+	filePath := fmt.Sprintf("%s/%s%s", ogFolder, ogCardHash(card), ext)
+	if info, err := os.Stat(filePath); err == nil {
+		// Waktu modifikasi dipakai sebagai "terakhir dipakai" oleh SweepOgImages
+		if time.Since(info.ModTime()) > 24*time.Hour {
+			now := time.Now()
+			os.Chtimes(filePath, now, now)
+		}
+		return filePath, nil
+	}
+
+	if err := os.MkdirAll(ogFolder, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	img, err := renderOgCard(card)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if ext == ".jpg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode og image: %v", err)
+	}
+
+	// Render paralel untuk card yang sama menghasilkan file identik, yang terakhir rename menang
+	if err := writeFileAtomic(filePath, buf.Bytes(), 0644); err != nil {
+		return "", err
+	}
+
+	return filePath, nil
+}
+
+// StartOgImageSweeper hapus share image yang lama tidak dipakai setiap hari; card dirender ulang kalau diminta lagi
+func StartOgImageSweeper() {
+	go func() {
+		for {
+			if removed := SweepOgImages(); removed > 0 {
+				log.Printf("[OG IMAGE] removed %d cached images", removed)
+			}
+			time.Sleep(24 * time.Hour)
+		}
+	}()
+}
+
+// SweepOgImages hapus file di uploads/og yang tidak dipakai selama OG_CACHE_MAX_AGE_DAYS (default 30),
+// termasuk card versi lama setelah judul/tag berubah, dan file sementara sisa render yang gagal
+func SweepOgImages() int {
+
+	maxAge := 30 * 24 * time.Hour
+	if days, err := strconv.Atoi(os.Getenv("OG_CACHE_MAX_AGE_DAYS")); err == nil && days > 0 {
+		maxAge = time.Duration(days) * 24 * time.Hour
+	}
+
+	entries, err := os.ReadDir(ogFolder)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		age := time.Since(info.ModTime())
+		if age > maxAge || (strings.HasSuffix(entry.Name(), ".tmp") && age > time.Hour) {
+			if os.Remove(filepath.Join(ogFolder, entry.Name())) == nil {
+				removed++
+			}
+		}
+	}
+	return removed
+}
+
+// ogCardHash hash semua field card untuk nama file cache
+func ogCardHash(card OgCard) string {
+	h := sha256.New()
+	for _, part := range []string{
+		ogVersion, card.Kicker, card.Title, card.Subtitle, strings.Join(card.Tags, ","),
+		card.Author, card.SiteName, card.Background, card.Accent,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// renderOgCard menggambar card 1200x630
+func renderOgCard(card OgCard) (*image.RGBA, error) {
+
+	bg := parseHexColor(card.Background, color.RGBA{15, 23, 42, 255})
+	accent := parseHexColor(card.Accent, color.RGBA{56, 189, 248, 255})
+	textColor := color.RGBA{248, 250, 252, 255}
+	mutedColor := color.RGBA{148, 163, 184, 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, ogWidth, ogHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
+
+	// Garis aksen di sisi kiri
+	draw.Draw(img, image.Rect(0, 0, 16, ogHeight), &image.Uniform{accent}, image.Point{}, draw.Src)
+
+	maxWidth := ogWidth - ogPadding*2
+	y := ogPadding
+
+	// Site name + kicker
+	small, err := ogFace(goregular.TTF, 28)
+	if err != nil {
+		return nil, err
+	}
+	header := card.SiteName
+	if card.Kicker != "" {
+		if header != "" {
+			header += "  ·  "
+		}
+		header += strings.ToUpper(card.Kicker)
+	}
+	if header != "" {
+		y += 28
+		drawText(img, small, accent, ogPadding, y, truncateToWidth(small, header, maxWidth))
+		y += 40
+	}
+
+	// Judul — ukuran font mengecil kalau judul panjang, maksimal 3 baris
+	var titleFace font.Face
+	var titleLines []string
+	var titleSize float64
+	for _, size := range []float64{72, 60, 50} {
+		titleFace, err = ogFace(gobold.TTF, size)
+		if err != nil {
+			return nil, err
+		}
+		titleSize = size
+		titleLines = wrapText(titleFace, card.Title, maxWidth)
+		if len(titleLines) <= 3 {
+			break
+		}
+	}
+	titleLines = clampLines(titleFace, titleLines, 3, maxWidth)
+
+	for _, line := range titleLines {
+		y += int(titleSize * 1.2)
+		drawText(img, titleFace, textColor, ogPadding, y, line)
+	}
+
+	// Subtitle maksimal 2 baris
+	if card.Subtitle != "" {
+		subFace, err := ogFace(goregular.TTF, 30)
+		if err != nil {
+			return nil, err
+		}
+		y += 20
+		for _, line := range clampLines(subFace, wrapText(subFace, card.Subtitle, maxWidth), 2, maxWidth) {
+			y += 40
+			drawText(img, subFace, mutedColor, ogPadding, y, line)
+		}
+	}
+
+	// Tags sebagai chip di baris bawah
+	bottom := ogHeight - ogPadding
+	if len(card.Tags) > 0 {
+		tagFace, err := ogFace(goregular.TTF, 24)
+		if err != nil {
+			return nil, err
+		}
+		x := ogPadding
+		for _, tag := range card.Tags {
+			label := "#" + tag
+			w := font.MeasureString(tagFace, label).Ceil() + 32
+			if x+w > ogWidth-ogPadding {
+				break
+			}
+			chip := image.Rect(x, bottom-84, x+w, bottom-40)
+			draw.Draw(img, chip, &image.Uniform{color.RGBA{accent.R / 4, accent.G / 4, accent.B / 4, 255}}, image.Point{}, draw.Src)
+			drawText(img, tagFace, accent, x+16, bottom-53, label)
+			x += w + 12
+		}
+	}
+
+	// Author di pojok kiri bawah
+	if card.Author != "" {
+		authorFace, err := ogFace(gobold.TTF, 26)
+		if err != nil {
+			return nil, err
+		}
+		drawText(img, authorFace, textColor, ogPadding, bottom, truncateToWidth(authorFace, card.Author, maxWidth))
+	}
+
+	return img, nil
+}
+
+// ogFace parse font bawaan golang.org/x/image/font/gofont
+func ogFace(ttf []byte, size float64) (font.Face, error) {
+	f, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font: %v", err)
+	}
+	return opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// drawText gambar satu baris teks dengan baseline di y
+func drawText(img *image.RGBA, face font.Face, c color.Color, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// wrapText pecah teks jadi beberapa baris sesuai lebar maksimal
+func wrapText(face font.Face, text string, maxWidth int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth || current == "" {
+			current = candidate
+			continue
+		}
+		lines = append(lines, current)
+		current = word
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// clampLines batasi jumlah baris, baris terakhir diberi elipsis kalau ada yang terpotong
+func clampLines(face font.Face, lines []string, max int, maxWidth int) []string {
+	if len(lines) <= max {
+		for i, line := range lines {
+			lines[i] = truncateToWidth(face, line, maxWidth)
+		}
+		return lines
+	}
+	clamped := lines[:max]
+	clamped[max-1] = truncateToWidth(face, clamped[max-1]+" "+lines[max], maxWidth)
+	if !strings.HasSuffix(clamped[max-1], "…") {
+		clamped[max-1] += "…"
+	}
+	return clamped
+}
+
+// truncateToWidth potong teks per rune sampai muat, tambahkan elipsis kalau terpotong
+func truncateToWidth(face font.Face, text string, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// parseHexColor parse "#rrggbb", pakai fallback kalau format tidak valid
+func parseHexColor(s string, fallback color.RGBA) color.RGBA {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return fallback
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return fallback
+	}
+	return color.RGBA{b[0], b[1], b[2], 255}
+}
@@ -1,13 +1,11 @@
 package helpers
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"context"
 	"os"
 	"strings"
-	"time"
+
+	"arlchoose/backend-api/models"
 )
 
 type OllamaRequest struct {
@@ -17,7 +15,20 @@ type OllamaRequest struct {
 }
 
 type OllamaResponse struct {
-	Response string `json:"response"`
+	Response        string `json:"response"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	TotalDuration   int64  `json:"total_duration"` // nanodetik
+}
+
+// OllamaStreamChunk satu baris NDJSON saat stream: true
+type OllamaStreamChunk struct {
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	Error           string `json:"error"`
+	PromptEvalCount int    `json:"prompt_eval_count"`
+	EvalCount       int    `json:"eval_count"`
+	TotalDuration   int64  `json:"total_duration"`
 }
 
 // getOllamaModel mengambil model ollama dari env, default ke llama3
@@ -38,85 +49,24 @@ func getOllamaUrl() string {
 	return ollamaUrl
 }
 
-// askOllama mengirim prompt ke Ollama dan mengembalikan response
-func askOllama(prompt string) (string, error) {
+// GenerateBlogTitles meminta LLM untuk generate judul-judul blog.
+// avoid berisi judul yang sudah ada supaya topiknya tidak diulang.
+// Mengembalikan id versi prompt template yang dipakai.
+func GenerateBlogTitles(keyword string, total int, avoid []string, ref LLMUsageRef) ([]string, uint, error) {
 
-	reqBody := OllamaRequest{
-		Model:  getOllamaModel(),
-		Prompt: prompt,
-		Stream: false,
-	}
+	data := NewPromptData()
+	data.Keyword = keyword
+	data.Total = total
+	data.RecentTitles = avoid
 
-	jsonBody, err := json.Marshal(reqBody)
+	prompt, templateId, err := RenderPrompt(LLMTaskTitles, data)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %v", err)
-	}
-
-	resp, err := http.Post(
-		getOllamaUrl()+"/api/generate",
-		"application/json",
-		bytes.NewBuffer(jsonBody),
-	)
-	if err != nil {
-		return "", fmt.Errorf("failed to connect to ollama: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama returned status %d", resp.StatusCode)
-	}
-
-	var ollamaResp OllamaResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-		return "", fmt.Errorf("failed to decode ollama response: %v", err)
-	}
-
-	return ollamaResp.Response, nil
-}
-
-// GenerateBlogTitles meminta Ollama untuk generate judul-judul blog
-// GenerateBlogTitles meminta Ollama untuk generate judul-judul blog
-func GenerateBlogTitles(keyword string, total int) ([]string, error) {
-
-	now := time.Now()
-	currentDate := now.Format("2 January 2006")
-
-	var prompt string
-	if keyword != "" {
-		prompt = fmt.Sprintf(`Kamu adalah editor blog profesional Indonesia.
-Hari ini tanggal %s.
-
-Buat %d judul artikel blog dalam Bahasa Indonesia berdasarkan topik: "%s"
-
-ATURAN KETAT:
-- Judul harus REALISTIS dan FAKTUAL, bukan fiksi atau spekulasi liar
-- Fokus pada fakta, berita, analisis, atau panduan praktis
-- JANGAN buat judul tentang skenario fiktif (seperti "di luar angkasa", "di masa depan 2050", dll)
-- JANGAN buat judul tentang event yang sudah selesai di masa lalu
-- Judul harus bisa dicari di internet dan punya referensi nyata
-- Singkat, jelas, SEO-friendly, maksimal 10 kata per judul
-- JANGAN tambahkan nomor, tanda strip, atau penjelasan
-
-Balas HANYA daftar judul, satu per baris.`, currentDate, total, keyword)
-	} else {
-		prompt = fmt.Sprintf(`Kamu adalah editor blog teknologi profesional Indonesia.
-Hari ini tanggal %s.
-
-Buat %d judul artikel blog teknologi terkini dalam Bahasa Indonesia.
-
-ATURAN KETAT:
-- Topik: AI, cloud computing, programming, cybersecurity, startup Indonesia, mobile dev
-- Judul harus REALISTIS, faktual, bisa dicari referensinya
-- JANGAN buat judul fiksi atau spekulasi liar
-- Singkat, jelas, SEO-friendly, maksimal 10 kata per judul
-- JANGAN tambahkan nomor, tanda strip, atau penjelasan
-
-Balas HANYA daftar judul, satu per baris.`, currentDate, total)
+		return nil, templateId, err
 	}
 
-	response, err := askOllama(prompt)
+	response, err := AskLLM(LLMTaskTitles, prompt, ref)
 	if err != nil {
-		return nil, err
+		return nil, templateId, err
 	}
 
 	var titles []string
@@ -132,54 +82,41 @@ Balas HANYA daftar judul, satu per baris.`, currentDate, total)
 		titles = titles[:total]
 	}
 
-	return titles, nil
+	return titles, templateId, nil
 }
 
-// GenerateBlogContent meminta Ollama untuk menulis blog dari referensi artikel
-func GenerateBlogContent(title string, references []string) (string, string, error) {
-
-	// Gabungkan semua referensi
-	var refText string
-	for i, ref := range references {
-		refText += fmt.Sprintf("=== Referensi %d ===\n%s\n\n", i+1, ref)
-	}
-
-	prompt := fmt.Sprintf(`Kamu adalah Aibys, AI Assistant dari Arlchoose yang bertugas menulis artikel blog teknologi dalam Bahasa Indonesia.
+// GenerateBlogContent meminta LLM untuk menulis blog dari sumber bernomor.
+// issues berisi masalah percobaan sebelumnya dari quality gate (kosong untuk percobaan pertama).
+// onChunk (boleh nil) menerima potongan jawaban selama model masih menulis.
+// Mengembalikan description, content dan id versi prompt template yang dipakai.
+func GenerateBlogContent(title string, sources []ResearchSource, issues []models.QualityIssue, ref LLMUsageRef, onChunk func(chunk string)) (string, string, uint, error) {
 
-Judul artikel yang harus kamu tulis: "%s"
+	data := NewPromptData()
+	data.Title = title
+	data.SetSources(sources)
 
-Berikut adalah referensi artikel yang bisa kamu gunakan sebagai sumber informasi:
-%s
-
-Instruksi penulisan:
-- Tulis artikel yang informatif dan menarik dalam Bahasa Indonesia
-- JANGAN menyalin atau memparafrase referensi secara langsung, tulis dengan gaya dan perspektifmu sendiri
-- Gunakan informasi dari referensi sebagai dasar fakta, tapi sampaikan dengan cara yang unik
-- Format artikel menggunakan HTML (gunakan tag h2, h3, p, ul, li, strong, em)
-- Panjang artikel minimal 500 kata
-- Sertakan intro yang menarik dan kesimpulan yang berkesan
-- Tulis deskripsi singkat (1-2 kalimat) di awal sebelum konten HTML, pisahkan dengan tanda "---DESCRIPTION---" dan "---CONTENT---"
-
-Format response:
----DESCRIPTION---
-[deskripsi singkat artikel]
----CONTENT---
-[konten artikel dalam HTML]`, title, refText)
+	prompt, templateId, err := RenderPrompt(LLMTaskContent, data)
+	if err != nil {
+		return "", "", templateId, err
+	}
+	if len(issues) > 0 {
+		prompt += QualityCorrectionPrompt(issues)
+	}
 
-	response, err := askOllama(prompt)
+	var response string
+	if onChunk != nil {
+		response, err = AskLLMStream(context.Background(), LLMTaskContent, prompt, ref, onChunk)
+	} else {
+		response, err = AskLLM(LLMTaskContent, prompt, ref)
+	}
 	if err != nil {
-		return "", "", err
+		return "", "", templateId, err
 	}
 
 	// Parse description dan content dari response
 	description, content := parseOllamaResponse(response)
 
-	return description, content, nil
-}
-
-// AskOllama expose askOllama ke package lain
-func AskOllama(prompt string) (string, error) {
-	return askOllama(prompt)
+	return description, content, templateId, nil
 }
 
 // SplitLines memisahkan string jadi slice of lines
@@ -0,0 +1,194 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"arlchoose/backend-api/models"
+)
+
+// Tag HTML yang boleh ada di konten AI: yang diminta prompt plus markup aman yang umum dipakai model
+var qualityAllowedTags = map[string]bool{
+	"h2": true, "h3": true, "h4": true, "p": true, "ul": true, "ol": true, "li": true,
+	"strong": true, "em": true, "b": true, "i": true, "a": true, "sup": true, "br": true,
+	"blockquote": true, "code": true, "pre": true,
+	"table": true, "thead": true, "tbody": true, "tr": true, "th": true, "td": true,
+}
+
+var (
+	qualityTagPattern = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9]*)[^>]*>`)
+	// Sisa markdown: heading, bold, link dan daftar di awal baris
+	qualityMarkdownPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+\S[^\n]{0,30}`),
+		regexp.MustCompile(`\*\*[^*\n]+\*\*`),
+		regexp.MustCompile(`\[[^\]\n]+\]\((https?://|/)[^)\s]*\)`),
+		regexp.MustCompile(`(?m)^\s*[*+]\s+\S[^\n]{0,30}`),
+	}
+	// Potongan prompt yang tidak boleh ikut tertulis di artikel
+	qualityEchoMarkers = []string{
+		"---DESCRIPTION---", "---CONTENT---", "Instruksi penulisan", "Format response",
+		"[deskripsi singkat", "[konten artikel", "sumber bernomor",
+	}
+)
+
+// Kata fungsi yang sangat sering muncul, untuk menebak bahasa teks
+var (
+	indonesianWords = map[string]bool{
+		"yang": true, "dan": true, "di": true, "ini": true, "itu": true, "dengan": true, "untuk": true,
+		"tidak": true, "dari": true, "dalam": true, "akan": true, "pada": true, "juga": true, "ke": true,
+		"karena": true, "adalah": true, "bisa": true, "ada": true, "atau": true, "lebih": true,
+		"seperti": true, "oleh": true, "sudah": true, "saat": true, "kita": true, "anda": true,
+	}
+	englishWords = map[string]bool{
+		"the": true, "and": true, "of": true, "to": true, "is": true, "in": true, "that": true,
+		"it": true, "for": true, "with": true, "as": true, "are": true, "this": true, "be": true,
+		"on": true, "by": true, "was": true, "can": true, "from": true, "have": true, "you": true,
+	}
+)
+
+// QualityMinWords minimal jumlah kata artikel dari setting quality_min_words / env QUALITY_MIN_WORDS.
+// Prompt meminta 500 kata; default 400 karena model sering menghitung kurang.
+func QualityMinWords() int {
+	value := GetSetting("quality_min_words", os.Getenv("QUALITY_MIN_WORDS"))
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+		return n
+	}
+	return 400
+}
+
+// QualityMaxRetries berapa kali tulis ulang dengan prompt koreksi, dari setting quality_max_retries, default 2
+func QualityMaxRetries() int {
+	value := GetSetting("quality_max_retries", os.Getenv("QUALITY_MAX_RETRIES"))
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 5 {
+		return n
+	}
+	return 2
+}
+
+// ValidateQualitySetting cek nilai setting quality_* sebelum disimpan
+func ValidateQualitySetting(key string, value string) error {
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	switch key {
+	case "quality_min_words":
+		if err != nil || n < 0 || n > 5000 {
+			return errors.New("min words must be a number between 0 and 5000")
+		}
+	case "quality_max_retries":
+		if err != nil || n < 0 || n > 5 {
+			return errors.New("max retries must be a number between 0 and 5")
+		}
+	}
+	return nil
+}
+
+// CheckDraftQuality validasi hasil tulisan AI sebelum disimpan. Kosong berarti lolos.
+func CheckDraftQuality(description string, content string) []models.QualityIssue {
+
+	var issues []models.QualityIssue
+	add := func(code, detail string) {
+		issues = append(issues, models.QualityIssue{Code: code, Detail: detail})
+	}
+
+	if strings.TrimSpace(description) == "" {
+		add("missing_description", "description is empty or the separators were missing")
+	}
+
+	for _, marker := range qualityEchoMarkers {
+		if strings.Contains(content, marker) || strings.Contains(description, marker) {
+			add("prompt_echo", fmt.Sprintf("output contains prompt text %q", marker))
+			break
+		}
+	}
+
+	tags := qualityTagPattern.FindAllStringSubmatch(content, -1)
+	if len(tags) == 0 {
+		add("no_html", "content has no HTML markup")
+	}
+	disallowed := map[string]bool{}
+	for _, m := range tags {
+		if name := strings.ToLower(m[1]); !qualityAllowedTags[name] {
+			disallowed[name] = true
+		}
+	}
+	if len(disallowed) > 0 {
+		names := make([]string, 0, len(disallowed))
+		for name := range disallowed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		add("disallowed_tags", "content uses tags outside the allowed set: "+strings.Join(names, ", "))
+	}
+
+	for _, pattern := range qualityMarkdownPatterns {
+		if match := pattern.FindString(content); match != "" {
+			add("markdown", fmt.Sprintf("content contains markdown %q", TrimSpace(match)))
+			break
+		}
+	}
+
+	words := similarityWords(content)
+	if minWords := QualityMinWords(); len(words) < minWords {
+		add("too_short", fmt.Sprintf("content has %d words, minimum is %d", len(words), minWords))
+	}
+
+	if len(words) > 0 && !isIndonesian(words) {
+		add("language", "content does not look like Bahasa Indonesia")
+	}
+
+	return issues
+}
+
+// isIndonesian tebak bahasa dari porsi kata fungsi Indonesia vs Inggris
+func isIndonesian(words []string) bool {
+	id, en := 0, 0
+	for _, w := range words {
+		if indonesianWords[w] {
+			id++
+		}
+		if englishWords[w] {
+			en++
+		}
+	}
+	return id > en && float64(id)/float64(len(words)) >= 0.04
+}
+
+// qualityCorrections instruksi perbaikan untuk model, sesuai kode masalah
+var qualityCorrections = map[string]string{
+	"missing_description": `Tulis deskripsi singkat di antara tanda "---DESCRIPTION---" dan "---CONTENT---", persis seperti format response.`,
+	"prompt_echo":         "Jangan ulangi instruksi, format, atau isi prompt di dalam artikel.",
+	"no_html":             "Tulis konten dalam HTML (h2, h3, p, ul, li, strong, em), bukan teks biasa.",
+	"disallowed_tags":     "Gunakan HANYA tag h2, h3, h4, p, ul, ol, li, strong, em, a, blockquote, code, pre, dan table.",
+	"markdown":            "JANGAN gunakan markdown (#, **, [teks](url), atau daftar dengan *), HANYA HTML murni.",
+	"too_short":           "Artikel terlalu pendek. Tulis minimal 500 kata dengan pembahasan yang lebih mendalam.",
+	"language":            "Tulis seluruh artikel dalam Bahasa Indonesia.",
+}
+
+// QualityCorrectionPrompt tambahan prompt untuk percobaan ulang setelah draft ditolak
+func QualityCorrectionPrompt(issues []models.QualityIssue) string {
+	var b strings.Builder
+	b.WriteString("\n\nPERHATIAN: jawaban sebelumnya ditolak karena:\n")
+	for _, issue := range issues {
+		b.WriteString("- ")
+		b.WriteString(firstNonEmpty(qualityCorrections[issue.Code], issue.Detail))
+		b.WriteString("\n")
+	}
+	b.WriteString("Tulis ulang artikel secara lengkap dan pastikan masalah di atas tidak terulang.")
+	return b.String()
+}
+
+// QualityIssuesSummary gabungkan detail masalah jadi satu pesan error
+func QualityIssuesSummary(issues []models.QualityIssue) string {
+	details := make([]string, len(issues))
+	for i, issue := range issues {
+		details[i] = issue.Detail
+	}
+	return strings.Join(details, "; ")
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"arlchoose/backend-api/models"
+)
+
+// indonesianParagraph satu paragraf 20 kata berbahasa Indonesia
+const indonesianParagraph = "Ini adalah contoh paragraf yang ditulis dengan bahasa Indonesia untuk menguji pemeriksaan kualitas draft dari model bahasa di sistem ini."
+
+func repeatParagraphs(n int) string {
+	return strings.Repeat("<p>"+indonesianParagraph+"</p>\n", n)
+}
+
+func issueCodes(issues []models.QualityIssue) []string {
+	codes := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		codes = append(codes, issue.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func TestCheckDraftQuality(t *testing.T) {
+	t.Setenv("QUALITY_MIN_WORDS", "100")
+
+	valid := "<h2>Pendahuluan</h2>\n" + repeatParagraphs(6)
+
+	tests := []struct {
+		name        string
+		description string
+		content     string
+		want        []string
+	}{
+		{"valid draft", "Deskripsi singkat.", valid, nil},
+		{"missing description", "", valid, []string{"missing_description"}},
+		{"prompt echo", "Deskripsi.", valid + "<p>---CONTENT---</p>", []string{"prompt_echo"}},
+		{"plain text", "Deskripsi.", strings.Repeat(indonesianParagraph+" ", 6), []string{"no_html"}},
+		{"disallowed tags", "Deskripsi.", valid + `<script>alert(1)</script><div>x</div>`, []string{"disallowed_tags"}},
+		{"markdown heading", "Deskripsi.", "## Pendahuluan\n" + valid, []string{"markdown"}},
+		{"markdown bold", "Deskripsi.", valid + "<p>**penting**</p>", []string{"markdown"}},
+		{"too short", "Deskripsi.", repeatParagraphs(2), []string{"too_short"}},
+		{"english", "Description.", strings.Repeat("<p>This is the sample paragraph that is written in English and it is used for the test of the quality gate.</p>", 6), []string{"language"}},
+		{"several issues", "", "## Judul\n" + indonesianParagraph, []string{"markdown", "missing_description", "no_html", "too_short"}},
+	}
+
+	for _, tt := range tests {
+		got := issueCodes(CheckDraftQuality(tt.description, tt.content))
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: issues = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckDraftQualityMinWordsSetting(t *testing.T) {
+	content := repeatParagraphs(3) // 60 kata
+
+	t.Setenv("QUALITY_MIN_WORDS", "50")
+	if issues := CheckDraftQuality("Deskripsi.", content); len(issues) != 0 {
+		t.Errorf("expected no issues with min 50 words, got %v", issueCodes(issues))
+	}
+
+	t.Setenv("QUALITY_MIN_WORDS", "61")
+	if got := issueCodes(CheckDraftQuality("Deskripsi.", content)); strings.Join(got, ",") != "too_short" {
+		t.Errorf("expected too_short with min 61 words, got %v", got)
+	}
+}
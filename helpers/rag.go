@@ -0,0 +1,442 @@
+package helpers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/models"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ragChunkRunes     = 800
+	ragEmbedBatch     = 32
+	ragMaxSources     = 5
+	ragMaxChunks      = 8
+	RagQuestionMaxLen = 500
+	// Skor minimal TF-IDF; jauh lebih kecil dari embedding karena vektornya sparse
+	ragTfidfMinScore = 0.05
+)
+
+// RagChunk potongan konten portfolio yang bisa diambil untuk menjawab pertanyaan
+type RagChunk struct {
+	EntityType string
+	EntityId   uint
+	Title      string
+	Slug       string
+	Text       string
+}
+
+// RagSource sumber bernomor untuk sitasi [n] di jawaban
+type RagSource struct {
+	Number     int     `json:"number"`
+	EntityType string  `json:"entity_type"`
+	EntityId   uint    `json:"entity_id"`
+	Title      string  `json:"title"`
+	Slug       string  `json:"slug,omitempty"`
+	Score      float64 `json:"score"`
+	Content    string  `json:"-"`
+}
+
+// ragIndex potongan konten beserta vektornya, dibangun ulang kalau sudah lebih tua dari RAG_INDEX_TTL_MINUTES.
+// Vektor disimpan per hash teks supaya saat dibangun ulang hanya potongan yang berubah yang di-embed.
+// Lock hanya dipegang untuk membaca / menukar isi; index baru dibangun di luar lock.
+var ragIndex = struct {
+	sync.RWMutex
+	builtAt time.Time
+	chunks  []RagChunk
+	vectors [][]float64 // nil kalau embedding tidak tersedia → TF-IDF
+	model   string
+	cache   map[string][]float64
+}{cache: map[string][]float64{}}
+
+// ragBuildMu hanya satu proses bangun ulang index dalam satu waktu
+var ragBuildMu sync.Mutex
+
+// Pola pertanyaan yang mencoba mengganti instruksi sistem
+var ragInjectionPattern = regexp.MustCompile(`(?i)(ignore|disregard|forget)\s+(all\s+|the\s+|your\s+)?(previous|above|prior)?\s*(instructions|prompt|rules)|abaikan\s+(semua\s+)?(instruksi|perintah|aturan)|system\s+prompt|you\s+are\s+now|kamu\s+sekarang\s+adalah|jailbreak`)
+
+// RagGuard cek pertanyaan sebelum diproses. Kosong berarti boleh lanjut;
+// selain itu berisi alasan penolakan (invalid, injection).
+func RagGuard(question string) string {
+	if strings.TrimSpace(question) == "" || len([]rune(question)) > RagQuestionMaxLen {
+		return "invalid"
+	}
+	if ragInjectionPattern.MatchString(question) {
+		return "injection"
+	}
+	return ""
+}
+
+// RagRefusal jawaban tetap untuk pertanyaan di luar topik, mengikuti bahasa pertanyaan
+func RagRefusal(question string) string {
+	if isIndonesian(similarityWords(question)) {
+		return "Maaf, aku hanya bisa menjawab pertanyaan seputar profil, pengalaman, keahlian, project, dan tulisan di portfolio ini."
+	}
+	return "Sorry, I can only answer questions about the profile, experience, skills, projects, and articles on this portfolio."
+}
+
+// RetrieveRagSources cari potongan konten paling relevan, dikelompokkan per konten jadi sumber bernomor.
+// Kosong berarti tidak ada yang cukup relevan (pertanyaan di luar topik).
+func RetrieveRagSources(question string) ([]RagSource, string, error) {
+
+	if err := ensureRagIndex(); err != nil {
+		return nil, "", err
+	}
+
+	ragIndex.RLock()
+	chunks, vectors, model := ragIndex.chunks, ragIndex.vectors, ragIndex.model
+	ragIndex.RUnlock()
+
+	if len(chunks) == 0 {
+		return nil, "", nil
+	}
+
+	scores := make([]float64, len(chunks))
+	method, minScore := "tfidf", ragTfidfMinScore
+
+	var queryVector []float64
+	if vectors != nil {
+		if v, err := requestEmbeddings(model, []string{semanticPrefix(model, "search_query: ") + question}); err == nil {
+			queryVector = v[0]
+		} else {
+			log.Printf("[RAG] query embedding failed, using tf-idf: %v", err)
+		}
+	}
+
+	if queryVector != nil {
+		method, minScore = "embedding", ragMinScore()
+		for i, v := range vectors {
+			scores[i] = CosineSimilarity(queryVector, v)
+		}
+	} else {
+		texts := make([]string, len(chunks)+1)
+		for i, chunk := range chunks {
+			texts[i] = chunk.Title + " " + chunk.Text
+		}
+		texts[len(chunks)] = question
+		tfidf := TfidfVectors(texts)
+		for i := range chunks {
+			scores[i] = SparseCosine(tfidf[len(chunks)], tfidf[i])
+		}
+	}
+
+	order := make([]int, len(chunks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return scores[order[a]] > scores[order[b]] })
+
+	// Gabungkan potongan dari konten yang sama supaya satu konten = satu nomor sitasi
+	var sources []RagSource
+	bySource := map[string]int{}
+	used := 0
+	for _, i := range order {
+		if scores[i] < minScore || used == ragMaxChunks {
+			break
+		}
+		chunk := chunks[i]
+		key := chunk.EntityType + ":" + strconv.FormatUint(uint64(chunk.EntityId), 10)
+		if idx, ok := bySource[key]; ok {
+			sources[idx].Content += "\n" + chunk.Text
+			used++
+			continue
+		}
+		if len(sources) == ragMaxSources {
+			continue
+		}
+		bySource[key] = len(sources)
+		sources = append(sources, RagSource{
+			Number:     len(sources) + 1,
+			EntityType: chunk.EntityType,
+			EntityId:   chunk.EntityId,
+			Title:      chunk.Title,
+			Slug:       chunk.Slug,
+			Score:      float64(int(scores[i]*10000)) / 10000,
+			Content:    chunk.Text,
+		})
+		used++
+	}
+	return sources, method, nil
+}
+
+// RagPromptSources sumber untuk PromptData.Sources
+func RagPromptSources(sources []RagSource) []PromptSource {
+	out := make([]PromptSource, len(sources))
+	for i, src := range sources {
+		out[i] = PromptSource{Number: src.Number, Title: src.EntityType + ": " + src.Title, Content: src.Content}
+	}
+	return out
+}
+
+// ragMinScore skor cosine minimal dari env RAG_MIN_SCORE, default 0.45
+func ragMinScore() float64 {
+	if f, err := strconv.ParseFloat(os.Getenv("RAG_MIN_SCORE"), 64); err == nil && f >= 0 && f <= 1 {
+		return f
+	}
+	return 0.45
+}
+
+// ensureRagIndex pastikan index tersedia. Index pertama dibangun langsung (request menunggu); index yang
+// kedaluwarsa tetap dipakai sementara versi barunya dibangun di background.
+func ensureRagIndex() error {
+
+	ragIndex.RLock()
+	built, stale := !ragIndex.builtAt.IsZero(), ragIndexStale()
+	ragIndex.RUnlock()
+
+	switch {
+	case !built:
+		ragBuildMu.Lock()
+		defer ragBuildMu.Unlock()
+		// Bisa jadi sudah dibangun request lain selagi menunggu
+		ragIndex.RLock()
+		built = !ragIndex.builtAt.IsZero()
+		ragIndex.RUnlock()
+		if !built {
+			rebuildRagIndex()
+		}
+	case stale:
+		if ragBuildMu.TryLock() {
+			go func() {
+				defer ragBuildMu.Unlock()
+				rebuildRagIndex()
+			}()
+		}
+	}
+	return nil
+}
+
+// ragIndexStale index lebih tua dari RAG_INDEX_TTL_MINUTES (default 10) atau model embedding sudah diganti.
+// Dipanggil dengan ragIndex terkunci.
+func ragIndexStale() bool {
+	minutes, err := strconv.Atoi(os.Getenv("RAG_INDEX_TTL_MINUTES"))
+	if err != nil || minutes < 1 {
+		minutes = 10
+	}
+	return time.Since(ragIndex.builtAt) >= time.Duration(minutes)*time.Minute || ragIndex.model != EmbeddingModel()
+}
+
+// rebuildRagIndex potong dan embed ulang semua konten tanpa memegang lock index, lalu tukar isinya sekaligus.
+// Pemanggil harus memegang ragBuildMu.
+func rebuildRagIndex() {
+
+	ragIndex.RLock()
+	cache := ragIndex.cache
+	ragIndex.RUnlock()
+
+	chunks := buildRagChunks()
+	model := EmbeddingModel()
+	vectors, cache, err := embedRagChunks(chunks, model, cache)
+	if err != nil {
+		log.Printf("[RAG] chunk embeddings unavailable, using tf-idf: %v", err)
+	}
+
+	ragIndex.Lock()
+	ragIndex.chunks = chunks
+	ragIndex.vectors = vectors
+	ragIndex.model = model
+	ragIndex.cache = cache
+	ragIndex.builtAt = time.Now()
+	ragIndex.Unlock()
+}
+
+// embedRagChunks vektor setiap potongan, memakai cache hash teks dari index sebelumnya.
+// Cache baru hanya berisi potongan yang masih ada; kalau gagal, cache lama dikembalikan utuh.
+func embedRagChunks(chunks []RagChunk, model string, cache map[string][]float64) ([][]float64, map[string][]float64, error) {
+
+	if EmbeddingProvider() == "tfidf" {
+		return nil, cache, ErrSemanticDisabled
+	}
+
+	keys := make([]string, len(chunks))
+	vectors := make([][]float64, len(chunks))
+	var missing []int
+	for i, chunk := range chunks {
+		keys[i] = model + ":" + ContentHash(chunk.Text)
+		if v, ok := cache[keys[i]]; ok {
+			vectors[i] = v
+		} else {
+			missing = append(missing, i)
+		}
+	}
+
+	for start := 0; start < len(missing); start += ragEmbedBatch {
+		batch := missing[start:min(start+ragEmbedBatch, len(missing))]
+		texts := make([]string, len(batch))
+		for j, i := range batch {
+			texts[j] = semanticPrefix(model, "search_document: ") + chunks[i].Title + "\n" + chunks[i].Text
+		}
+		embeddings, err := requestEmbeddings(model, texts)
+		if err != nil {
+			return nil, cache, err
+		}
+		for j, i := range batch {
+			vectors[i] = embeddings[j]
+		}
+	}
+
+	fresh := make(map[string][]float64, len(chunks))
+	for i, key := range keys {
+		fresh[key] = vectors[i]
+	}
+	return vectors, fresh, nil
+}
+
+// buildRagChunks potong semua konten publik portfolio: profil, pengalaman, project, skill, kursus,
+// pendidikan, dan blog yang sudah publish
+func buildRagChunks() []RagChunk {
+
+	var chunks []RagChunk
+	add := func(entityType string, id uint, title string, slug string, text string) {
+		for _, part := range splitRagText(text, ragChunkRunes) {
+			chunks = append(chunks, RagChunk{EntityType: entityType, EntityId: id, Title: title, Slug: slug, Text: part})
+		}
+	}
+
+	var profile models.Profile
+	if database.DB.First(&profile).Error == nil {
+		add("profile", profile.Id, profile.Name, "", joinNonEmpty(". ",
+			profile.Name, profile.Tagline, profile.Bio, labeled("Lokasi", profile.Location),
+			labeled("GitHub", profile.Github), labeled("LinkedIn", profile.Linkedin)))
+	}
+
+	var experiences []models.Experience
+	database.DB.Order("start_date desc").Find(&experiences)
+	for _, e := range experiences {
+		period := ragPeriod(e.StartDate, e.EndDate, e.IsCurrent)
+		add("experience", e.Id, e.Role+" - "+e.Company, "", joinNonEmpty(". ",
+			fmt.Sprintf("%s di %s", e.Role, e.Company), period, labeled("Lokasi", e.Location), e.Description))
+	}
+
+	var projects []models.Project
+	database.DB.Preload("TechStacks").Find(&projects)
+	for _, p := range projects {
+		var stack []string
+		for _, s := range p.TechStacks {
+			stack = append(stack, s.Name)
+		}
+		add("project", p.Id, p.Title, p.Slug, joinNonEmpty(". ",
+			"Project "+p.Title, labeled("Platform", p.Platform), labeled("Tech stack", strings.Join(stack, ", ")),
+			p.Description, labeled("URL", p.Url)))
+	}
+
+	// Skill digabung per kategori, satu baris per skill terlalu pendek untuk di-embed
+	var skills []models.Skill
+	database.DB.Order("category asc, `order` asc").Find(&skills)
+	byCategory := map[string][]string{}
+	var categories []string
+	firstId := map[string]uint{}
+	for _, s := range skills {
+		if _, ok := byCategory[s.Category]; !ok {
+			categories = append(categories, s.Category)
+			firstId[s.Category] = s.Id
+		}
+		byCategory[s.Category] = append(byCategory[s.Category], fmt.Sprintf("%s (%s)", s.Name, s.Level))
+	}
+	for _, category := range categories {
+		add("skill", firstId[category], "Skill "+category, "", "Skill "+category+": "+strings.Join(byCategory[category], ", "))
+	}
+
+	var courses []models.Course
+	database.DB.Find(&courses)
+	for _, c := range courses {
+		issued := ""
+		if c.IssuedAt != nil {
+			issued = "Terbit " + c.IssuedAt.Format("January 2006")
+		}
+		add("course", c.Id, c.Title, "", joinNonEmpty(". ",
+			"Sertifikat/kursus "+c.Title, labeled("Penerbit", c.Issuer), issued, c.Description))
+	}
+
+	var educations []models.Education
+	database.DB.Find(&educations)
+	for _, e := range educations {
+		years := ""
+		if e.StartYear > 0 {
+			years = strconv.Itoa(e.StartYear) + " - "
+			if e.EndYear > 0 {
+				years += strconv.Itoa(e.EndYear)
+			} else {
+				years += "sekarang"
+			}
+		}
+		add("education", e.Id, e.School, "", joinNonEmpty(". ",
+			"Pendidikan di "+e.School, joinNonEmpty(" ", e.Degree, e.Field), years, e.Description))
+	}
+
+	var blogs []models.Blog
+	database.DB.Select("id", "title", "slug", "description", "content").Where("status = ?", "published").Find(&blogs)
+	for _, b := range blogs {
+		add("blog", b.Id, b.Title, b.Slug, joinNonEmpty("\n", "Artikel: "+b.Title, b.Description, HtmlToText(b.Content)))
+	}
+
+	return chunks
+}
+
+// splitRagText potong teks per kalimat jadi bagian maksimal size rune
+func splitRagText(text string, size int) []string {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return nil
+	}
+
+	var parts []string
+	var current strings.Builder
+	for _, sentence := range strings.SplitAfter(text, ". ") {
+		if current.Len() > 0 && len([]rune(current.String()))+len([]rune(sentence)) > size {
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+		// Kalimat yang sangat panjang dipotong paksa
+		for len([]rune(sentence)) > size {
+			r := []rune(sentence)
+			parts = append(parts, strings.TrimSpace(string(r[:size])))
+			sentence = string(r[size:])
+		}
+		current.WriteString(sentence)
+	}
+	if s := strings.TrimSpace(current.String()); s != "" {
+		parts = append(parts, s)
+	}
+	return parts
+}
+
+func ragPeriod(start *time.Time, end *time.Time, current bool) string {
+	if start == nil {
+		return ""
+	}
+	period := start.Format("January 2006") + " - "
+	switch {
+	case current:
+		period += "sekarang"
+	case end != nil:
+		period += end.Format("January 2006")
+	default:
+		period = start.Format("January 2006")
+	}
+	return period
+}
+
+func labeled(label string, value string) string {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+	return label + ": " + value
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
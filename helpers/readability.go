@@ -0,0 +1,541 @@
+package helpers
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Article hasil ekstraksi halaman artikel, mirip Readability milik Firefox
+type Article struct {
+	Url         string     `json:"url"`
+	Title       string     `json:"title"`
+	Byline      string     `json:"byline"`
+	SiteName    string     `json:"site_name"`
+	Excerpt     string     `json:"excerpt"`
+	LeadImage   string     `json:"lead_image"`
+	PublishedAt *time.Time `json:"published_at"`
+	// Text isi artikel, satu paragraf per baris
+	Text string `json:"text"`
+	// Length jumlah karakter (rune) Text sebelum dipotong
+	Length    int  `json:"length"`
+	Truncated bool `json:"truncated"`
+}
+
+// Batas isi artikel untuk prompt AI
+const MaxArticleRunes = 3000
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)-ad-|ai2html|baca-juga|bacajuga|banner|breadcrumbs|read-also|readmore|combx|comment|community|consent|cookie|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|modal|newsletter|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental|ad-break|agegate|pagination|pager|popup|promo|tags|widget`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveClass      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story|detail|isi`)
+	negativeClass      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|footer|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|widget|baca-juga|bacajuga`)
+	titleSeparator     = regexp.MustCompile(`\s+[|\-–—»:]\s+`)
+	spaceRun           = regexp.MustCompile(`[ \t\p{Zs}]+`)
+)
+
+// ExtractArticle cari isi utama dan metadata dari dokumen HTML.
+// Dokumen dimodifikasi (elemen yang tidak relevan dihapus).
+func ExtractArticle(doc *goquery.Document) *Article {
+
+	article := &Article{}
+	if doc.Url != nil {
+		article.Url = doc.Url.String()
+	}
+
+	// Metadata dibaca dulu sebelum script (JSON-LD) dan header dihapus
+	ld := readJsonLdArticle(doc)
+	article.Title = extractTitle(doc, ld)
+	article.Byline = extractByline(doc, ld)
+	article.SiteName = metaContent(doc, `meta[property="og:site_name"]`)
+	article.PublishedAt = extractPublishedAt(doc, ld)
+	article.LeadImage = extractLeadImage(doc, ld, article.Url)
+	article.Excerpt = firstNonEmpty(
+		metaContent(doc, `meta[property="og:description"]`),
+		metaContent(doc, `meta[name="description"]`),
+		metaContent(doc, `meta[name="twitter:description"]`),
+	)
+
+	prepareDocument(doc)
+
+	top := findTopCandidate(doc)
+	var blocks []string
+	if top != nil {
+		blocks = collectArticleBlocks(top)
+	}
+	if len(blocks) == 0 {
+		blocks = collectArticleBlocks(doc.Find("body"))
+	}
+
+	article.Text = strings.Join(blocks, "\n")
+	article.Length = utf8.RuneCountInString(article.Text)
+	if article.Excerpt == "" && len(blocks) > 0 {
+		article.Excerpt, _ = TruncateText(blocks[0], 200)
+	}
+	if article.LeadImage == "" && top != nil {
+		if src, ok := top.Find("img[src]").First().Attr("src"); ok && article.Url != "" {
+			article.LeadImage, _ = resolveUrl(article.Url, src)
+		}
+	}
+
+	return article
+}
+
+// prepareDocument hapus elemen yang pasti bukan isi artikel
+func prepareDocument(doc *goquery.Document) {
+	doc.Find("script, style, noscript, iframe, form, svg, button, input, select, textarea, nav, aside, footer, link, meta, template").Remove()
+	doc.Find(`[hidden], [aria-hidden="true"], [role="dialog"], [role="alertdialog"], [role="navigation"], [role="complementary"]`).Remove()
+	doc.Find("[style]").Each(func(_ int, s *goquery.Selection) {
+		style := strings.ReplaceAll(strings.ToLower(s.AttrOr("style", "")), " ", "")
+		if strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+			s.Remove()
+		}
+	})
+
+	// Elemen yang class/id-nya mirip banner, komentar, cookie, dll
+	doc.Find("body *").Each(func(_ int, s *goquery.Selection) {
+		tag := goquery.NodeName(s)
+		if tag == "body" || tag == "article" || tag == "main" || tag == "a" {
+			return
+		}
+		match := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikelyCandidates.MatchString(match) && !maybeCandidate.MatchString(match) {
+			s.Remove()
+		}
+	})
+}
+
+// findTopCandidate skor setiap paragraf ke parent dan kakeknya, lalu ambil yang skornya tertinggi
+func findTopCandidate(doc *goquery.Document) *goquery.Selection {
+
+	scores := map[*html.Node]float64{}
+	var order []*html.Node
+
+	initScore := func(n *html.Node) {
+		if _, ok := scores[n]; ok {
+			return
+		}
+		s := goquery.NewDocumentFromNode(n).Selection
+		scores[n] = tagWeight(n.Data) + classWeight(s)
+		order = append(order, n)
+	}
+
+	doc.Find("p, pre, td, blockquote, div").Each(func(_ int, s *goquery.Selection) {
+		// div hanya dihitung kalau isinya teks langsung (tidak punya blok di dalamnya)
+		if goquery.NodeName(s) == "div" && s.Find("p, div, table, ul, ol, pre, blockquote, h1, h2, h3, h4, h5, h6").Length() > 0 {
+			return
+		}
+
+		text := normalizeSpace(s.Text())
+		if utf8.RuneCountInString(text) < 25 {
+			return
+		}
+
+		score := 1.0
+		score += float64(strings.Count(text, ",") + strings.Count(text, "،"))
+		score += min(float64(utf8.RuneCountInString(text))/100, 3)
+
+		node := s.Get(0).Parent
+		for level := 0; node != nil && node.Type == html.ElementNode && level < 5; level++ {
+			if node.Data == "body" || node.Data == "html" {
+				break
+			}
+			initScore(node)
+			divider := 1.0
+			switch {
+			case level == 1:
+				divider = 2
+			case level > 1:
+				divider = float64(level) * 3
+			}
+			scores[node] += score / divider
+			node = node.Parent
+		}
+	})
+
+	if len(order) == 0 {
+		return nil
+	}
+
+	// Skor akhir dikurangi kepadatan link, supaya daftar link/menu tidak menang
+	final := make(map[*html.Node]float64, len(order))
+	for _, n := range order {
+		final[n] = scores[n] * (1 - linkDensity(goquery.NewDocumentFromNode(n).Selection))
+	}
+	sort.SliceStable(order, func(i, j int) bool { return final[order[i]] > final[order[j]] })
+
+	topNode := order[0]
+	top := doc.FindNodes(topNode)
+
+	// Gabungkan sibling yang juga isi artikel (misal artikel dipecah jadi beberapa div)
+	threshold := max(10, final[topNode]*0.2)
+	var keep []*html.Node
+	for sib := topNode.Parent.FirstChild; sib != nil; sib = sib.NextSibling {
+		if sib.Type != html.ElementNode {
+			continue
+		}
+		if sib == topNode {
+			keep = append(keep, sib)
+			continue
+		}
+		if score, ok := final[sib]; ok && score >= threshold {
+			keep = append(keep, sib)
+			continue
+		}
+		if sib.Data == "p" {
+			s := goquery.NewDocumentFromNode(sib).Selection
+			text := normalizeSpace(s.Text())
+			density := linkDensity(s)
+			n := utf8.RuneCountInString(text)
+			if (n > 80 && density < 0.25) || (n > 0 && density == 0 && strings.HasSuffix(text, ".")) {
+				keep = append(keep, sib)
+			}
+		}
+	}
+	if len(keep) > 1 {
+		return doc.FindNodes(keep...)
+	}
+	return top
+}
+
+// collectArticleBlocks ambil teks per blok (paragraf, subjudul, item list) dengan urutan asli
+func collectArticleBlocks(sel *goquery.Selection) []string {
+	var blocks []string
+	seen := map[string]bool{}
+
+	sel.Find("p, h2, h3, h4, h5, h6, li, pre, blockquote, figcaption").Each(func(_ int, s *goquery.Selection) {
+		// Blok di dalam blok lain (misal p di dalam blockquote) sudah terbaca lewat parent-nya
+		if s.ParentsFiltered("p, li, pre, blockquote").Length() > 0 {
+			return
+		}
+		text := normalizeSpace(s.Text())
+		if text == "" || seen[text] {
+			return
+		}
+		// Blok pendek yang isinya hampir semua link biasanya "baca juga" atau tag
+		if utf8.RuneCountInString(text) < 120 && linkDensity(s) > 0.5 {
+			return
+		}
+		seen[text] = true
+		blocks = append(blocks, text)
+	})
+
+	if len(blocks) == 0 {
+		for _, line := range strings.Split(sel.Text(), "\n") {
+			if line = normalizeSpace(line); line != "" {
+				blocks = append(blocks, line)
+			}
+		}
+	}
+	return blocks
+}
+
+// TruncateText potong teks maksimal maxRunes karakter tanpa memotong rune UTF-8.
+// Kalau bisa dipotong di akhir kalimat, kalau tidak di batas kata.
+func TruncateText(text string, maxRunes int) (string, bool) {
+	if utf8.RuneCountInString(text) <= maxRunes {
+		return text, false
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:maxRunes])
+
+	// Akhir kalimat terakhir, asal tidak membuang lebih dari separuh
+	best := -1
+	for i, r := range cut {
+		if r == '.' || r == '!' || r == '?' || r == '\n' {
+			next := i + utf8.RuneLen(r)
+			if next == len(cut) || cut[next] == ' ' || cut[next] == '\n' || r == '\n' {
+				best = next
+			}
+		}
+	}
+	if best > len(cut)/2 {
+		return strings.TrimSpace(cut[:best]), true
+	}
+
+	if i := strings.LastIndexAny(cut, " \n"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimSpace(cut) + "…", true
+}
+
+func linkDensity(s *goquery.Selection) float64 {
+	textLen := utf8.RuneCountInString(normalizeSpace(s.Text()))
+	if textLen == 0 {
+		return 0
+	}
+	linkLen := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLen += utf8.RuneCountInString(normalizeSpace(a.Text()))
+	})
+	return min(float64(linkLen)/float64(textLen), 1)
+}
+
+func tagWeight(tag string) float64 {
+	switch tag {
+	case "article":
+		return 10
+	case "div", "main", "section":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+	return 0
+}
+
+func classWeight(s *goquery.Selection) float64 {
+	weight := 0.0
+	for _, attr := range []string{"class", "id"} {
+		value := s.AttrOr(attr, "")
+		if value == "" {
+			continue
+		}
+		if negativeClass.MatchString(value) {
+			weight -= 25
+		}
+		if positiveClass.MatchString(value) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+func normalizeSpace(s string) string {
+	return strings.TrimSpace(spaceRun.ReplaceAllString(strings.ReplaceAll(s, "\n", " "), " "))
+}
+
+func metaContent(doc *goquery.Document, selector string) string {
+	return normalizeSpace(doc.Find(selector).First().AttrOr("content", ""))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// jsonLdArticle field schema.org Article yang dipakai
+type jsonLdArticle struct {
+	Headline      string          `json:"headline"`
+	DatePublished string          `json:"datePublished"`
+	Author        json.RawMessage `json:"author"`
+	Image         json.RawMessage `json:"image"`
+}
+
+var jsonLdArticleTypes = map[string]bool{
+	"Article": true, "NewsArticle": true, "BlogPosting": true, "TechArticle": true, "Report": true, "ReportageNewsArticle": true,
+}
+
+// readJsonLdArticle cari objek Article di <script type="application/ld+json">, termasuk di dalam @graph
+func readJsonLdArticle(doc *goquery.Document) *jsonLdArticle {
+	var found *jsonLdArticle
+
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var raw any
+		if json.Unmarshal([]byte(s.Text()), &raw) != nil {
+			return true
+		}
+		found = findJsonLdArticle(raw)
+		return found == nil
+	})
+	return found
+}
+
+func findJsonLdArticle(raw any) *jsonLdArticle {
+	switch v := raw.(type) {
+	case []any:
+		for _, item := range v {
+			if a := findJsonLdArticle(item); a != nil {
+				return a
+			}
+		}
+	case map[string]any:
+		if graph, ok := v["@graph"]; ok {
+			if a := findJsonLdArticle(graph); a != nil {
+				return a
+			}
+		}
+		if jsonLdHasType(v["@type"]) {
+			b, _ := json.Marshal(v)
+			var a jsonLdArticle
+			if json.Unmarshal(b, &a) == nil {
+				return &a
+			}
+		}
+	}
+	return nil
+}
+
+func jsonLdHasType(t any) bool {
+	switch v := t.(type) {
+	case string:
+		return jsonLdArticleTypes[v]
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok && jsonLdArticleTypes[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLdName ambil "name" dari string, objek, atau array objek (author/image)
+func jsonLdName(raw json.RawMessage, field string) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var obj map[string]any
+	if json.Unmarshal(raw, &obj) == nil {
+		if v, ok := obj[field].(string); ok {
+			return v
+		}
+		return ""
+	}
+	var list []json.RawMessage
+	if json.Unmarshal(raw, &list) == nil {
+		var names []string
+		for _, item := range list {
+			if name := jsonLdName(item, field); name != "" {
+				names = append(names, name)
+			}
+		}
+		if field == "url" && len(names) > 0 {
+			return names[0]
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func extractTitle(doc *goquery.Document, ld *jsonLdArticle) string {
+	if title := metaContent(doc, `meta[property="og:title"]`); title != "" {
+		return title
+	}
+	if ld != nil && ld.Headline != "" {
+		return normalizeSpace(ld.Headline)
+	}
+	if h1 := doc.Find("h1"); h1.Length() == 1 {
+		if title := normalizeSpace(h1.Text()); title != "" {
+			return title
+		}
+	}
+
+	// <title> biasanya "Judul Artikel | Nama Situs", ambil bagian judulnya
+	title := normalizeSpace(doc.Find("title").First().Text())
+	if parts := titleSeparator.Split(title, -1); len(parts) > 1 {
+		longest := parts[0]
+		for _, p := range parts[1:] {
+			if len(p) > len(longest) {
+				longest = p
+			}
+		}
+		if len(strings.Fields(longest)) >= 3 {
+			return longest
+		}
+	}
+	return title
+}
+
+func extractByline(doc *goquery.Document, ld *jsonLdArticle) string {
+	if ld != nil {
+		if name := jsonLdName(ld.Author, "name"); name != "" {
+			return name
+		}
+	}
+	if author := metaContent(doc, `meta[name="author"]`); author != "" {
+		return author
+	}
+	if author := metaContent(doc, `meta[property="article:author"]`); author != "" && !strings.HasPrefix(author, "http") {
+		return author
+	}
+	for _, selector := range []string{`[itemprop="author"] [itemprop="name"]`, `[itemprop="author"]`, `[rel="author"]`, ".byline", ".author"} {
+		if text := normalizeSpace(doc.Find(selector).First().Text()); text != "" && utf8.RuneCountInString(text) < 100 {
+			return text
+		}
+	}
+	return ""
+}
+
+var publishedLayouts = []string{
+	time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02", time.RFC1123Z, time.RFC1123,
+}
+
+func extractPublishedAt(doc *goquery.Document, ld *jsonLdArticle) *time.Time {
+	candidates := []string{}
+	if ld != nil {
+		candidates = append(candidates, ld.DatePublished)
+	}
+	candidates = append(candidates,
+		metaContent(doc, `meta[property="article:published_time"]`),
+		metaContent(doc, `meta[name="pubdate"]`),
+		metaContent(doc, `meta[name="publishdate"]`),
+		metaContent(doc, `meta[name="date"]`),
+		metaContent(doc, `meta[name="DC.date.issued"]`),
+		doc.Find(`[itemprop="datePublished"]`).First().AttrOr("content", doc.Find(`[itemprop="datePublished"]`).First().AttrOr("datetime", "")),
+		doc.Find("time[datetime]").First().AttrOr("datetime", ""),
+	)
+
+	for _, value := range candidates {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		for _, layout := range publishedLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
+
+func extractLeadImage(doc *goquery.Document, ld *jsonLdArticle, base string) string {
+	image := firstNonEmpty(
+		metaContent(doc, `meta[property="og:image"]`),
+		metaContent(doc, `meta[name="twitter:image"]`),
+		doc.Find(`link[rel="image_src"]`).First().AttrOr("href", ""),
+	)
+	if image == "" && ld != nil {
+		image = jsonLdName(ld.Image, "url")
+	}
+	if image == "" || base == "" {
+		return image
+	}
+	if resolved, err := resolveUrl(base, image); err == nil {
+		return resolved
+	}
+	return image
+}
+
+// ScrapeArticle ambil halaman dan ekstrak artikelnya, isi dipotong maksimal MaxArticleRunes karakter
+func ScrapeArticle(articleUrl string) (*Article, error) {
+
+	doc, _, err := FetchDocument(articleUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	article := ExtractArticle(doc)
+	article.Text, article.Truncated = TruncateText(article.Text, MaxArticleRunes)
+
+	return article, nil
+}
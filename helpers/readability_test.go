@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		max       int
+		want      string
+		truncated bool
+	}{
+		{"fits", "Halo dunia.", 20, "Halo dunia.", false},
+		{"exact length", "Halo dunia.", 11, "Halo dunia.", false},
+		{"cut at sentence end", "Kalimat pertama. Kalimat kedua yang panjang.", 30, "Kalimat pertama.", true},
+		{"cut at word boundary", "satu dua tiga empat lima enam", 17, "satu dua tiga…", true},
+		{"sentence end too early", "Ya. kemudian panjang sekali teksnya", 20, "Ya. kemudian…", true},
+		{"newline counts as sentence end", "Baris pertama\nbaris kedua yang panjang", 20, "Baris pertama", true},
+		{"multibyte runes", "ééééééééé", 4, "éééé…", true},
+	}
+
+	for _, tt := range tests {
+		got, truncated := TruncateText(tt.text, tt.max)
+		if got != tt.want || truncated != tt.truncated {
+			t.Errorf("%s: TruncateText() = (%q, %v), want (%q, %v)", tt.name, got, truncated, tt.want, tt.truncated)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("%s: result is not valid UTF-8: %q", tt.name, got)
+		}
+	}
+}
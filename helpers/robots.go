@@ -0,0 +1,250 @@
+package helpers
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// robotsRules aturan robots.txt yang berlaku untuk bot kita di satu host
+type robotsRules struct {
+	allow       []string
+	disallow    []string
+	disallowAll bool
+	crawlDelay  time.Duration
+	expiresAt   time.Time
+}
+
+const maxCrawlDelay = 30 * time.Second
+
+var (
+	robotsMu    sync.Mutex
+	robotsCache = map[string]*robotsRules{}
+	// robotsFetching cegah beberapa goroutine mengambil robots.txt host yang sama bersamaan
+	robotsFetching = map[string]*sync.Mutex{}
+)
+
+func respectRobots() bool {
+	return os.Getenv("FETCH_RESPECT_ROBOTS") != "false"
+}
+
+// checkRobots kembalikan ErrRobotsDisallowed kalau url dilarang untuk bot kita
+func checkRobots(u *url.URL) error {
+	if !respectRobots() {
+		return nil
+	}
+	rules := robotsFor(u.Scheme, u.Host)
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if !rules.Allowed(path) {
+		return ErrRobotsDisallowed
+	}
+	return nil
+}
+
+// robotsCrawlDelay Crawl-delay host dari cache (tanpa fetch), 0 kalau belum diketahui
+func robotsCrawlDelay(host string) time.Duration {
+	if !respectRobots() {
+		return 0
+	}
+	robotsMu.Lock()
+	defer robotsMu.Unlock()
+	if rules, ok := robotsCache[host]; ok {
+		return rules.crawlDelay
+	}
+	return 0
+}
+
+// robotsFor ambil aturan robots.txt host, di-cache 24 jam
+func robotsFor(scheme, host string) *robotsRules {
+
+	robotsMu.Lock()
+	if rules, ok := robotsCache[host]; ok && time.Now().Before(rules.expiresAt) {
+		robotsMu.Unlock()
+		return rules
+	}
+	lock, ok := robotsFetching[host]
+	if !ok {
+		lock = &sync.Mutex{}
+		robotsFetching[host] = lock
+	}
+	robotsMu.Unlock()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Goroutine lain mungkin sudah selesai mengambil selama kita menunggu
+	robotsMu.Lock()
+	if rules, ok := robotsCache[host]; ok && time.Now().Before(rules.expiresAt) {
+		robotsMu.Unlock()
+		return rules
+	}
+	robotsMu.Unlock()
+
+	rules := fetchRobots(scheme, host)
+
+	robotsMu.Lock()
+	robotsCache[host] = rules
+	robotsMu.Unlock()
+	return rules
+}
+
+// fetchRobots ambil dan parse robots.txt. Sesuai RFC 9309: 4xx berarti boleh semua,
+// 5xx berarti dilarang semua (dicoba lagi 1 jam kemudian), gagal koneksi dianggap boleh.
+func fetchRobots(scheme, host string) *robotsRules {
+
+	req, err := http.NewRequest(http.MethodGet, scheme+"://"+host+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{expiresAt: time.Now().Add(time.Hour)}
+	}
+	req.Header.Set("User-Agent", FetchUserAgent())
+
+	client := &http.Client{Timeout: 10 * time.Second, Transport: fetchTransport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return &robotsRules{expiresAt: time.Now().Add(time.Hour)}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return &robotsRules{disallowAll: true, expiresAt: time.Now().Add(time.Hour)}
+	case resp.StatusCode != http.StatusOK:
+		return &robotsRules{expiresAt: time.Now().Add(24 * time.Hour)}
+	}
+
+	// RFC 9309 minta minimal 500 KiB diproses
+	rules := parseRobots(io.LimitReader(resp.Body, 512<<10), fetchBotName())
+	rules.expiresAt = time.Now().Add(24 * time.Hour)
+	return rules
+}
+
+// parseRobots ambil grup User-agent yang paling cocok dengan bot; kalau tidak ada, pakai grup "*"
+func parseRobots(r io.Reader, bot string) *robotsRules {
+
+	type group struct {
+		agents   []string
+		allow    []string
+		disallow []string
+		delay    time.Duration
+	}
+
+	var groups []*group
+	var current *group
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+
+		if field == "user-agent" {
+			// Beberapa baris User-agent berurutan membentuk satu grup
+			if !lastWasAgent || current == nil {
+				current = &group{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+			continue
+		}
+		lastWasAgent = false
+		if current == nil {
+			continue
+		}
+
+		switch field {
+		case "allow":
+			if value != "" {
+				current.allow = append(current.allow, value)
+			}
+		case "disallow":
+			if value != "" {
+				current.disallow = append(current.disallow, value)
+			}
+		case "crawl-delay":
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.delay = min(time.Duration(seconds*float64(time.Second)), maxCrawlDelay)
+			}
+		}
+	}
+
+	rules := &robotsRules{}
+	var matched, wildcard []*group
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			if agent == "*" {
+				wildcard = append(wildcard, g)
+			} else if bot != "" && strings.Contains(bot, agent) {
+				matched = append(matched, g)
+			}
+		}
+	}
+	if len(matched) == 0 {
+		matched = wildcard
+	}
+	for _, g := range matched {
+		rules.allow = append(rules.allow, g.allow...)
+		rules.disallow = append(rules.disallow, g.disallow...)
+		rules.crawlDelay = max(rules.crawlDelay, g.delay)
+	}
+	return rules
+}
+
+// Allowed aturan paling spesifik (pattern terpanjang) menang, Allow menang kalau sama panjang
+func (r *robotsRules) Allowed(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+	best, allowed := -1, true
+	for _, pattern := range r.disallow {
+		if len(pattern) > best && robotsMatch(pattern, path) {
+			best, allowed = len(pattern), false
+		}
+	}
+	for _, pattern := range r.allow {
+		if len(pattern) >= best && robotsMatch(pattern, path) {
+			best, allowed = len(pattern), true
+		}
+	}
+	return allowed
+}
+
+// robotsMatch cocokkan path dengan pattern robots.txt (prefix, * wildcard, $ akhir)
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	pos := len(parts[0])
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 && anchored {
+			return strings.HasSuffix(path[pos:], part)
+		}
+		idx := strings.Index(path[pos:], part)
+		if idx < 0 {
+			return false
+		}
+		pos += idx + len(part)
+	}
+	return !anchored || pos == len(path)
+}
@@ -0,0 +1,269 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchResult satu hasil pencarian dari provider mana pun
+type SearchResult struct {
+	Title       string `json:"title"`
+	Url         string `json:"url"`
+	Description string `json:"description"`
+	Provider    string `json:"provider,omitempty"`
+}
+
+// SearchOptions pengaturan pencarian. Lang kode bahasa ISO (id, en), Country kode negara (ID, US),
+// Freshness salah satu dari day, week, month, year atau kosong.
+type SearchOptions struct {
+	Lang      string
+	Country   string
+	Freshness string
+	Count     int
+}
+
+// SearchProvider sumber riset untuk generator blog
+type SearchProvider interface {
+	Name() string
+	// Configured false kalau kredensial/URL belum diisi, provider dilewati
+	Configured() bool
+	Search(query string, opts SearchOptions) ([]SearchResult, error)
+}
+
+// SearchStatusError status HTTP non-200 dari provider
+type SearchStatusError struct {
+	Provider string
+	Code     int
+}
+
+func (e *SearchStatusError) Error() string {
+	switch e.Code {
+	case http.StatusTooManyRequests:
+		return fmt.Sprintf("%s returned 429 - rate limit exceeded", e.Provider)
+	case http.StatusUnprocessableEntity:
+		return fmt.Sprintf("%s returned 422 - invalid request", e.Provider)
+	}
+	return fmt.Sprintf("%s returned status %d", e.Provider, e.Code)
+}
+
+// SearchProviderNames provider yang dikenal, juga urutan default
+var SearchProviderNames = []string{"brave", "searxng", "duckduckgo"}
+
+var searchClient = &http.Client{Timeout: 15 * time.Second}
+
+// GetSearchProvider buat provider berdasarkan nama
+func GetSearchProvider(name string) (SearchProvider, error) {
+	switch name {
+	case "brave":
+		return &BraveProvider{ApiKey: os.Getenv("BRAVE_API_KEY")}, nil
+	case "searxng":
+		return &SearxngProvider{BaseUrl: strings.TrimRight(os.Getenv("SEARXNG_URL"), "/")}, nil
+	case "duckduckgo", "ddg":
+		return &DuckDuckGoProvider{}, nil
+	}
+	return nil, fmt.Errorf("unknown search provider: %s", name)
+}
+
+// ResolveSearchProviders urutan provider dari setting search_providers / env SEARCH_PROVIDERS,
+// dipisah koma. Provider yang belum dikonfigurasi dilewati.
+func ResolveSearchProviders() ([]SearchProvider, error) {
+	names := SearchProviderNames
+	if value := GetSetting("search_providers", os.Getenv("SEARCH_PROVIDERS")); value != "" {
+		names = strings.Split(value, ",")
+	}
+
+	var providers []SearchProvider
+	for _, name := range names {
+		name = strings.TrimSpace(strings.ToLower(name))
+		if name == "" {
+			continue
+		}
+		provider, err := GetSearchProvider(name)
+		if err != nil {
+			return nil, err
+		}
+		if provider.Configured() {
+			providers = append(providers, provider)
+		}
+	}
+
+	if len(providers) == 0 {
+		return nil, errors.New("no search provider configured (set BRAVE_API_KEY or SEARXNG_URL, or enable duckduckgo)")
+	}
+	return providers, nil
+}
+
+// ResolveSearchOptions ambil opsi dari setting search_*, lalu env SEARCH_*, lalu default
+func ResolveSearchOptions() SearchOptions {
+	opts := SearchOptions{
+		Lang:      GetSetting("search_lang", getEnvDefault("SEARCH_LANG", "id")),
+		Country:   strings.ToUpper(GetSetting("search_country", getEnvDefault("SEARCH_COUNTRY", "ID"))),
+		Freshness: GetSetting("search_freshness", os.Getenv("SEARCH_FRESHNESS")),
+		Count:     2,
+	}
+	if count, err := strconv.Atoi(GetSetting("search_count", os.Getenv("SEARCH_COUNT"))); err == nil && count > 0 {
+		opts.Count = min(count, 20)
+	}
+	return opts
+}
+
+// ValidateSearchSetting cek nilai setting search_* sebelum disimpan
+func ValidateSearchSetting(key string, value string) error {
+	switch key {
+	case "search_providers":
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(strings.ToLower(name)); name == "" {
+				continue
+			}
+			if _, err := GetSearchProvider(name); err != nil {
+				return err
+			}
+		}
+	case "search_freshness":
+		switch value {
+		case "", "day", "week", "month", "year":
+		default:
+			return errors.New("freshness must be one of day, week, month, year")
+		}
+	case "search_count":
+		if count, err := strconv.Atoi(value); value != "" && (err != nil || count < 1 || count > 20) {
+			return errors.New("count must be between 1 and 20")
+		}
+	}
+	return nil
+}
+
+// SearchWeb cari dengan provider pertama, pindah ke provider berikutnya kalau kena 429/422
+// atau hasilnya kosong
+func SearchWeb(query string) ([]SearchResult, error) {
+
+	providers, err := ResolveSearchProviders()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := ResolveSearchOptions()
+	cleanedQuery := cleanQuery(query)
+
+	var lastErr error
+	for _, provider := range providers {
+		searchLimiterFor(provider.Name()).Wait()
+
+		results, err := provider.Search(cleanedQuery, opts)
+		if err != nil {
+			var statusErr *SearchStatusError
+			if errors.As(err, &statusErr) &&
+				(statusErr.Code == http.StatusTooManyRequests || statusErr.Code == http.StatusUnprocessableEntity) {
+				log.Printf("[SEARCH] %s: %v, trying next provider", provider.Name(), err)
+				lastErr = err
+				continue
+			}
+			return nil, err
+		}
+
+		if len(results) == 0 {
+			log.Printf("[SEARCH] %s: no results for %q, trying next provider", provider.Name(), cleanedQuery)
+			continue
+		}
+
+		if len(results) > opts.Count {
+			results = results[:opts.Count]
+		}
+		for i := range results {
+			results[i].Provider = provider.Name()
+		}
+
+		log.Printf("[SEARCH] %s: found %d results for %q", provider.Name(), len(results), cleanedQuery)
+		return results, nil
+	}
+
+	return nil, lastErr
+}
+
+// cleanQuery hanya bersihkan karakter bermasalah, TIDAK memotong kata
+func cleanQuery(title string) string {
+	replacer := strings.NewReplacer(
+		"\"", "", "'", "", "\\", "", "#", "",
+		":", "", "?", "", "!", "",
+	)
+	clean := strings.TrimSpace(replacer.Replace(title))
+
+	// Batasi maksimal 100 karakter untuk hindari 422
+	if len(clean) > 100 {
+		// Potong di kata terakhir yang masuk dalam 100 karakter
+		words := strings.Fields(clean)
+		result := ""
+		for _, w := range words {
+			if len(result)+len(w)+1 > 100 {
+				break
+			}
+			if result != "" {
+				result += " "
+			}
+			result += w
+		}
+		return result
+	}
+	return clean
+}
+
+// searchLimiter jaga jarak minimum antar request ke satu provider.
+// Pemanggil hanya menunggu kalau memang terlalu cepat, dan antrian tetap adil antar worker.
+type searchLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func (l *searchLimiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
+
+var (
+	searchLimitersMu sync.Mutex
+	searchLimiters   = map[string]*searchLimiter{}
+)
+
+// searchRateDefaults request per detik tiap provider; bisa diubah lewat env SEARCH_RATE_<NAME>
+var searchRateDefaults = map[string]float64{
+	"brave":      1,
+	"searxng":    2,
+	"duckduckgo": 0.5,
+}
+
+func searchLimiterFor(name string) *searchLimiter {
+	searchLimitersMu.Lock()
+	defer searchLimitersMu.Unlock()
+
+	if l, ok := searchLimiters[name]; ok {
+		return l
+	}
+
+	rate := searchRateDefaults[name]
+	if value, err := strconv.ParseFloat(os.Getenv("SEARCH_RATE_"+strings.ToUpper(name)), 64); err == nil && value > 0 {
+		rate = value
+	}
+	if rate <= 0 {
+		rate = 1
+	}
+
+	l := &searchLimiter{interval: time.Duration(float64(time.Second) / rate)}
+	searchLimiters[name] = l
+	return l
+}
@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type searxngResponse struct {
+	Results []struct {
+		Title   string `json:"title"`
+		Url     string `json:"url"`
+		Content string `json:"content"`
+	} `json:"results"`
+}
+
+// SearxngProvider instance SearxNG sendiri (SEARXNG_URL), format json harus diaktifkan di settings.yml
+type SearxngProvider struct {
+	BaseUrl string
+}
+
+func (p *SearxngProvider) Name() string     { return "searxng" }
+func (p *SearxngProvider) Configured() bool { return p.BaseUrl != "" }
+
+func (p *SearxngProvider) Search(query string, opts SearchOptions) ([]SearchResult, error) {
+
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "json")
+	if opts.Lang != "" {
+		lang := strings.ToLower(opts.Lang)
+		if opts.Country != "" {
+			lang += "-" + opts.Country
+		}
+		params.Set("language", lang)
+	}
+	if opts.Freshness != "" {
+		params.Set("time_range", opts.Freshness)
+	}
+
+	req, err := http.NewRequest("GET", p.BaseUrl+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := searchClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search searxng: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &SearchStatusError{Provider: p.Name(), Code: resp.StatusCode}
+	}
+
+	var searxResp searxngResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searxResp); err != nil {
+		return nil, fmt.Errorf("failed to decode searxng response: %v", err)
+	}
+
+	var results []SearchResult
+	for _, r := range searxResp.Results {
+		if len(results) >= opts.Count {
+			break
+		}
+		results = append(results, SearchResult{
+			Title:       r.Title,
+			Url:         r.Url,
+			Description: r.Content,
+		})
+	}
+
+	return results, nil
+}
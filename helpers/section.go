@@ -0,0 +1,230 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Instruksi bawaan untuk rewrite bagian artikel; selain ini instruksi dianggap teks bebas
+var SectionInstructions = map[string]string{
+	"expand":           "Perluas bagian ini dengan penjelasan, contoh, dan detail tambahan yang relevan.",
+	"simplify":         "Sederhanakan bagian ini supaya lebih mudah dipahami pemula, dengan kalimat yang lebih pendek.",
+	"add_code_example": "Tambahkan contoh kode yang relevan di dalam <pre><code>...</code></pre> beserta penjelasan singkatnya.",
+}
+
+var ErrSectionNotFound = errors.New("section not found")
+
+// HtmlBlock satu elemen level teratas di konten HTML beserta posisi byte-nya di string asli
+type HtmlBlock struct {
+	Tag   string
+	Level int // 1-6 untuk heading, 0 untuk elemen lain
+	Id    string
+	Text  string
+	Start int
+	End   int
+}
+
+// DiffLine satu baris diff per blok HTML
+type DiffLine struct {
+	Op   string `json:"op"` // equal, delete, insert
+	Text string `json:"text"`
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// ParseHtmlBlocks pecah konten jadi elemen level teratas tanpa mengubah HTML-nya,
+// supaya satu bagian bisa diganti tanpa menormalisasi sisa artikel
+func ParseHtmlBlocks(content string) []HtmlBlock {
+
+	var blocks []HtmlBlock
+	var current *HtmlBlock
+	var text strings.Builder
+	depth, offset := 0, 0
+
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		raw := len(z.Raw())
+		start := offset
+		offset += raw
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			tag := string(name)
+			if depth == 0 {
+				block := HtmlBlock{Tag: tag, Start: start}
+				if len(tag) == 2 && tag[0] == 'h' && tag[1] >= '1' && tag[1] <= '6' {
+					block.Level = int(tag[1] - '0')
+				}
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = z.TagAttr()
+					if string(key) == "id" {
+						block.Id = string(val)
+					}
+				}
+				current = &block
+				text.Reset()
+			}
+			if tt == html.StartTagToken && !voidElements[tag] {
+				depth++
+			}
+			if depth == 0 && current != nil {
+				current.End = offset
+				blocks = append(blocks, *current)
+				current = nil
+			}
+
+		case html.EndTagToken:
+			if depth > 0 {
+				depth--
+			}
+			if depth == 0 && current != nil {
+				current.End = offset
+				current.Text = strings.Join(strings.Fields(html.UnescapeString(text.String())), " ")
+				blocks = append(blocks, *current)
+				current = nil
+			}
+
+		case html.TextToken:
+			if depth == 0 {
+				// Teks lepas di luar elemen dianggap blok sendiri
+				if t := strings.TrimSpace(string(z.Text())); t != "" {
+					blocks = append(blocks, HtmlBlock{Tag: "#text", Text: t, Start: start, End: offset})
+				}
+			} else {
+				text.Write(z.Text())
+				text.WriteByte(' ')
+			}
+		}
+	}
+
+	// Elemen yang tidak ditutup sampai akhir konten
+	if current != nil {
+		current.End = len(content)
+		current.Text = strings.Join(strings.Fields(text.String()), " ")
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
+
+// FindSection posisi byte bagian artikel: heading dengan id / slug teks = anchor beserta isi sampai
+// heading berikutnya yang levelnya sama atau lebih tinggi, atau paragraf ke-n (mulai dari 0)
+func FindSection(content string, anchor string, paragraphIndex *int) (int, int, error) {
+
+	blocks := ParseHtmlBlocks(content)
+
+	if anchor != "" {
+		anchor = strings.TrimPrefix(anchor, "#")
+		for i, block := range blocks {
+			if block.Level == 0 || (block.Id != anchor && GenerateSlug(block.Text) != anchor) {
+				continue
+			}
+			end := len(content)
+			for _, next := range blocks[i+1:] {
+				if next.Level > 0 && next.Level <= block.Level {
+					end = next.Start
+					break
+				}
+			}
+			return block.Start, end, nil
+		}
+		return 0, 0, fmt.Errorf("%w: no heading with anchor %q", ErrSectionNotFound, anchor)
+	}
+
+	if paragraphIndex != nil {
+		n := 0
+		for _, block := range blocks {
+			if block.Tag != "p" {
+				continue
+			}
+			if n == *paragraphIndex {
+				return block.Start, block.End, nil
+			}
+			n++
+		}
+		return 0, 0, fmt.Errorf("%w: article has %d paragraphs", ErrSectionNotFound, n)
+	}
+
+	return 0, 0, fmt.Errorf("%w: anchor or paragraph_index is required", ErrSectionNotFound)
+}
+
+// SectionAnchors daftar anchor heading yang bisa dipilih, untuk pesan error dan UI
+func SectionAnchors(content string) []string {
+	var anchors []string
+	for _, block := range ParseHtmlBlocks(content) {
+		if block.Level == 0 {
+			continue
+		}
+		if block.Id != "" {
+			anchors = append(anchors, block.Id)
+		} else if slug := GenerateSlug(block.Text); slug != "" {
+			anchors = append(anchors, slug)
+		}
+	}
+	return anchors
+}
+
+// DiffHtmlBlocks diff per elemen level teratas (LCS), spasi dirapikan supaya beda format tidak dianggap berubah
+func DiffHtmlBlocks(original string, proposed string) []DiffLine {
+
+	a := blockLines(original)
+	b := blockLines(proposed)
+
+	// lcs[i][j] = panjang LCS a[i:] dan b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := []DiffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: "equal", Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, DiffLine{Op: "delete", Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: "insert", Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: "delete", Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: "insert", Text: b[j]})
+	}
+	return diff
+}
+
+func blockLines(content string) []string {
+	var lines []string
+	for _, block := range ParseHtmlBlocks(content) {
+		lines = append(lines, strings.Join(strings.Fields(content[block.Start:block.End]), " "))
+	}
+	return lines
+}
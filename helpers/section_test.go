@@ -0,0 +1,130 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+)
+
+const sectionArticle = `<p>Pembuka artikel.</p>
+<h2 id="instalasi">Instalasi Go</h2>
+<p>Unduh installer.</p>
+<h3>Cek Versi</h3>
+<p>Jalankan <code>go version</code>.</p>
+<h2>Hello World</h2>
+<p>Tulis program pertama.</p>
+<img src="/a.png">
+teks lepas`
+
+func TestParseHtmlBlocks(t *testing.T) {
+	blocks := ParseHtmlBlocks(sectionArticle)
+
+	want := []struct {
+		tag   string
+		level int
+		id    string
+		text  string
+	}{
+		{"p", 0, "", "Pembuka artikel."},
+		{"h2", 2, "instalasi", "Instalasi Go"},
+		{"p", 0, "", "Unduh installer."},
+		{"h3", 3, "", "Cek Versi"},
+		{"p", 0, "", "Jalankan go version ."},
+		{"h2", 2, "", "Hello World"},
+		{"p", 0, "", "Tulis program pertama."},
+		{"img", 0, "", ""},
+		{"#text", 0, "", "teks lepas"},
+	}
+
+	if len(blocks) != len(want) {
+		t.Fatalf("got %d blocks, want %d: %+v", len(blocks), len(want), blocks)
+	}
+	for i, w := range want {
+		b := blocks[i]
+		if b.Tag != w.tag || b.Level != w.level || b.Id != w.id || b.Text != w.text {
+			t.Errorf("block %d = {%s %d %q %q}, want {%s %d %q %q}", i, b.Tag, b.Level, b.Id, b.Text, w.tag, w.level, w.id, w.text)
+		}
+	}
+
+	// Posisi byte menunjuk ke HTML asli tanpa dinormalisasi
+	if got := sectionArticle[blocks[1].Start:blocks[1].End]; got != `<h2 id="instalasi">Instalasi Go</h2>` {
+		t.Errorf("block 1 raw = %q", got)
+	}
+}
+
+func TestFindSection(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name      string
+		anchor    string
+		paragraph *int
+		want      string
+		err       bool
+	}{
+		{
+			name:   "heading by id includes nested subheading",
+			anchor: "instalasi",
+			want: `<h2 id="instalasi">Instalasi Go</h2>
+<p>Unduh installer.</p>
+<h3>Cek Versi</h3>
+<p>Jalankan <code>go version</code>.</p>
+`,
+		},
+		{
+			name:   "heading by slug with hash",
+			anchor: "#cek-versi",
+			want: `<h3>Cek Versi</h3>
+<p>Jalankan <code>go version</code>.</p>
+`,
+		},
+		{
+			name:   "last heading runs to end of content",
+			anchor: "hello-world",
+			want: `<h2>Hello World</h2>
+<p>Tulis program pertama.</p>
+<img src="/a.png">
+teks lepas`,
+		},
+		{name: "first paragraph", paragraph: intPtr(0), want: "<p>Pembuka artikel.</p>"},
+		{name: "third paragraph", paragraph: intPtr(2), want: "<p>Jalankan <code>go version</code>.</p>"},
+		{name: "unknown anchor", anchor: "tidak-ada", err: true},
+		{name: "paragraph out of range", paragraph: intPtr(10), err: true},
+		{name: "nothing selected", err: true},
+	}
+
+	for _, tt := range tests {
+		start, end, err := FindSection(sectionArticle, tt.anchor, tt.paragraph)
+		if tt.err {
+			if !errors.Is(err, ErrSectionNotFound) {
+				t.Errorf("%s: expected ErrSectionNotFound, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if got := sectionArticle[start:end]; got != tt.want {
+			t.Errorf("%s: section = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiffHtmlBlocks(t *testing.T) {
+	diff := DiffHtmlBlocks("<p>Satu</p><p>Dua</p>", "<p>Satu</p>\n<p>Dua   baru</p><p>Tiga</p>")
+
+	want := []DiffLine{
+		{Op: "equal", Text: "<p>Satu</p>"},
+		{Op: "delete", Text: "<p>Dua</p>"},
+		{Op: "insert", Text: "<p>Dua baru</p>"},
+		{Op: "insert", Text: "<p>Tiga</p>"},
+	}
+	if len(diff) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(diff), len(want), diff)
+	}
+	for i := range want {
+		if diff[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, diff[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package helpers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/models"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// Jenis konten yang di-embed untuk pencarian semantik
+var SemanticEntityTypes = []string{"blog", "project", "bookmark"}
+
+var ErrSemanticDisabled = errors.New("semantic search is disabled (embedding_provider is tfidf)")
+
+const (
+	// Panjang teks dokumen yang di-embed; awal artikel biasanya sudah mewakili topiknya
+	semanticDocumentRunes = 6000
+	semanticQueueSize     = 1000
+)
+
+// SemanticHit satu hasil pencarian semantik
+type SemanticHit struct {
+	EntityType string  `json:"entity_type"`
+	EntityId   uint    `json:"entity_id"`
+	Score      float64 `json:"score"`
+}
+
+type semanticKey struct {
+	EntityType string
+	EntityId   uint
+}
+
+// semanticIndex salinan vektor di memori supaya pencarian tidak membaca blob dari MySQL setiap request.
+// Hanya vektor dari model embedding yang sedang aktif yang dimuat.
+var semanticIndex = struct {
+	sync.RWMutex
+	model   string
+	vectors map[semanticKey][]float64
+}{vectors: map[semanticKey][]float64{}}
+
+var semanticQueue = make(chan semanticKey, semanticQueueSize)
+
+// StartSemanticIndex muat index dari database lalu jalankan worker embedding di background.
+// Index dimuat ulang berkala (SEMANTIC_INDEX_RELOAD_MINUTES, default 10) supaya perubahan
+// dari instance lain ikut terbaca.
+func StartSemanticIndex() {
+	if os.Getenv("SEMANTIC_INDEX_DISABLED") == "true" {
+		return
+	}
+
+	go func() {
+		LoadSemanticIndex()
+
+		minutes, err := strconv.Atoi(os.Getenv("SEMANTIC_INDEX_RELOAD_MINUTES"))
+		if err != nil || minutes < 1 {
+			minutes = 10
+		}
+		reload := time.NewTicker(time.Duration(minutes) * time.Minute)
+		defer reload.Stop()
+
+		for {
+			select {
+			case key := <-semanticQueue:
+				if err := indexSemanticEntity(key, false); err != nil && !errors.Is(err, errEmbeddingUnchanged) && !errors.Is(err, ErrSemanticDisabled) {
+					log.Printf("[SEMANTIC ERROR] %s %d: %v", key.EntityType, key.EntityId, err)
+				}
+			case <-reload.C:
+				LoadSemanticIndex()
+			}
+		}
+	}()
+}
+
+// LoadSemanticIndex ganti isi index memori dengan vektor di database untuk model aktif
+func LoadSemanticIndex() {
+
+	model := EmbeddingModel()
+	var rows []models.ContentEmbedding
+	if err := database.DB.Where("model = ?", model).Find(&rows).Error; err != nil {
+		log.Printf("[SEMANTIC ERROR] failed to load index: %v", err)
+		return
+	}
+
+	vectors := make(map[semanticKey][]float64, len(rows))
+	for _, row := range rows {
+		if v := decodeVector(row.Vector, row.Dimension); v != nil {
+			vectors[semanticKey{row.EntityType, row.EntityId}] = v
+		}
+	}
+
+	semanticIndex.Lock()
+	semanticIndex.model = model
+	semanticIndex.vectors = vectors
+	semanticIndex.Unlock()
+}
+
+// QueueEmbedding antrekan konten untuk di-embed ulang setelah dibuat atau diubah.
+// Tidak memblok request; kalau antrean penuh cukup dicatat, backfill akan melengkapinya.
+func QueueEmbedding(entityType string, entityId uint) {
+	if os.Getenv("SEMANTIC_INDEX_DISABLED") == "true" {
+		return
+	}
+	select {
+	case semanticQueue <- semanticKey{entityType, entityId}:
+	default:
+		log.Printf("[SEMANTIC] queue full, skipped %s %d", entityType, entityId)
+	}
+}
+
+// RemoveEmbedding hapus vektor konten yang dihapus
+func RemoveEmbedding(entityType string, entityIds ...uint) {
+	if len(entityIds) == 0 {
+		return
+	}
+	database.DB.Where("entity_type = ? AND entity_id IN ?", entityType, entityIds).Delete(&models.ContentEmbedding{})
+
+	semanticIndex.Lock()
+	for _, id := range entityIds {
+		delete(semanticIndex.vectors, semanticKey{entityType, id})
+	}
+	semanticIndex.Unlock()
+}
+
+// BackfillEmbeddings embed semua konten yang belum punya vektor, isinya berubah, atau dibuat dengan
+// model lain. force = embed ulang semuanya.
+func BackfillEmbeddings(force bool) (indexed int, skipped int, failed int) {
+
+	if EmbeddingProvider() == "tfidf" {
+		log.Printf("[SEMANTIC] %v", ErrSemanticDisabled)
+		return
+	}
+
+	tables := map[string]any{"blog": &models.Blog{}, "project": &models.Project{}, "bookmark": &models.Bookmark{}}
+	for _, entityType := range SemanticEntityTypes {
+		var ids []uint
+		database.DB.Model(tables[entityType]).Order("id asc").Pluck("id", &ids)
+
+		for _, id := range ids {
+			before := time.Now()
+			err := indexSemanticEntity(semanticKey{entityType, id}, force)
+			switch {
+			case errors.Is(err, errEmbeddingUnchanged):
+				skipped++
+			case err != nil:
+				failed++
+				log.Printf("[SEMANTIC ERROR] %s %d: %v", entityType, id, err)
+			default:
+				indexed++
+				log.Printf("[SEMANTIC] embedded %s %d (%s)", entityType, id, time.Since(before).Round(time.Millisecond))
+			}
+		}
+	}
+	return
+}
+
+var errEmbeddingUnchanged = errors.New("embedding is up to date")
+
+// indexSemanticEntity embed satu konten dan simpan vektornya. Konten yang sudah tidak ada dihapus dari index.
+func indexSemanticEntity(key semanticKey, force bool) error {
+
+	if EmbeddingProvider() == "tfidf" {
+		return ErrSemanticDisabled
+	}
+
+	text, ok := semanticDocument(key.EntityType, key.EntityId)
+	if !ok {
+		RemoveEmbedding(key.EntityType, key.EntityId)
+		return nil
+	}
+
+	model := EmbeddingModel()
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:])
+
+	var existing models.ContentEmbedding
+	found := database.DB.Where("entity_type = ? AND entity_id = ?", key.EntityType, key.EntityId).First(&existing).Error == nil
+	if !force && found && existing.Model == model && existing.ContentHash == hash {
+		return errEmbeddingUnchanged
+	}
+
+	vectors, err := requestEmbeddings(model, []string{semanticPrefix(model, "search_document: ") + text})
+	if err != nil {
+		return err
+	}
+	vector := vectors[0]
+
+	row := models.ContentEmbedding{
+		EntityType:  key.EntityType,
+		EntityId:    key.EntityId,
+		Model:       model,
+		Dimension:   len(vector),
+		Vector:      encodeVector(vector),
+		ContentHash: hash,
+	}
+	if err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"model", "dimension", "vector", "content_hash", "updated_at"}),
+	}).Create(&row).Error; err != nil {
+		return err
+	}
+
+	semanticIndex.Lock()
+	if semanticIndex.model == model {
+		semanticIndex.vectors[key] = vector
+	}
+	semanticIndex.Unlock()
+	return nil
+}
+
+// SemanticSearch embed query lalu urutkan konten di index berdasarkan cosine similarity.
+// types kosong = semua jenis; hasil di bawah minScore dibuang.
+func SemanticSearch(query string, types []string, minScore float64) ([]SemanticHit, error) {
+
+	if EmbeddingProvider() == "tfidf" {
+		return nil, ErrSemanticDisabled
+	}
+
+	model := EmbeddingModel()
+	vectors, err := requestEmbeddings(model, []string{semanticPrefix(model, "search_query: ") + query})
+	if err != nil {
+		return nil, err
+	}
+	queryVector := vectors[0]
+
+	semanticIndex.RLock()
+	if semanticIndex.model != model {
+		// Model embedding baru saja diganti lewat setting; index lama tidak bisa dibandingkan
+		semanticIndex.RUnlock()
+		LoadSemanticIndex()
+		semanticIndex.RLock()
+	}
+	var hits []SemanticHit
+	for key, vector := range semanticIndex.vectors {
+		if len(types) > 0 && !slices.Contains(types, key.EntityType) {
+			continue
+		}
+		if score := CosineSimilarity(queryVector, vector); score >= minScore {
+			hits = append(hits, SemanticHit{EntityType: key.EntityType, EntityId: key.EntityId, Score: math.Round(score*10000) / 10000})
+		}
+	}
+	semanticIndex.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	return hits, nil
+}
+
+// SemanticMinScore skor minimal hasil pencarian dari env SEMANTIC_MIN_SCORE, default 0.45
+func SemanticMinScore() float64 {
+	if f, err := strconv.ParseFloat(os.Getenv("SEMANTIC_MIN_SCORE"), 64); err == nil && f >= 0 && f <= 1 {
+		return f
+	}
+	return 0.45
+}
+
+// semanticDocument teks yang di-embed untuk satu konten, false kalau kontennya sudah tidak ada
+func semanticDocument(entityType string, entityId uint) (string, bool) {
+
+	var parts []string
+	switch entityType {
+	case "blog":
+		var blog models.Blog
+		if database.DB.Preload("Tags").First(&blog, entityId).Error != nil {
+			return "", false
+		}
+		parts = append(parts, blog.Title, blog.Description)
+		for _, tag := range blog.Tags {
+			parts = append(parts, tag.Name)
+		}
+		parts = append(parts, HtmlToText(blog.Content))
+
+	case "project":
+		var project models.Project
+		if database.DB.Preload("TechStacks").First(&project, entityId).Error != nil {
+			return "", false
+		}
+		parts = append(parts, project.Title, project.Platform, project.Description)
+		for _, stack := range project.TechStacks {
+			parts = append(parts, stack.Name)
+		}
+
+	case "bookmark":
+		var bookmark models.Bookmark
+		if database.DB.Preload("Topics").First(&bookmark, entityId).Error != nil {
+			return "", false
+		}
+		parts = append(parts, bookmark.Title, bookmark.Description, bookmark.Url)
+		for _, topic := range bookmark.Topics {
+			parts = append(parts, topic.Name)
+		}
+
+	default:
+		return "", false
+	}
+
+	var nonEmpty []string
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			nonEmpty = append(nonEmpty, part)
+		}
+	}
+	text, _ := TruncateText(strings.Join(nonEmpty, "\n"), semanticDocumentRunes)
+	return text, true
+}
+
+// semanticPrefix awalan tugas yang diwajibkan model nomic-embed-text, model lain tidak memakainya
+func semanticPrefix(model string, prefix string) string {
+	if strings.Contains(model, "nomic-embed") {
+		return prefix
+	}
+	return ""
+}
+
+// encodeVector simpan vektor sebagai float32 little-endian (separuh ukuran float64, presisi cukup)
+func encodeVector(v []float64) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(f)))
+	}
+	return buf
+}
+
+// decodeVector kebalikan encodeVector, nil kalau panjang blob tidak sesuai dimensi
+func decodeVector(b []byte, dimension int) []float64 {
+	if dimension <= 0 || len(b) != 4*dimension {
+		return nil
+	}
+	v := make([]float64, dimension)
+	for i := range v {
+		v[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:])))
+	}
+	return v
+}
@@ -0,0 +1,203 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"arlchoose/backend-api/models"
+)
+
+// Batas panjang metadata SEO (karakter), mengikuti yang ditampilkan mesin pencari
+const (
+	MetaTitleMax          = 60
+	MetaDescriptionMin    = 70
+	MetaDescriptionMax    = 160
+	FocusKeywordMaxWords  = 4
+	FaqMaxItems           = 10
+	FaqQuestionMax        = 200
+	FaqAnswerMax          = 500
+	seoContentPromptRunes = 4000
+)
+
+// SeoProposal metadata SEO yang diusulkan LLM atau dikirim editor
+type SeoProposal struct {
+	MetaTitle       string           `json:"meta_title"`
+	MetaDescription string           `json:"meta_description"`
+	FocusKeyword    string           `json:"focus_keyword"`
+	Faq             []models.BlogFaq `json:"faq"`
+}
+
+// SeoValidationError usulan LLM tetap tidak lolos validasi panjang setelah dicoba ulang
+type SeoValidationError struct {
+	Errors map[string]string
+}
+
+func (e *SeoValidationError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for field, msg := range e.Errors {
+		parts = append(parts, field+": "+msg)
+	}
+	return "invalid seo proposal: " + strings.Join(parts, "; ")
+}
+
+// ValidateSeo cek panjang setiap field, kosong berarti valid.
+// required untuk usulan LLM: semua field wajib terisi; editor boleh mengosongkan field.
+func ValidateSeo(p SeoProposal, required bool) map[string]string {
+
+	errs := map[string]string{}
+	if required {
+		if p.MetaTitle == "" {
+			errs["meta_title"] = "is required"
+		}
+		if p.MetaDescription == "" {
+			errs["meta_description"] = "is required"
+		}
+		if p.FocusKeyword == "" {
+			errs["focus_keyword"] = "is required"
+		}
+		if len(p.Faq) == 0 {
+			errs["faq"] = "is required"
+		}
+	}
+
+	if n := utf8.RuneCountInString(p.MetaTitle); n > MetaTitleMax {
+		errs["meta_title"] = fmt.Sprintf("must be at most %d characters, got %d", MetaTitleMax, n)
+	}
+	if n := utf8.RuneCountInString(p.MetaDescription); n > 0 && (n < MetaDescriptionMin || n > MetaDescriptionMax) {
+		errs["meta_description"] = fmt.Sprintf("must be %d-%d characters, got %d", MetaDescriptionMin, MetaDescriptionMax, n)
+	}
+	if n := len(strings.Fields(p.FocusKeyword)); n > FocusKeywordMaxWords {
+		errs["focus_keyword"] = fmt.Sprintf("must be at most %d words, got %d", FocusKeywordMaxWords, n)
+	}
+
+	if len(p.Faq) > FaqMaxItems {
+		errs["faq"] = fmt.Sprintf("must have at most %d items", FaqMaxItems)
+	}
+	for i, item := range p.Faq {
+		q, a := utf8.RuneCountInString(item.Question), utf8.RuneCountInString(item.Answer)
+		switch {
+		case q == 0 || a == 0:
+			errs["faq"] = fmt.Sprintf("item %d needs both question and answer", i+1)
+		case q > FaqQuestionMax:
+			errs["faq"] = fmt.Sprintf("question %d must be at most %d characters", i+1, FaqQuestionMax)
+		case a > FaqAnswerMax:
+			errs["faq"] = fmt.Sprintf("answer %d must be at most %d characters", i+1, FaqAnswerMax)
+		}
+	}
+
+	return errs
+}
+
+// ProposeSeo minta LLM mengusulkan metadata SEO dari isi blog. Kalau panjangnya tidak valid,
+// dicoba sekali lagi dengan daftar kesalahannya.
+func ProposeSeo(blog *models.Blog, ref LLMUsageRef) (SeoProposal, error) {
+
+	text, _ := TruncateText(HtmlToText(blog.Content), seoContentPromptRunes)
+
+	data := NewPromptData()
+	data.Title = blog.Title
+	data.Description = blog.Description
+	data.Content = text
+
+	prompt, _, err := RenderPrompt(LLMTaskSeo, data)
+	if err != nil {
+		return SeoProposal{}, err
+	}
+
+	var errs map[string]string
+	for attempt := 0; attempt < 2; attempt++ {
+		request := prompt
+		if len(errs) > 0 {
+			request += "\n\nPERHATIAN: jawaban sebelumnya tidak valid:\n"
+			for field, msg := range errs {
+				request += "- " + field + ": " + msg + "\n"
+			}
+			request += "Perbaiki dan balas ulang JSON lengkap."
+		}
+
+		response, err := AskLLM(LLMTaskSeo, request, ref)
+		if err != nil {
+			return SeoProposal{}, err
+		}
+
+		proposal, err := parseSeoProposal(response)
+		if err != nil {
+			errs = map[string]string{"json": err.Error()}
+			continue
+		}
+		if errs = ValidateSeo(proposal, true); len(errs) == 0 {
+			return proposal, nil
+		}
+	}
+	return SeoProposal{}, &SeoValidationError{Errors: errs}
+}
+
+// parseSeoProposal ambil objek JSON dari jawaban LLM (model kadang menambah teks atau code fence)
+func parseSeoProposal(response string) (SeoProposal, error) {
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start < 0 || end < start {
+		return SeoProposal{}, errors.New("response contains no JSON object")
+	}
+
+	var p SeoProposal
+	if err := json.Unmarshal([]byte(response[start:end+1]), &p); err != nil {
+		return SeoProposal{}, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	p.MetaTitle = strings.TrimSpace(p.MetaTitle)
+	p.MetaDescription = strings.TrimSpace(p.MetaDescription)
+	p.FocusKeyword = strings.ToLower(strings.TrimSpace(p.FocusKeyword))
+	faq := p.Faq[:0]
+	for _, item := range p.Faq {
+		item.Question = strings.TrimSpace(item.Question)
+		item.Answer = strings.TrimSpace(item.Answer)
+		if item.Question != "" || item.Answer != "" {
+			faq = append(faq, item)
+		}
+	}
+	p.Faq = faq
+	return p, nil
+}
+
+// ApplySeo salin usulan ke blog
+func ApplySeo(blog *models.Blog, p SeoProposal) {
+	blog.MetaTitle = p.MetaTitle
+	blog.MetaDescription = p.MetaDescription
+	blog.FocusKeyword = p.FocusKeyword
+	blog.Faq = p.Faq
+}
+
+// FaqSchema FAQPage JSON-LD (schema.org) dari daftar FAQ, nil kalau kosong
+func FaqSchema(faq []models.BlogFaq) map[string]any {
+	if len(faq) == 0 {
+		return nil
+	}
+
+	entities := make([]map[string]any, 0, len(faq))
+	for _, item := range faq {
+		entities = append(entities, map[string]any{
+			"@type": "Question",
+			"name":  item.Question,
+			"acceptedAnswer": map[string]any{
+				"@type": "Answer",
+				"text":  item.Answer,
+			},
+		})
+	}
+	return map[string]any{
+		"@context":   "https://schema.org",
+		"@type":      "FAQPage",
+		"mainEntity": entities,
+	}
+}
+
+// HtmlToText buang tag HTML, rapikan spasi dan decode entity
+func HtmlToText(s string) string {
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
@@ -0,0 +1,69 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"arlchoose/backend-api/models"
+)
+
+func validSeoProposal() SeoProposal {
+	return SeoProposal{
+		MetaTitle:       "Panduan Belajar Golang untuk Pemula",
+		MetaDescription: "Pelajari dasar Golang mulai dari instalasi, sintaks dasar, hingga membuat program pertama dengan langkah yang mudah diikuti.",
+		FocusKeyword:    "belajar golang",
+		Faq: []models.BlogFaq{
+			{Question: "Apa itu Golang?", Answer: "Bahasa pemrograman open source buatan Google."},
+		},
+	}
+}
+
+func seoErrorFields(errs map[string]string) []string {
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestValidateSeo(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(p *SeoProposal)
+		required bool
+		want     []string
+	}{
+		{"valid", func(p *SeoProposal) {}, true, nil},
+		{"empty allowed for editor", func(p *SeoProposal) { *p = SeoProposal{} }, false, nil},
+		{"empty rejected for llm", func(p *SeoProposal) { *p = SeoProposal{} }, true, []string{"faq", "focus_keyword", "meta_description", "meta_title"}},
+		{"title too long", func(p *SeoProposal) { p.MetaTitle = strings.Repeat("a", MetaTitleMax+1) }, false, []string{"meta_title"}},
+		{"title counts runes", func(p *SeoProposal) { p.MetaTitle = strings.Repeat("é", MetaTitleMax) }, false, nil},
+		{"description too short", func(p *SeoProposal) { p.MetaDescription = "Terlalu pendek." }, false, []string{"meta_description"}},
+		{"description too long", func(p *SeoProposal) { p.MetaDescription = strings.Repeat("a", MetaDescriptionMax+1) }, false, []string{"meta_description"}},
+		{"keyword too many words", func(p *SeoProposal) { p.FocusKeyword = "satu dua tiga empat lima" }, false, []string{"focus_keyword"}},
+		{"faq missing answer", func(p *SeoProposal) { p.Faq = []models.BlogFaq{{Question: "Apa?"}} }, false, []string{"faq"}},
+		{"faq question too long", func(p *SeoProposal) {
+			p.Faq = []models.BlogFaq{{Question: strings.Repeat("a", FaqQuestionMax+1), Answer: "Ya."}}
+		}, false, []string{"faq"}},
+		{"faq answer too long", func(p *SeoProposal) {
+			p.Faq = []models.BlogFaq{{Question: "Apa?", Answer: strings.Repeat("a", FaqAnswerMax+1)}}
+		}, false, []string{"faq"}},
+		{"too many faq items", func(p *SeoProposal) {
+			p.Faq = make([]models.BlogFaq, FaqMaxItems+1)
+			for i := range p.Faq {
+				p.Faq[i] = models.BlogFaq{Question: "Apa?", Answer: "Ya."}
+			}
+		}, false, []string{"faq"}},
+	}
+
+	for _, tt := range tests {
+		p := validSeoProposal()
+		tt.edit(&p)
+		got := seoErrorFields(ValidateSeo(p, tt.required))
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: error fields = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
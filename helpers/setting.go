@@ -0,0 +1,19 @@
+package helpers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/models"
+)
+
+// GetSetting ambil nilai setting berdasarkan key, fallback kalau belum ada atau kosong
+func GetSetting(key string, fallback string) string {
+	var setting models.Setting
+	if database.DB == nil {
+		return fallback
+	}
+	database.DB.Where("`key` = ?", key).Limit(1).Find(&setting)
+	if setting.Value == "" {
+		return fallback
+	}
+	return setting.Value
+}
@@ -0,0 +1,158 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Panjang shingle dalam kata. 5 kata cukup panjang supaya frasa umum
+// ("salah satu dari yang") jarang dianggap menyalin.
+const shingleSize = 5
+
+// Jumlah hash MinHash; error estimasi Jaccard kira-kira 1/sqrt(128) ≈ 9%
+const minHashSize = 128
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// similarityWords ubah HTML/teks jadi daftar kata huruf kecil tanpa tanda baca
+func similarityWords(text string) []string {
+	text = htmlTagPattern.ReplaceAllString(text, " ")
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Shingles set hash dari setiap n kata berurutan
+func Shingles(text string) map[uint64]struct{} {
+	words := similarityWords(text)
+	set := make(map[uint64]struct{})
+
+	if len(words) < shingleSize {
+		if len(words) > 0 {
+			set[hashShingle(words)] = struct{}{}
+		}
+		return set
+	}
+
+	for i := 0; i+shingleSize <= len(words); i++ {
+		set[hashShingle(words[i:i+shingleSize])] = struct{}{}
+	}
+	return set
+}
+
+func hashShingle(words []string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(words, " ")))
+	return h.Sum64()
+}
+
+// Containment porsi shingle a yang juga ada di b (0..1).
+// Dipakai untuk hasil AI vs referensi: referensi jauh lebih panjang, jadi Jaccard akan selalu kecil.
+func Containment(a, b map[uint64]struct{}) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	shared := 0
+	for h := range a {
+		if _, ok := b[h]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a))
+}
+
+// MinHash signature dari set shingle, untuk membandingkan dengan banyak blog tanpa memuat kontennya
+func MinHash(shingles map[uint64]struct{}) []uint64 {
+	sig := make([]uint64, minHashSize)
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	for h := range shingles {
+		for i := range sig {
+			// Permutasi ke-i: mix hash dengan seed berbeda (splitmix64)
+			v := h + uint64(i+1)*0x9e3779b97f4a7c15
+			v = (v ^ (v >> 30)) * 0xbf58476d1ce4e5b9
+			v = (v ^ (v >> 27)) * 0x94d049bb133111eb
+			v ^= v >> 31
+			if v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// MinHashSimilarity estimasi Jaccard dari dua signature
+func MinHashSimilarity(a, b []uint64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// EncodeMinHash simpan signature sebagai base64 (disimpan di kolom blog)
+func EncodeMinHash(sig []uint64) string {
+	buf := make([]byte, 8*len(sig))
+	for i, v := range sig {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
+
+// DecodeMinHash kebalikan EncodeMinHash, nil kalau format tidak valid
+func DecodeMinHash(s string) []uint64 {
+	buf, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(buf) != 8*minHashSize {
+		return nil
+	}
+	sig := make([]uint64, minHashSize)
+	for i := range sig {
+		sig[i] = binary.LittleEndian.Uint64(buf[i*8:])
+	}
+	return sig
+}
+
+// SimilarityThreshold batas skor (0..1) dari setting similarity_threshold / env SIMILARITY_THRESHOLD, default 0.3
+func SimilarityThreshold() float64 {
+	value := GetSetting("similarity_threshold", os.Getenv("SIMILARITY_THRESHOLD"))
+	if threshold, err := strconv.ParseFloat(value, 64); err == nil && threshold > 0 && threshold <= 1 {
+		return threshold
+	}
+	return 0.3
+}
+
+// SimilarityAction "flag" (tetap pending, ditandai) atau "reject" (langsung ditolak), default flag
+func SimilarityAction() string {
+	if GetSetting("similarity_action", os.Getenv("SIMILARITY_ACTION")) == "reject" {
+		return "reject"
+	}
+	return "flag"
+}
+
+// ValidateSimilaritySetting cek nilai setting similarity_* sebelum disimpan
+func ValidateSimilaritySetting(key string, value string) error {
+	switch key {
+	case "similarity_threshold":
+		if threshold, err := strconv.ParseFloat(value, 64); value != "" && (err != nil || threshold <= 0 || threshold > 1) {
+			return errors.New("threshold must be a number between 0 and 1")
+		}
+	case "similarity_action":
+		if value != "" && value != "flag" && value != "reject" {
+			return errors.New("action must be flag or reject")
+		}
+	}
+	return nil
+}
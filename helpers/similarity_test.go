@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShingles(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want int
+	}{
+		{"empty", "", 0},
+		{"shorter than shingle", "satu dua tiga", 1},
+		{"exactly one shingle", "satu dua tiga empat lima", 1},
+		{"sliding window", "satu dua tiga empat lima enam tujuh", 3},
+		{"html and punctuation ignored", "<p>Satu, dua.</p> <p>TIGA empat lima!</p>", 1},
+		{"duplicates collapse", "a b c d e a b c d e", 5},
+	}
+
+	for _, tt := range tests {
+		if got := len(Shingles(tt.text)); got != tt.want {
+			t.Errorf("%s: len(Shingles) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	// Markup dan huruf besar tidak mengubah hasil
+	a := Shingles("<h2>Belajar Go</h2><p>Bahasa pemrograman yang sederhana.</p>")
+	b := Shingles("belajar go bahasa pemrograman yang sederhana")
+	if Containment(a, b) != 1 || Containment(b, a) != 1 {
+		t.Error("shingles should ignore HTML tags and case")
+	}
+}
+
+func TestContainment(t *testing.T) {
+	draft := "golang adalah bahasa pemrograman yang dibuat oleh google untuk sistem besar"
+	reference := "sejarah singkat: golang adalah bahasa pemrograman yang dibuat oleh google untuk sistem besar dan modern"
+
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"empty a", "", reference, 0},
+		{"identical", draft, draft, 1},
+		{"fully contained in longer text", draft, reference, 1},
+		{"unrelated", draft, "resep nasi goreng kampung dengan telur mata sapi dan kerupuk udang", 0},
+		// 9 shingle; 3 di awal sama persis, sisanya berbeda
+		{"partial overlap", "satu dua tiga empat lima enam tujuh delapan sembilan sepuluh sebelas dua belas",
+			"satu dua tiga empat lima enam tujuh", 3.0 / 9.0},
+	}
+
+	for _, tt := range tests {
+		got := Containment(Shingles(tt.a), Shingles(tt.b))
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Containment = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Containment tidak simetris: referensi yang panjang hanya sebagian ada di draft
+	if got := Containment(Shingles(reference), Shingles(draft)); got >= 1 {
+		t.Errorf("reverse containment should be below 1, got %v", got)
+	}
+}
+
+func TestMinHashRoundTrip(t *testing.T) {
+	sig := MinHash(Shingles("golang adalah bahasa pemrograman yang dibuat oleh google"))
+	decoded := DecodeMinHash(EncodeMinHash(sig))
+	if MinHashSimilarity(sig, decoded) != 1 {
+		t.Error("decoded signature differs from original")
+	}
+	if DecodeMinHash("not base64!") != nil {
+		t.Error("invalid signature should decode to nil")
+	}
+}
@@ -1,6 +1,9 @@
 package helpers
 
 import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/models"
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -25,3 +28,89 @@ func GenerateSlug(title string) string {
 
 	return slug
 }
+
+// slugTables memetakan entity type ke model yang punya kolom slug unik
+var slugTables = map[string]any{
+	"blog":    &models.Blog{},
+	"project": &models.Project{},
+	"tag":     &models.Tag{},
+}
+
+// UniqueSlug generate slug dari title yang dijamin belum dipakai entity lain
+// contoh: "hello-world" sudah ada → "hello-world-2", "hello-world-3", dst
+// excludeId diisi id entity sendiri saat update supaya slug miliknya tidak dianggap bentrok
+func UniqueSlug(entityType string, title string, excludeId uint) string {
+
+	base := GenerateSlug(title)
+	if base == "" {
+		base = "untitled"
+	}
+
+	model, ok := slugTables[entityType]
+	if !ok {
+		return base
+	}
+
+	candidate := base
+	for i := 2; ; i++ {
+		if !isSlugTaken(entityType, model, candidate, excludeId) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// isSlugTaken cek slug di tabel entity dan di slug_redirects milik entity lain
+func isSlugTaken(entityType string, model any, slug string, excludeId uint) bool {
+
+	var count int64
+	database.DB.Model(model).Where("slug = ? AND id != ?", slug, excludeId).Count(&count)
+	if count > 0 {
+		return true
+	}
+
+	// Slug lama milik entity lain tetap dicadangkan supaya link lama tidak nyasar
+	database.DB.Model(&models.SlugRedirect{}).
+		Where("entity_type = ? AND old_slug = ? AND entity_id != ?", entityType, slug, excludeId).
+		Count(&count)
+	return count > 0
+}
+
+// RecordSlugRedirect simpan slug lama kalau slug entity berubah
+func RecordSlugRedirect(entityType string, entityId uint, oldSlug string, newSlug string) {
+
+	if oldSlug == "" || oldSlug == newSlug {
+		return
+	}
+
+	// Slug baru sudah aktif lagi → redirect untuk slug itu tidak diperlukan
+	database.DB.Where("entity_type = ? AND old_slug = ?", entityType, newSlug).Delete(&models.SlugRedirect{})
+
+	var redirect models.SlugRedirect
+	if err := database.DB.Where("entity_type = ? AND old_slug = ?", entityType, oldSlug).First(&redirect).Error; err != nil {
+		database.DB.Create(&models.SlugRedirect{
+			EntityType: entityType,
+			OldSlug:    oldSlug,
+			EntityId:   entityId,
+		})
+		return
+	}
+
+	redirect.EntityId = entityId
+	database.DB.Save(&redirect)
+}
+
+// FindSlugRedirect cari entity id dari slug lama, return false kalau tidak ada
+func FindSlugRedirect(entityType string, oldSlug string) (uint, bool) {
+
+	var redirect models.SlugRedirect
+	if err := database.DB.Where("entity_type = ? AND old_slug = ?", entityType, oldSlug).First(&redirect).Error; err != nil {
+		return 0, false
+	}
+	return redirect.EntityId, true
+}
+
+// DeleteSlugRedirects hapus semua slug lama milik entity yang dihapus
+func DeleteSlugRedirects(entityType string, entityId uint) {
+	database.DB.Where("entity_type = ? AND entity_id = ?", entityType, entityId).Delete(&models.SlugRedirect{})
+}
@@ -0,0 +1,158 @@
+package helpers
+
+import (
+	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/models"
+	"errors"
+	"os"
+	"strings"
+)
+
+const (
+	// Tag yang dikirim ke prompt, diurutkan dari yang paling sering dipakai
+	tagPromptVocabularyLimit = 200
+	// Kemiripan minimal (1 - jarak edit / panjang) supaya tag usulan dianggap typo dari tag yang ada
+	tagFuzzyThreshold = 0.8
+	// Tag pendek seperti "go" / "js" tidak dicocokkan secara fuzzy, terlalu mudah salah
+	tagFuzzyMinLength = 5
+)
+
+// TagVocabulary daftar tag yang sudah ada beserta alias-nya, untuk mencocokkan tag usulan LLM
+type TagVocabulary struct {
+	Tags  []models.Tag
+	byKey map[string]int
+}
+
+// TagMatch hasil pencocokan satu tag usulan
+type TagMatch struct {
+	Suggested string     `json:"suggested"`
+	Tag       models.Tag `json:"tag"`
+	MatchedBy string     `json:"matched_by"` // exact, alias, fuzzy
+}
+
+// TagKey bentuk pembanding tag: slug tanpa strip, jadi "Go-Lang", "go lang" dan "golang" sama
+func TagKey(name string) string {
+	return strings.ReplaceAll(GenerateSlug(name), "-", "")
+}
+
+// LoadTagVocabulary ambil semua tag (paling sering dipakai dulu) dan alias-nya
+func LoadTagVocabulary() *TagVocabulary {
+
+	var tags []models.Tag
+	database.DB.Model(&models.Tag{}).
+		Select("tags.*").
+		Joins("LEFT JOIN blog_tags ON blog_tags.tag_id = tags.id").
+		Group("tags.id").
+		Order("COUNT(blog_tags.blog_id) desc, tags.name asc").
+		Find(&tags)
+
+	var aliases []models.TagAlias
+	database.DB.Find(&aliases)
+
+	v := &TagVocabulary{Tags: tags, byKey: map[string]int{}}
+	index := map[uint]int{}
+	for i, tag := range tags {
+		index[tag.Id] = i
+		v.byKey[TagKey(tag.Name)] = i
+		v.byKey[TagKey(tag.Slug)] = i
+	}
+	for _, alias := range aliases {
+		if i, ok := index[alias.TagId]; ok {
+			if _, taken := v.byKey[TagKey(alias.Alias)]; !taken {
+				v.byKey[TagKey(alias.Alias)] = i
+			}
+		}
+	}
+	return v
+}
+
+// Names nama tag untuk prompt, dibatasi supaya prompt tidak membengkak
+func (v *TagVocabulary) Names() []string {
+	names := make([]string, 0, min(len(v.Tags), tagPromptVocabularyLimit))
+	for _, tag := range v.Tags {
+		if len(names) == tagPromptVocabularyLimit {
+			break
+		}
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// Lookup cari tag dengan nama, slug, atau alias yang sama persis (setelah dinormalisasi)
+func (v *TagVocabulary) Lookup(name string) (models.Tag, bool) {
+	if i, ok := v.byKey[TagKey(name)]; ok {
+		return v.Tags[i], true
+	}
+	return models.Tag{}, false
+}
+
+// Match cari tag yang cocok: nama/slug persis, alias, lalu typo (jarak edit kecil)
+func (v *TagVocabulary) Match(name string) (TagMatch, bool) {
+
+	key := TagKey(name)
+	if key == "" {
+		return TagMatch{}, false
+	}
+
+	if tag, ok := v.Lookup(name); ok {
+		matchedBy := "alias"
+		if TagKey(tag.Name) == key || TagKey(tag.Slug) == key {
+			matchedBy = "exact"
+		}
+		return TagMatch{Suggested: name, Tag: tag, MatchedBy: matchedBy}, true
+	}
+
+	if len(key) < tagFuzzyMinLength {
+		return TagMatch{}, false
+	}
+
+	best, bestScore := -1, 0.0
+	for candidate, i := range v.byKey {
+		if len(candidate) < tagFuzzyMinLength {
+			continue
+		}
+		score := 1 - float64(levenshtein(key, candidate))/float64(max(len(key), len(candidate)))
+		if score >= tagFuzzyThreshold && (score > bestScore || (score == bestScore && i < best)) {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		return TagMatch{}, false
+	}
+	return TagMatch{Suggested: name, Tag: v.Tags[best], MatchedBy: "fuzzy"}, true
+}
+
+// TagAllowNew apakah tag di luar kosakata boleh dibuat otomatis, dari setting tag_allow_new / env TAG_ALLOW_NEW.
+// Default false: tag baru hanya dibuat kalau kosakata masih kosong.
+func TagAllowNew() bool {
+	return GetSetting("tag_allow_new", os.Getenv("TAG_ALLOW_NEW")) == "true"
+}
+
+// ValidateTagSetting validasi setting tag_* sebelum disimpan
+func ValidateTagSetting(key string, value string) error {
+	if key == "tag_allow_new" && value != "" && value != "true" && value != "false" {
+		return errors.New("must be true or false")
+	}
+	return nil
+}
+
+// levenshtein jarak edit antar dua string (per byte; key tag sudah ASCII)
+func levenshtein(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
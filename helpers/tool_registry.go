@@ -451,13 +451,16 @@ func toolHttpGet(input map[string]any) (any, error) {
 	if url == "" {
 		return nil, fmt.Errorf("url is required")
 	}
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := Fetch(url, FetchOptions{
+		Accept:   "application/json, text/plain;q=0.9, */*;q=0.8",
+		MaxBytes: 1 << 20,
+		Timeout:  10 * time.Second,
+		NoCache:  true,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %v", err)
 	}
-	defer resp.Body.Close()
-	body, _ := io.ReadAll(resp.Body)
+	body := resp.Body
 	var parsed any
 	if err := json.Unmarshal(body, &parsed); err != nil {
 		return map[string]any{"status": resp.StatusCode, "body": string(body)}, nil
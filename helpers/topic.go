@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"arlchoose/backend-api/models"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+)
+
+// TopicDoc artikel yang sudah ada, pembanding untuk judul baru
+type TopicDoc struct {
+	BlogId      uint
+	Title       string
+	Description string
+}
+
+// DuplicateTitleThreshold batas kemiripan judul dari setting duplicate_title_threshold / env DUPLICATE_TITLE_THRESHOLD.
+// Skala embedding dan TF-IDF berbeda, jadi default-nya juga beda.
+func DuplicateTitleThreshold(method string) float64 {
+	value := GetSetting("duplicate_title_threshold", os.Getenv("DUPLICATE_TITLE_THRESHOLD"))
+	if threshold, err := strconv.ParseFloat(value, 64); err == nil && threshold > 0 && threshold <= 1 {
+		return threshold
+	}
+	if method == "embedding" {
+		return 0.85
+	}
+	return 0.5
+}
+
+// ValidateTopicSetting cek nilai setting deteksi topik duplikat sebelum disimpan
+func ValidateTopicSetting(key string, value string) error {
+	switch key {
+	case "duplicate_title_threshold":
+		if threshold, err := strconv.ParseFloat(value, 64); value != "" && (err != nil || threshold <= 0 || threshold > 1) {
+			return errors.New("threshold must be a number between 0 and 1")
+		}
+	case "embedding_provider":
+		if value != "" && value != "ollama" && value != "tfidf" {
+			return errors.New("embedding provider must be ollama or tfidf")
+		}
+	}
+	return nil
+}
+
+// FilterDuplicateTitles buang kandidat judul yang terlalu mirip dengan judul/deskripsi artikel yang ada
+// atau dengan kandidat sebelumnya. Pakai embedding Ollama, fallback ke TF-IDF kalau gagal.
+func FilterDuplicateTitles(candidates []string, existing []TopicDoc) ([]string, []models.SkippedTitle) {
+
+	// Teks pembanding: judul dan deskripsi masing-masing artikel
+	var refTexts []string
+	var refDocs []TopicDoc
+	for _, doc := range existing {
+		refTexts = append(refTexts, doc.Title)
+		refDocs = append(refDocs, doc)
+		if doc.Description != "" {
+			refTexts = append(refTexts, doc.Description)
+			refDocs = append(refDocs, doc)
+		}
+	}
+
+	texts := append(append([]string{}, refTexts...), candidates...)
+	score, method := titleScorer(texts)
+
+	threshold := DuplicateTitleThreshold(method)
+	nRef := len(refTexts)
+
+	var kept []string
+	var keptIdx []int
+	var skipped []models.SkippedTitle
+
+	for ci, title := range candidates {
+		idx := nRef + ci
+
+		best, bestTitle, bestBlog := 0.0, "", uint(0)
+		for ri := range refTexts {
+			if s := score(idx, ri); s > best {
+				best, bestTitle, bestBlog = s, refDocs[ri].Title, refDocs[ri].BlogId
+			}
+		}
+		for k, ki := range keptIdx {
+			if s := score(idx, ki); s > best {
+				best, bestTitle, bestBlog = s, kept[k], 0
+			}
+		}
+
+		if best >= threshold {
+			skipped = append(skipped, models.SkippedTitle{
+				Title:     title,
+				SimilarTo: bestTitle,
+				BlogId:    bestBlog,
+				Score:     best,
+				Method:    method,
+			})
+			continue
+		}
+		kept = append(kept, title)
+		keptIdx = append(keptIdx, idx)
+	}
+
+	return kept, skipped
+}
+
+// titleScorer siapkan fungsi skor kemiripan antar teks berdasarkan index
+func titleScorer(texts []string) (func(i, j int) float64, string) {
+
+	if EmbeddingProvider() == "ollama" {
+		vectors, err := EmbedTexts(texts)
+		if err == nil {
+			return func(i, j int) float64 { return CosineSimilarity(vectors[i], vectors[j]) }, "embedding"
+		}
+		log.Printf("[DUPLICATE TITLE] embeddings unavailable, using tf-idf: %v", err)
+	}
+
+	vectors := TfidfVectors(texts)
+	return func(i, j int) float64 { return SparseCosine(vectors[i], vectors[j]) }, "tfidf"
+}
@@ -10,6 +10,7 @@ import (
 	"image/jpeg"
 	"image/png"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -243,6 +244,64 @@ func UploadFile(c *gin.Context, fieldName string, folder string) (string, error)
 	return filePath, nil
 }
 
+// DownloadImage download gambar dari URL lalu simpan ke uploads/ lewat jalur kompresi yang sama dengan UploadFile
+func DownloadImage(imageUrl string, folder string) (string, error) {
+
+	// Batasi 15MB supaya tidak ada file raksasa yang nyangkut
+	resp, err := Fetch(imageUrl, FetchOptions{
+		Accept:   "image/*",
+		MaxBytes: 15 << 20,
+		Timeout:  20 * time.Second,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to download image: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("image url returned status %d", resp.StatusCode)
+	}
+	data := resp.Body
+
+	// Ekstensi selalu dari isi file, bukan dari URL: SVG bisa membawa script dan ICO tidak dikompres,
+	// jadi hanya gambar raster yang diterima dari sumber luar
+	var ext string
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		ext = ".jpg"
+	case "image/png":
+		ext = ".png"
+	case "image/gif":
+		ext = ".gif"
+	case "image/webp":
+		ext = ".webp"
+	case "image/bmp":
+		ext = ".bmp"
+	default:
+		return "", fmt.Errorf("url is not a supported image (jpg, png, gif, webp, bmp)")
+	}
+
+	uploadPath := fmt.Sprintf("uploads/%s", folder)
+	if err := os.MkdirAll(uploadPath, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	outExt := ext
+	if isCompressableImage(ext) {
+		compressed, compressedExt, err := compressImageBytes(data, ext, 700*1024)
+		if err == nil {
+			data = compressed
+			outExt = compressedExt
+		}
+	}
+
+	filePath := fmt.Sprintf("%s/%d%s", uploadPath, time.Now().UnixNano(), outExt)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", err
+	}
+
+	return filePath, nil
+}
+
 func DeleteFile(fileUrl string) error {
 	if fileUrl == "" {
 		return nil
@@ -271,6 +330,28 @@ func DeleteFile(fileUrl string) error {
 	return nil
 }
 
+// writeFileAtomic tulis ke file sementara unik di folder yang sama lalu rename, supaya pembaca tidak
+// dapat file setengah jadi dan penulis paralel tidak saling menimpa file sementara
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
 // validateFileExtension memvalidasi ekstensi file yang diizinkan
 func validateFileExtension(file *multipart.FileHeader) error {
 	allowedExtensions := map[string]bool{
@@ -0,0 +1,33 @@
+package helpers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDownloadImageRejectsNonRaster(t *testing.T) {
+	t.Setenv("FETCH_ALLOW_PRIVATE", "true")
+	t.Setenv("FETCH_CACHE_DIR", t.TempDir())
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"svg with script", "/logo.svg", `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`},
+		{"svg behind png extension", "/logo.png", `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`},
+		{"icon", "/favicon.ico", "\x00\x00\x01\x00\x01\x00\x10\x10"},
+		{"html page", "/image.jpg", "<html><body>not found</body></html>"},
+	}
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tt.body))
+		}))
+		if path, err := DownloadImage(srv.URL+tt.path, "test"); err == nil {
+			t.Errorf("%s: expected error, saved as %s", tt.name, path)
+		}
+		srv.Close()
+	}
+}
@@ -0,0 +1,270 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// WebmentionSource hasil verifikasi halaman sumber webmention
+type WebmentionSource struct {
+	Type        string // mention, reply, like, repost
+	Title       string
+	Excerpt     string
+	AuthorName  string
+	AuthorUrl   string
+	AuthorPhoto string
+	PublishedAt *time.Time
+}
+
+// ErrSourceGone halaman sumber sudah dihapus (404/410) → mention ikut dihapus
+var ErrSourceGone = errors.New("source is gone")
+
+// webmentionClient POST ke endpoint yang diumumkan halaman luar, jadi lewat transport yang menolak alamat internal
+var webmentionClient = &http.Client{Timeout: 15 * time.Second, Transport: fetchTransport}
+
+var linkHeaderPattern = regexp.MustCompile(`<([^>]*)>\s*;[^,]*rel="?([^",]*)"?`)
+
+// ExtractOutboundLinks ambil link http(s) keluar dari konten HTML, link ke situs sendiri dilewati
+func ExtractOutboundLinks(content string, ownHosts ...string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+
+	own := map[string]bool{}
+	for _, h := range ownHosts {
+		own[strings.ToLower(h)] = true
+	}
+
+	seen := map[string]bool{}
+	var links []string
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		u, err := url.Parse(strings.TrimSpace(href))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || own[strings.ToLower(u.Host)] {
+			return
+		}
+		u.Fragment = ""
+		link := u.String()
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	})
+	return links
+}
+
+// DiscoverWebmentionEndpoint cari endpoint webmention target: header Link dulu, lalu <link>/<a rel="webmention">
+func DiscoverWebmentionEndpoint(target string) (string, error) {
+	doc, header, err := FetchDocumentWith(target, FetchOptions{SkipRobots: true})
+	if header != nil {
+		for _, value := range header.Values("Link") {
+			for _, m := range linkHeaderPattern.FindAllStringSubmatch(value, -1) {
+				if hasRel(m[2], "webmention") {
+					return resolveUrl(target, m[1])
+				}
+			}
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var endpoint string
+	found := false
+	doc.Find("link[rel][href], a[rel][href]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		rel, _ := s.Attr("rel")
+		if !hasRel(rel, "webmention") {
+			return true
+		}
+		endpoint, _ = s.Attr("href")
+		found = true
+		return false
+	})
+	if !found {
+		return "", nil
+	}
+
+	// href kosong berarti endpoint adalah halaman itu sendiri
+	return resolveUrl(doc.Url.String(), endpoint)
+}
+
+// SendWebmention POST source & target ke endpoint, return status code
+func SendWebmention(endpoint string, source string, target string) (int, error) {
+	form := url.Values{"source": {source}, "target": {target}}
+	resp, err := webmentionClient.PostForm(endpoint, form)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// VerifyWebmentionSource ambil halaman sumber dan pastikan benar-benar menautkan ke target.
+// Metadata (judul, author, tipe) diambil dari microformats h-entry kalau ada.
+func VerifyWebmentionSource(source string, target string) (*WebmentionSource, error) {
+	// Tanpa cache: sumber yang menghapus tautannya harus langsung terdeteksi
+	doc, _, err := FetchDocumentWith(source, FetchOptions{SkipRobots: true, NoCache: true})
+	if err != nil {
+		var statusErr *FetchStatusError
+		if errors.As(err, &statusErr) && (statusErr.Code == http.StatusNotFound || statusErr.Code == http.StatusGone) {
+			return nil, ErrSourceGone
+		}
+		return nil, err
+	}
+
+	wanted := normalizeMentionUrl(target)
+	var link *goquery.Selection
+	doc.Find("a[href], img[src], video[src], audio[src]").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		ref, ok := s.Attr("href")
+		if !ok {
+			ref, _ = s.Attr("src")
+		}
+		abs, err := resolveUrl(doc.Url.String(), ref)
+		if err == nil && normalizeMentionUrl(abs) == wanted {
+			link = s
+			return false
+		}
+		return true
+	})
+	if link == nil {
+		return nil, errors.New("source does not link to target")
+	}
+
+	result := &WebmentionSource{Type: "mention"}
+	switch {
+	case link.HasClass("u-in-reply-to"):
+		result.Type = "reply"
+	case link.HasClass("u-like-of"):
+		result.Type = "like"
+	case link.HasClass("u-repost-of"):
+		result.Type = "repost"
+	}
+
+	entry := doc.Find(".h-entry").First()
+	if entry.Length() == 0 {
+		entry = doc.Selection
+	}
+
+	result.Title = firstText(entry.Find(".p-name").First(), doc.Find("title").First())
+	result.Excerpt = firstText(entry.Find(".p-summary").First(), entry.Find(".e-content").First())
+	if result.Excerpt == "" {
+		result.Excerpt, _ = doc.Find(`meta[name="description"]`).Attr("content")
+	}
+	result.Excerpt = truncateRunes(strings.Join(strings.Fields(result.Excerpt), " "), 500)
+	result.Title = truncateRunes(result.Title, 250)
+
+	author := entry.Find(".p-author").First()
+	if author.Length() > 0 {
+		result.AuthorName = firstText(author.Find(".p-name").First(), author)
+		if href, ok := author.Find(".u-url").Attr("href"); ok {
+			result.AuthorUrl = publicHttpUrl(doc.Url.String(), href)
+		} else if href, ok := author.Attr("href"); ok {
+			result.AuthorUrl = publicHttpUrl(doc.Url.String(), href)
+		}
+		if src, ok := author.Find(".u-photo").Attr("src"); ok {
+			result.AuthorPhoto = publicHttpUrl(doc.Url.String(), src)
+		}
+	} else {
+		result.AuthorName, _ = doc.Find(`meta[name="author"]`).Attr("content")
+	}
+	result.AuthorName = truncateRunes(strings.TrimSpace(result.AuthorName), 190)
+
+	if published, ok := entry.Find(".dt-published").Attr("datetime"); ok {
+		if t, err := time.Parse(time.RFC3339, published); err == nil {
+			result.PublishedAt = &t
+		}
+	}
+
+	return result, nil
+}
+
+// publicHttpUrl URL absolut dari halaman luar yang aman ditampilkan: hanya http(s) dan muat di kolom 500 karakter.
+// Selain itu (javascript:, data:, dll) dikosongkan.
+func publicHttpUrl(base string, ref string) string {
+	abs, err := resolveUrl(base, ref)
+	if err != nil || len(abs) > 500 {
+		return ""
+	}
+	u, err := url.Parse(abs)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return abs
+}
+
+// NormalizeMentionTarget target webmention tanpa fragment, supaya #komentar tidak jadi mention terpisah
+func NormalizeMentionTarget(target string) string {
+	u, err := url.Parse(strings.TrimSpace(target))
+	if err != nil {
+		return target
+	}
+	u.Fragment = ""
+	return u.String()
+}
+
+// normalizeMentionUrl samakan URL untuk dibandingkan: tanpa fragment, tanpa trailing slash, host lowercase
+func normalizeMentionUrl(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.Fragment = ""
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u.String()
+}
+
+// resolveUrl ubah URL relatif jadi absolut berdasarkan base
+func resolveUrl(base string, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	r, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// hasRel cek apakah atribut rel (dipisah spasi) berisi nilai tertentu
+func hasRel(rel string, value string) bool {
+	for _, r := range strings.Fields(strings.ToLower(rel)) {
+		if r == value {
+			return true
+		}
+	}
+	return false
+}
+
+// firstText teks pertama yang tidak kosong dari beberapa selection
+func firstText(selections ...*goquery.Selection) string {
+	for _, s := range selections {
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func truncateRunes(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"arlchoose/backend-api/config"
+	"arlchoose/backend-api/controllers"
 	"arlchoose/backend-api/database"
+	"arlchoose/backend-api/helpers"
 	"arlchoose/backend-api/routes"
 )
 
@@ -14,6 +16,24 @@ func main() {
 	// Inisialisasi database
 	database.InitDB()
 
+	// Jadwal pengiriman digest newsletter
+	controllers.StartNewsletterScheduler()
+
+	// Worker antrean generate blog AI
+	controllers.StartAiWorkers()
+
+	// Jadwal generate blog AI berulang
+	controllers.StartAiScheduler()
+
+	// Index embedding untuk pencarian semantik
+	helpers.StartSemanticIndex()
+
+	// Bersihkan cache fetch halaman eksternal di disk
+	helpers.StartFetchCacheSweeper()
+
+	// Bersihkan share image Open Graph yang lama tidak dipakai
+	helpers.StartOgImageSweeper()
+
 	// Setup router
 	r := routes.SetupRouter()
 
@@ -28,9 +28,41 @@ var contactLimiter = &rateLimiter{
 	window:   10 * time.Minute,
 }
 
+// newsletterLimiter — max 5 subscribe per 10 menit per IP
+var newsletterLimiter = &rateLimiter{
+	requests: make(map[string][]time.Time),
+	max:      5,
+	window:   10 * time.Minute,
+}
+
+// webmentionLimiter — max 20 webmention per 10 menit per IP
+var webmentionLimiter = &rateLimiter{
+	requests: make(map[string][]time.Time),
+	max:      20,
+	window:   10 * time.Minute,
+}
+
+// searchLimiter — max 30 pencarian semantik per menit per IP (setiap query di-embed lewat Ollama)
+var searchLimiter = &rateLimiter{
+	requests: make(map[string][]time.Time),
+	max:      30,
+	window:   time.Minute,
+}
+
+// askLimiter — max 10 pertanyaan Ask Aibys per 10 menit per IP, setiap pertanyaan memanggil LLM
+var askLimiter = &rateLimiter{
+	requests: make(map[string][]time.Time),
+	max:      10,
+	window:   10 * time.Minute,
+}
+
 func init() {
 	go toolLimiter.cleanup()
 	go contactLimiter.cleanup()
+	go newsletterLimiter.cleanup()
+	go webmentionLimiter.cleanup()
+	go searchLimiter.cleanup()
+	go askLimiter.cleanup()
 }
 
 func (rl *rateLimiter) allow(ip string) bool {
@@ -105,3 +137,59 @@ func ContactRateLimit() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func NewsletterRateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !newsletterLimiter.allow(c.ClientIP()) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": "Too many subscribe attempts. Please wait a few minutes before trying again.",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func WebmentionRateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !webmentionLimiter.allow(c.ClientIP()) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": "Too many webmentions. Please wait a few minutes before trying again.",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func SearchRateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !searchLimiter.allow(c.ClientIP()) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": "Too many requests (max 30/minute)",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func AskRateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !askLimiter.allow(c.ClientIP()) {
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": "Too many questions. Please wait a few minutes before asking again.",
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ApFollower akun fediverse (Mastodon, dsb.) yang mengikuti actor blog
+type ApFollower struct {
+	Id          uint      `json:"id" gorm:"primaryKey"`
+	ActorUrl    string    `json:"actor_url" gorm:"size:500;unique;not null"`
+	Username    string    `json:"username"`
+	Domain      string    `json:"domain" gorm:"index"`
+	Inbox       string    `json:"inbox" gorm:"size:500;not null"`
+	SharedInbox string    `json:"shared_inbox" gorm:"size:500"`
+	FollowId    string    `json:"follow_id" gorm:"size:500"` // id activity Follow, dipakai untuk Accept
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ApDelivery log pengiriman activity ke inbox follower
+type ApDelivery struct {
+	Id           uint      `json:"id" gorm:"primaryKey"`
+	BlogId       *uint     `json:"blog_id" gorm:"index"`
+	ActivityId   string    `json:"activity_id" gorm:"size:500"`
+	ActivityType string    `json:"activity_type" gorm:"size:32"`
+	Inbox        string    `json:"inbox" gorm:"size:500"`
+	Status       string    `json:"status" gorm:"type:enum('sent','failed');not null;index"`
+	StatusCode   int       `json:"status_code"`
+	Attempts     int       `json:"attempts"`
+	Error        string    `json:"error" gorm:"type:text"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// AiJob satu permintaan generate blog AI. Step "titles" dikerjakan di level job,
+// setelah judul didapat setiap judul jadi AiJobTask yang dikerjakan worker terpisah.
+type AiJob struct {
+	Id               uint           `json:"id" gorm:"primaryKey"`
+	Keyword          string         `json:"keyword"`
+	Total            int            `json:"total"`
+	TitlesTemplateId *uint          `json:"titles_template_id"`
+	ScheduleId       *uint          `json:"schedule_id" gorm:"index"`
+	SkippedTitles    []SkippedTitle `json:"skipped_titles" gorm:"serializer:json;type:text"`
+	Status           string         `json:"status" gorm:"type:enum('queued','running','completed','failed','cancelled');default:'queued';index"`
+	Step             string         `json:"step" gorm:"type:enum('titles','tasks');default:'titles'"`
+	Error            string         `json:"error" gorm:"type:text"`
+	Attempts         int            `json:"attempts"`
+	CancelRequested  bool           `json:"cancel_requested"`
+	LeaseOwner       string         `json:"-" gorm:"size:100"`
+	LeaseUntil       *time.Time     `json:"-" gorm:"index"`
+	StartedAt        *time.Time     `json:"started_at"`
+	FinishedAt       *time.Time     `json:"finished_at"`
+	Tasks            []AiJobTask    `json:"tasks,omitempty" gorm:"foreignKey:JobId;constraint:OnDelete:CASCADE"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// SkippedTitle judul kandidat yang dibuang karena terlalu mirip artikel yang sudah ada
+type SkippedTitle struct {
+	Title     string  `json:"title"`
+	SimilarTo string  `json:"similar_to"`
+	BlogId    uint    `json:"blog_id,omitempty"`
+	Score     float64 `json:"score"`
+	Method    string  `json:"method"`
+}
+
+// AiJobTask satu judul di dalam job. Step adalah langkah berikutnya yang harus dikerjakan,
+// jadi task yang gagal atau terputus karena restart dilanjutkan dari langkah itu.
+type AiJobTask struct {
+	Id            uint   `json:"id" gorm:"primaryKey"`
+	JobId         uint   `json:"job_id" gorm:"not null;index"`
+	Position      int    `json:"position"`
+	Title         string `json:"title"`
+	Step          string `json:"step" gorm:"type:enum('search','scrape','write','tag','seo','done');default:'search'"`
+	Status        string `json:"status" gorm:"type:enum('queued','running','completed','failed','cancelled');default:'queued';index"`
+	FailedStep    string `json:"failed_step" gorm:"size:20"`
+	Error         string `json:"error" gorm:"type:text"`
+	Attempts      int    `json:"attempts"`
+	SearchResults string `json:"-" gorm:"type:longtext"` // JSON []SearchResult
+	ScrapedRefs   string `json:"-" gorm:"type:longtext"` // JSON []string, hasil scrape
+	// Artikel yang sedang ditulis, disimpan berkala supaya tidak hilang kalau proses mati
+	PartialContent  string           `json:"partial_content,omitempty" gorm:"type:longtext"`
+	QualityFailures []QualityFailure `json:"quality_failures" gorm:"serializer:json;type:text"`
+	BlogId          *uint            `json:"blog_id"`
+	LeaseOwner      string           `json:"-" gorm:"size:100"`
+	LeaseUntil      *time.Time       `json:"-" gorm:"index"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// QualityIssue satu alasan draft AI ditolak quality gate
+type QualityIssue struct {
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// QualityFailure hasil quality gate untuk satu percobaan menulis yang gagal
+type QualityFailure struct {
+	Attempt int            `json:"attempt"`
+	Issues  []QualityIssue `json:"issues"`
+	At      time.Time      `json:"at"`
+}
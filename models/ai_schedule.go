@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// AiSchedule jadwal generate blog AI berulang. Setiap kali jalan, satu keyword diambil
+// bergiliran dari Keywords dan dibuat AiJob seperti POST /api/blogs/generate.
+type AiSchedule struct {
+	Id          uint       `json:"id" gorm:"primaryKey"`
+	Name        string     `json:"name" gorm:"size:100;not null"`
+	Cron        string     `json:"cron" gorm:"size:100;not null"`
+	Keywords    []string   `json:"keywords" gorm:"serializer:json;type:text"`
+	NextKeyword int        `json:"next_keyword"`
+	Total       int        `json:"total"`
+	Tags        []Tag      `json:"tags" gorm:"many2many:ai_schedule_tags"`
+	Enabled     bool       `json:"enabled" gorm:"default:true;index"`
+	NextRunAt   *time.Time `json:"next_run_at" gorm:"index"`
+	LastRunAt   *time.Time `json:"last_run_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AiScheduleRun riwayat satu kali jadwal dijalankan
+type AiScheduleRun struct {
+	Id         uint      `json:"id" gorm:"primaryKey"`
+	ScheduleId uint      `json:"schedule_id" gorm:"not null;index"`
+	Keyword    string    `json:"keyword"`
+	Trigger    string    `json:"trigger" gorm:"type:enum('schedule','manual');default:'schedule'"`
+	JobId      *uint     `json:"job_id"`
+	Job        *AiJob    `json:"job,omitempty" gorm:"foreignKey:JobId;constraint:OnDelete:SET NULL"`
+	Error      string    `json:"error" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SchedulerLock lock leader antar instance: hanya pemegang lease yang menjalankan jadwal
+type SchedulerLock struct {
+	Name       string    `json:"name" gorm:"primaryKey;size:50"`
+	Owner      string    `json:"owner" gorm:"size:100"`
+	LeaseUntil time.Time `json:"lease_until"`
+}
@@ -3,18 +3,57 @@ package models
 import "time"
 
 type Blog struct {
-	Id            uint      `json:"id" gorm:"primaryKey"`
-	Title         string    `json:"title" gorm:"not null"`
-	Slug          string    `json:"slug" gorm:"unique;not null"`
-	Description   string    `json:"description" gorm:"type:text"`
-	Content       string    `json:"content" gorm:"type:longtext"`
-	CoverImage    string    `json:"cover_image"`
-	Author        string    `json:"author" gorm:"type:enum('user','aibys');default:'user'"`
-	Status        string    `json:"status" gorm:"type:enum('pending','published','rejected','archived');default:'published'"`
-	RejectComment string    `json:"reject_comment" gorm:"type:text"`
-	UserId        *uint     `json:"user_id"`
-	User          *User     `json:"user,omitempty" gorm:"foreignKey:UserId;constraint:OnDelete:SET NULL"`
-	Tags          []Tag     `json:"tags" gorm:"many2many:blog_tags;"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	Id                  uint            `json:"id" gorm:"primaryKey"`
+	Title               string          `json:"title" gorm:"not null"`
+	Slug                string          `json:"slug" gorm:"unique;not null"`
+	Description         string          `json:"description" gorm:"type:text"`
+	Content             string          `json:"content" gorm:"type:longtext"`
+	CoverImage          string          `json:"cover_image"`
+	Author              string          `json:"author" gorm:"type:enum('user','aibys');default:'user'"`
+	Status              string          `json:"status" gorm:"type:enum('pending','published','rejected','archived');default:'published'"`
+	RejectComment       string          `json:"reject_comment" gorm:"type:text"`
+	UserId              *uint           `json:"user_id"`
+	User                *User           `json:"user,omitempty" gorm:"foreignKey:UserId;constraint:OnDelete:SET NULL"`
+	Tags                []Tag           `json:"tags" gorm:"many2many:blog_tags;"`
+	PromptTemplateId    *uint           `json:"prompt_template_id"`
+	PromptTemplate      *PromptTemplate `json:"prompt_template,omitempty" gorm:"foreignKey:PromptTemplateId;constraint:OnDelete:SET NULL"`
+	Sources             []BlogSource    `json:"sources,omitempty" gorm:"foreignKey:BlogId;constraint:OnDelete:CASCADE"`
+	ReferencesHtml      string          `json:"references_html,omitempty" gorm:"-"`
+	SourceSimilarity    float64         `json:"source_similarity"`
+	BlogSimilarity      float64         `json:"blog_similarity"`
+	SimilarBlogId       *uint           `json:"similar_blog_id"`
+	SimilarityFlagged   bool            `json:"similarity_flagged" gorm:"default:false;index"`
+	SimilarityCheckedAt *time.Time      `json:"similarity_checked_at"`
+	SimilaritySignature string          `json:"-" gorm:"type:text"`
+	MetaTitle           string          `json:"meta_title" gorm:"size:100"`
+	MetaDescription     string          `json:"meta_description" gorm:"size:300"`
+	FocusKeyword        string          `json:"focus_keyword" gorm:"size:100"`
+	Faq                 []BlogFaq       `json:"faq" gorm:"serializer:json;type:text"`
+	FaqSchema           map[string]any  `json:"faq_schema,omitempty" gorm:"-"`
+	PublishedAt         *time.Time      `json:"published_at" gorm:"index"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
+// PublicBlog blog untuk endpoint publik. Field di bawah menutupi field Blog dengan nama JSON yang sama
+// dan selalu kosong, jadi skor kemiripan dan template prompt hanya terlihat di endpoint admin.
+type PublicBlog struct {
+	Blog
+	PromptTemplateId    *struct{} `json:"prompt_template_id,omitempty"`
+	SourceSimilarity    *struct{} `json:"source_similarity,omitempty"`
+	BlogSimilarity      *struct{} `json:"blog_similarity,omitempty"`
+	SimilarBlogId       *struct{} `json:"similar_blog_id,omitempty"`
+	SimilarityFlagged   *struct{} `json:"similarity_flagged,omitempty"`
+	SimilarityCheckedAt *struct{} `json:"similarity_checked_at,omitempty"`
+}
+
+// Public salinan blog tanpa metadata internal
+func (b Blog) Public() PublicBlog {
+	return PublicBlog{Blog: b}
+}
+
+// BlogFaq satu pertanyaan FAQ, dirender frontend sebagai FAQPage JSON-LD
+type BlogFaq struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
 }
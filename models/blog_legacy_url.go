@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// BlogLegacyUrl URL asli post yang di-import dari WordPress/Medium, dipakai untuk redirect link lama
+type BlogLegacyUrl struct {
+	Id        uint      `json:"id" gorm:"primaryKey"`
+	BlogId    uint      `json:"blog_id" gorm:"not null;index"`
+	Blog      *Blog     `json:"blog,omitempty" gorm:"foreignKey:BlogId;constraint:OnDelete:CASCADE"`
+	Source    string    `json:"source" gorm:"type:enum('wordpress','medium');not null"`
+	Url       string    `json:"url" gorm:"size:500;unique;not null"`
+	Path      string    `json:"path" gorm:"size:500;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// BlogRewrite usulan LLM untuk menulis ulang satu bagian artikel. Konten blog baru berubah saat
+// usulan diterima; BaseHash = hash konten saat usulan dibuat, untuk tahu kalau artikel sudah diedit.
+type BlogRewrite struct {
+	Id             uint      `json:"id" gorm:"primaryKey"`
+	BlogId         uint      `json:"blog_id" gorm:"not null;index"`
+	Blog           *Blog     `json:"blog,omitempty" gorm:"foreignKey:BlogId;constraint:OnDelete:CASCADE"`
+	Anchor         string    `json:"anchor" gorm:"size:255"`
+	ParagraphIndex *int      `json:"paragraph_index"`
+	Instruction    string    `json:"instruction" gorm:"type:text"`
+	Original       string    `json:"original" gorm:"type:longtext"`
+	Proposed       string    `json:"proposed" gorm:"type:longtext"`
+	BaseHash       string    `json:"-" gorm:"size:64"`
+	Status         string    `json:"status" gorm:"type:enum('pending','accepted','discarded');default:'pending';index"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
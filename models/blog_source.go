@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// BlogSource referensi yang dipakai generator AI untuk menulis blog.
+// Position sama dengan nomor sitasi [n] di konten.
+type BlogSource struct {
+	Id          uint      `json:"id" gorm:"primaryKey"`
+	BlogId      uint      `json:"blog_id" gorm:"not null;index"`
+	Position    int       `json:"position"`
+	Url         string    `json:"url" gorm:"size:500;not null"`
+	Title       string    `json:"title"`
+	Snippet     string    `json:"snippet" gorm:"type:text"`
+	ContentHash string    `json:"content_hash" gorm:"size:64"`
+	Similarity  float64   `json:"similarity"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ContentEmbedding vektor embedding satu konten (blog, project, bookmark) untuk pencarian semantik.
+// Vektor disimpan sebagai float32 little-endian; ContentHash dipakai untuk melewati konten yang tidak berubah.
+type ContentEmbedding struct {
+	Id          uint      `json:"id" gorm:"primaryKey"`
+	EntityType  string    `json:"entity_type" gorm:"type:enum('blog','project','bookmark');not null;uniqueIndex:idx_content_embedding"`
+	EntityId    uint      `json:"entity_id" gorm:"not null;uniqueIndex:idx_content_embedding"`
+	Model       string    `json:"model" gorm:"size:100;not null"`
+	Dimension   int       `json:"dimension"`
+	Vector      []byte    `json:"-" gorm:"type:mediumblob"`
+	ContentHash string    `json:"content_hash" gorm:"size:64"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
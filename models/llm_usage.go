@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// LlmUsage satu panggilan LLM: token, durasi dan hasilnya, ditautkan ke job/task/blog yang memicunya
+type LlmUsage struct {
+	Id               uint      `json:"id" gorm:"primaryKey"`
+	Task             string    `json:"task" gorm:"size:30;index"`
+	Provider         string    `json:"provider" gorm:"size:30"`
+	Model            string    `json:"model" gorm:"size:100"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	DurationMs       int64     `json:"duration_ms"`
+	Success          bool      `json:"success"`
+	Error            string    `json:"error" gorm:"type:text"`
+	JobId            *uint     `json:"job_id" gorm:"index"`
+	TaskId           *uint     `json:"task_id" gorm:"index"`
+	BlogId           *uint     `json:"blog_id" gorm:"index"`
+	CreatedAt        time.Time `json:"created_at" gorm:"index"`
+}
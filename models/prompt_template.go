@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PromptTemplate satu versi prompt. Versi tidak pernah diubah; edit = versi baru.
+// Hanya satu versi per key yang aktif dan dipakai generator.
+type PromptTemplate struct {
+	Id        uint      `json:"id" gorm:"primaryKey"`
+	Key       string    `json:"key" gorm:"column:key;size:50;not null;uniqueIndex:idx_prompt_version"`
+	Version   int       `json:"version" gorm:"not null;uniqueIndex:idx_prompt_version"`
+	Body      string    `json:"body" gorm:"type:longtext;not null"`
+	Note      string    `json:"note"`
+	IsActive  bool      `json:"is_active" gorm:"default:false;index"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SlugRedirect menyimpan slug lama sebuah konten supaya link lama tetap bisa diarahkan
+type SlugRedirect struct {
+	Id         uint      `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"type:enum('blog','project','tag');not null;uniqueIndex:idx_slug_redirect"`
+	OldSlug    string    `json:"old_slug" gorm:"size:255;not null;uniqueIndex:idx_slug_redirect"`
+	EntityId   uint      `json:"entity_id" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Subscriber pembaca newsletter — status pending sampai email dikonfirmasi (double opt-in)
+type Subscriber struct {
+	Id               uint       `json:"id" gorm:"primaryKey"`
+	Email            string     `json:"email" gorm:"size:191;unique;not null"`
+	Name             string     `json:"name"`
+	Status           string     `json:"status" gorm:"type:enum('pending','active','unsubscribed','bounced');default:'pending';index"`
+	ConfirmToken     string     `json:"-" gorm:"size:64;index"`
+	UnsubscribeToken string     `json:"-" gorm:"size:64;unique;not null"`
+	BounceCount      int        `json:"bounce_count" gorm:"default:0"`
+	ConfirmedAt      *time.Time `json:"confirmed_at"`
+	UnsubscribedAt   *time.Time `json:"unsubscribed_at"`
+	LastSentAt       *time.Time `json:"last_sent_at"`
+	// Kosong = terima semua tag
+	Tags      []Tag     `json:"tags" gorm:"many2many:subscriber_tags;"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewsletterDigest satu kali pengiriman digest
+type NewsletterDigest struct {
+	Id          uint       `json:"id" gorm:"primaryKey"`
+	BlogIds     string     `json:"blog_ids" gorm:"type:text"` // comma separated
+	Recipients  int        `json:"recipients"`
+	SentCount   int        `json:"sent_count"`
+	FailedCount int        `json:"failed_count"`
+	Trigger     string     `json:"trigger" gorm:"type:enum('schedule','manual');default:'schedule'"`
+	StartedAt   time.Time  `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// NewsletterSend log per email yang dikirim
+type NewsletterSend struct {
+	Id           uint      `json:"id" gorm:"primaryKey"`
+	DigestId     *uint     `json:"digest_id" gorm:"index"`
+	SubscriberId uint      `json:"subscriber_id" gorm:"not null;index"`
+	Email        string    `json:"email" gorm:"size:191;index"`
+	Kind         string    `json:"kind" gorm:"type:enum('confirm','digest');not null"`
+	Status       string    `json:"status" gorm:"type:enum('sent','failed','bounced');not null;index"`
+	Error        string    `json:"error" gorm:"type:text"`
+	CreatedAt    time.Time `json:"created_at" gorm:"index"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
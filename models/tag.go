@@ -3,9 +3,18 @@ package models
 import "time"
 
 type Tag struct {
+	Id        uint       `json:"id" gorm:"primaryKey"`
+	Name      string     `json:"name" gorm:"unique;not null"`
+	Slug      string     `json:"slug" gorm:"unique;not null"`
+	Aliases   []TagAlias `json:"aliases,omitempty" gorm:"foreignKey:TagId"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TagAlias sinonim tag (contoh: go-lang → golang); dipakai saat mencocokkan tag usulan AI
+type TagAlias struct {
 	Id        uint      `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"unique;not null"`
-	Slug      string    `json:"slug" gorm:"unique;not null"`
+	TagId     uint      `json:"tag_id" gorm:"not null;index"`
+	Alias     string    `json:"alias" gorm:"size:100;unique;not null"` // bentuk slug
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
 }
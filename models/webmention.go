@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Webmention mention masuk dari situs lain.
+// Status: pending (menunggu verifikasi) → unmoderated (terverifikasi) → approved/rejected, atau invalid.
+type Webmention struct {
+	Id          uint       `json:"id" gorm:"primaryKey"`
+	BlogId      uint       `json:"blog_id" gorm:"not null;index"`
+	Blog        *Blog      `json:"blog,omitempty" gorm:"foreignKey:BlogId;constraint:OnDelete:CASCADE"`
+	Source      string     `json:"source" gorm:"size:500;not null;uniqueIndex:idx_webmention"`
+	Target      string     `json:"target" gorm:"size:255;not null;uniqueIndex:idx_webmention"`
+	Type        string     `json:"type" gorm:"type:enum('mention','reply','like','repost');default:'mention'"`
+	Title       string     `json:"title"`
+	Excerpt     string     `json:"excerpt" gorm:"type:text"`
+	AuthorName  string     `json:"author_name"`
+	AuthorUrl   string     `json:"author_url" gorm:"size:500"`
+	AuthorPhoto string     `json:"author_photo" gorm:"size:500"`
+	Status      string     `json:"status" gorm:"type:enum('pending','unmoderated','approved','rejected','invalid');default:'pending';index"`
+	Error       string     `json:"error" gorm:"type:text"`
+	PublishedAt *time.Time `json:"published_at"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// WebmentionSend log webmention keluar, satu baris per link di blog
+type WebmentionSend struct {
+	Id         uint      `json:"id" gorm:"primaryKey"`
+	BlogId     uint      `json:"blog_id" gorm:"not null;uniqueIndex:idx_webmention_send"`
+	Target     string    `json:"target" gorm:"size:500;not null;uniqueIndex:idx_webmention_send"`
+	Endpoint   string    `json:"endpoint" gorm:"size:500"`
+	Status     string    `json:"status" gorm:"type:enum('sent','failed','no_endpoint');not null"`
+	StatusCode int       `json:"status_code"`
+	Error      string    `json:"error" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -21,6 +21,9 @@ func SetupRouter() *gin.Engine {
 		ExposeHeaders: []string{"Content-Length"},
 	}))
 
+	// WebFinger untuk discovery ActivityPub — harus di root, bukan di /api
+	router.GET("/.well-known/webfinger", controllers.WebFinger)
+
 	// Base API group
 	api := router.Group("/api")
 
@@ -83,6 +86,10 @@ func SetupRouter() *gin.Engine {
 		auth.POST("/tags", controllers.CreateTag)
 		auth.PUT("/tags/:id", controllers.UpdateTag)
 		auth.DELETE("/tags/:id", controllers.DeleteTag)
+		auth.POST("/tags/suggest", controllers.SuggestTags)
+		auth.POST("/tags/:id/merge", controllers.MergeTags)
+		auth.POST("/tags/:id/aliases", controllers.CreateTagAlias)
+		auth.DELETE("/tags/:id/aliases/:aliasId", controllers.DeleteTagAlias)
 
 		auth.POST("/blogs", controllers.CreateBlog)
 		auth.GET("/blogs/all", controllers.FindAllBlogs)
@@ -91,8 +98,29 @@ func SetupRouter() *gin.Engine {
 
 		// AI Blog generation
 		auth.POST("/blogs/generate", controllers.GenerateAiBlog)
+		auth.GET("/blogs/generate/jobs", controllers.FindAiJobs)
+		auth.GET("/blogs/generate/jobs/:id", controllers.FindAiJobById)
+		auth.POST("/blogs/generate/jobs/:id/cancel", controllers.CancelAiJob)
+		auth.POST("/blogs/generate/jobs/:id/retry", controllers.RetryAiJob)
+		auth.POST("/blogs/generate/jobs/:id/tasks/:taskId/retry", controllers.RetryAiJobTask)
+		auth.POST("/blogs/generate/jobs/:id/tasks/:taskId/draft", controllers.SaveAiTaskDraft)
+		auth.GET("/blogs/generate/schedules", controllers.FindAiSchedules)
+		auth.POST("/blogs/generate/schedules", controllers.CreateAiSchedule)
+		auth.GET("/blogs/generate/schedules/:id", controllers.FindAiScheduleById)
+		auth.PUT("/blogs/generate/schedules/:id", controllers.UpdateAiSchedule)
+		auth.DELETE("/blogs/generate/schedules/:id", controllers.DeleteAiSchedule)
+		auth.GET("/blogs/generate/schedules/:id/runs", controllers.FindAiScheduleRuns)
+		auth.POST("/blogs/generate/schedules/:id/run", controllers.RunAiScheduleNow)
 		auth.PUT("/blogs/:id/publish", controllers.PublishBlog)
 		auth.PUT("/blogs/:id/reject", controllers.RejectBlog)
+		auth.POST("/blogs/:id/similarity", controllers.CheckBlogSimilarity)
+		auth.POST("/blogs/:id/seo", controllers.ProposeBlogSeo)
+
+		// Rewrite bagian artikel — di luar /blogs/:id supaya tidak bentrok dengan GET /blogs/:slug publik
+		auth.GET("/blog-rewrites", controllers.FindBlogRewrites)
+		auth.POST("/blog-rewrites", controllers.ProposeBlogRewrite)
+		auth.POST("/blog-rewrites/:id/accept", controllers.AcceptBlogRewrite)
+		auth.POST("/blog-rewrites/:id/discard", controllers.DiscardBlogRewrite)
 
 		auth.POST("/bookmarks", controllers.CreateBookmark)
 		auth.PUT("/bookmarks/:id", controllers.UpdateBookmark)
@@ -101,6 +129,12 @@ func SetupRouter() *gin.Engine {
 		auth.PUT("/profile", controllers.UpsertProfile)
 		auth.PUT("/settings", controllers.UpsertSettings)
 
+		auth.GET("/prompts", controllers.FindPromptTemplates)
+		auth.POST("/prompts", controllers.CreatePromptTemplate)
+		auth.POST("/prompts/preview", controllers.PreviewPromptTemplate)
+		auth.GET("/prompts/:id", controllers.FindPromptTemplateById)
+		auth.PUT("/prompts/:id/activate", controllers.ActivatePromptTemplate)
+
 		auth.GET("/tools/all", controllers.FindAllTools)
 		auth.GET("/tools/stats", controllers.ToolStats)
 		auth.POST("/tools/sync", controllers.SyncTools)
@@ -112,9 +146,28 @@ func SetupRouter() *gin.Engine {
 		auth.GET("/blogs/stream", controllers.BlogStream)
 
 		auth.GET("/blogs/stats", controllers.BlogStats)
+		auth.GET("/ai/stats", controllers.AiStats)
 		auth.PUT("/blogs/:id/archive", controllers.ArchiveBlog)
 		auth.POST("/blogs/bulk", controllers.BulkActionBlog)
 
+		// Markdown bundle
+		auth.GET("/blogs/export", controllers.ExportBlogs)
+		auth.POST("/blogs/import", controllers.ImportBlogs)
+		auth.POST("/blogs/import/external", controllers.ImportExternalBlogs)
+
+		// Newsletter
+		auth.GET("/newsletter/subscribers", controllers.FindSubscribers)
+		auth.DELETE("/newsletter/subscribers/:id", controllers.DeleteSubscriber)
+		auth.GET("/newsletter/stats", controllers.NewsletterStats)
+		auth.POST("/newsletter/digest/send", controllers.SendDigestNow)
+
+		// Webmention moderation
+		auth.GET("/webmentions", controllers.FindWebmentions)
+		auth.GET("/webmentions/sent", controllers.FindSentWebmentions)
+		auth.PUT("/webmentions/:id/approve", controllers.ApproveWebmention)
+		auth.PUT("/webmentions/:id/reject", controllers.RejectWebmention)
+		auth.DELETE("/webmentions/:id", controllers.DeleteWebmention)
+
 	}
 
 	// Public routes
@@ -149,7 +202,11 @@ func SetupRouter() *gin.Engine {
 
 		public.GET("/blogs", controllers.FindBlogs)
 		public.GET("/blogs/:slug", controllers.FindBlogBySlug)
+		public.GET("/blogs/:slug/webmentions", controllers.FindBlogWebmentions)
+		public.GET("/redirects", controllers.ResolveLegacyUrl)
 
+		public.GET("/search/semantic", middlewares.SearchRateLimit(), controllers.SemanticSearch)
+		public.GET("/ask", middlewares.AskRateLimit(), controllers.AskAibys)
 		public.GET("/bookmarks", controllers.FindBookmarks)
 		public.GET("/bookmarks/:id", controllers.FindBookmarkById)
 
@@ -162,6 +219,31 @@ func SetupRouter() *gin.Engine {
 		public.GET("/tools/:slug", middlewares.ToolRateLimit(), controllers.FindToolBySlug)
 		public.GET("/tools/:slug/run", middlewares.ToolRateLimit(), controllers.RunTool)
 		public.POST("/tools/:slug/run", middlewares.ToolRateLimit(), controllers.RunTool)
+
+		// Open Graph share images
+		public.GET("/og/blogs/:file", controllers.BlogOgImage)
+		public.GET("/og/projects/:file", controllers.ProjectOgImage)
+		public.GET("/og/profile.png", controllers.ProfileOgImage)
+		public.GET("/og/profile.jpg", controllers.ProfileOgImage)
+
+		// Newsletter — publik
+		public.POST("/newsletter/subscribe", middlewares.NewsletterRateLimit(), controllers.SubscribeNewsletter)
+		public.GET("/newsletter/confirm", controllers.ConfirmNewsletter)
+		public.GET("/newsletter/unsubscribe", controllers.UnsubscribeNewsletterPage)
+		public.POST("/newsletter/unsubscribe", controllers.UnsubscribeNewsletter)
+		public.GET("/newsletter/preferences", controllers.GetNewsletterPreferences)
+		public.PUT("/newsletter/preferences", controllers.UpdateNewsletterPreferences)
+		public.POST("/newsletter/bounces", controllers.NewsletterBounce)
+
+		// ActivityPub federation
+		public.GET("/ap/actor", controllers.ApActor)
+		public.GET("/ap/outbox", controllers.ApOutbox)
+		public.GET("/ap/followers", controllers.ApFollowers)
+		public.GET("/ap/articles/:id", controllers.ApArticle)
+		public.POST("/ap/inbox", controllers.ApInbox)
+
+		// Webmention
+		public.POST("/webmention", middlewares.WebmentionRateLimit(), controllers.ReceiveWebmention)
 	}
 
 	return router
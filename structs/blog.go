@@ -13,6 +13,12 @@ type BlogUpdateRequest struct {
 	Content     string `form:"content"`
 	TagIds      []uint `form:"tag_ids"`
 	UpdateTags  bool   `form:"update_tags"`
+	// Field SEO hanya diubah kalau update_seo = true; faq berupa string JSON [{question, answer}]
+	MetaTitle       string `form:"meta_title"`
+	MetaDescription string `form:"meta_description"`
+	FocusKeyword    string `form:"focus_keyword"`
+	Faq             string `form:"faq"`
+	UpdateSeo       bool   `form:"update_seo"`
 }
 
 type AiBlogGenerateRequest struct {
@@ -31,3 +37,50 @@ type BlogBulkRequest struct {
 	// Hanya dipakai kalau action = reject
 	Comment string `json:"comment"`
 }
+
+// Import bundle Markdown (multipart, field file = zip)
+type BlogImportRequest struct {
+	DryRun    bool `form:"dry_run"`
+	Overwrite bool `form:"overwrite"`
+}
+
+type BlogImportConflict struct {
+	File   string `json:"file"`
+	Slug   string `json:"slug"`
+	Reason string `json:"reason"`
+}
+
+// Laporan hasil import — saat dry run isinya rencana, bukan hasil
+type BlogImportReport struct {
+	DryRun    bool                 `json:"dry_run"`
+	Creates   []string             `json:"creates"`
+	Updates   []string             `json:"updates"`
+	Conflicts []BlogImportConflict `json:"conflicts"`
+	Uploads   int                  `json:"uploads"`
+}
+
+// Import dari WordPress (file WXR .xml) atau Medium (zip export)
+type BlogExternalImportRequest struct {
+	Source    string `form:"source" binding:"required,oneof=wordpress medium"`
+	DryRun    bool   `form:"dry_run"`
+	Overwrite bool   `form:"overwrite"`
+}
+
+// AiScheduleRequest jadwal generate AI berulang; keyword dipakai bergiliran setiap kali jalan
+type AiScheduleRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Cron     string   `json:"cron" binding:"required"`
+	Keywords []string `json:"keywords" binding:"required,min=1,dive,required"`
+	Total    int      `json:"total" binding:"required,min=1,max=10"`
+	TagIds   []uint   `json:"tag_ids"`
+	Enabled  *bool    `json:"enabled"`
+}
+
+// Rewrite satu bagian artikel: pilih heading lewat anchor (id / slug teks heading) atau
+// paragraph_index (paragraf ke-n, mulai 0). instruction = expand, simplify, add_code_example, atau teks bebas
+type BlogSectionRewriteRequest struct {
+	BlogId         uint   `json:"blog_id" binding:"required"`
+	Anchor         string `json:"anchor" binding:"max=255"`
+	ParagraphIndex *int   `json:"paragraph_index" binding:"omitempty,min=0"`
+	Instruction    string `json:"instruction" binding:"required,max=1000"`
+}
@@ -1,5 +1,6 @@
 package structs
 
+// BookmarkRequest description boleh kosong, diisi di background dari metadata halaman
 type BookmarkRequest struct {
 	Url         string   `json:"url" binding:"required"`
 	Title       string   `json:"title" binding:"required"`
@@ -0,0 +1,22 @@
+package structs
+
+// Struct ini digunakan saat pengunjung subscribe newsletter (publik)
+type NewsletterSubscribeRequest struct {
+	Email  string `json:"email" binding:"required,email,max=191"`
+	Name   string `json:"name" binding:"max=100"`
+	TagIds []uint `json:"tag_ids"`
+}
+
+// Struct ini digunakan saat subscriber mengubah preferensi tag
+type NewsletterPreferenceRequest struct {
+	Token  string `json:"token" binding:"required"`
+	TagIds []uint `json:"tag_ids"`
+}
+
+// Struct ini digunakan oleh webhook bounce dari mail provider
+type NewsletterBounceRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	Reason string `json:"reason"`
+	// hard = alamat tidak valid, langsung dinonaktifkan
+	Hard bool `json:"hard"`
+}
@@ -0,0 +1,38 @@
+package structs
+
+// Struct ini digunakan untuk membuat versi baru prompt template
+type PromptTemplateCreateRequest struct {
+	Key      string `json:"key" binding:"required,oneof=titles content tags regenerate seo ask section"`
+	Body     string `json:"body" binding:"required"`
+	Note     string `json:"note" binding:"max=255"`
+	Activate bool   `json:"activate"`
+}
+
+// Struct ini digunakan untuk preview prompt: pakai body langsung, template_id, atau versi aktif dari key
+type PromptPreviewRequest struct {
+	Key        string           `json:"key" binding:"omitempty,oneof=titles content tags regenerate seo ask section"`
+	TemplateId uint             `json:"template_id"`
+	Body       string           `json:"body"`
+	Variables  *PromptVariables `json:"variables"`
+}
+
+// Variabel template, field kosong diisi contoh data
+type PromptVariables struct {
+	Title       string                 `json:"title"`
+	Keyword     string                 `json:"keyword"`
+	Total       int                    `json:"total"`
+	References  []string               `json:"references"`
+	Sources     []PromptSourceVariable `json:"sources"`
+	Date        string                 `json:"date"`
+	Comment     string                 `json:"comment"`
+	Tone        string                 `json:"tone"`
+	Description string                 `json:"description"`
+	Content     string                 `json:"content"`
+}
+
+// Sumber bernomor untuk preview template konten
+type PromptSourceVariable struct {
+	Title   string `json:"title"`
+	Url     string `json:"url"`
+	Content string `json:"content"`
+}
@@ -0,0 +1,17 @@
+package structs
+
+// Struct ini digunakan untuk response 301 saat konten diakses lewat slug lama
+type SlugRedirectResponse struct {
+	EntityType string `json:"entity_type"`
+	OldSlug    string `json:"old_slug"`
+	Slug       string `json:"slug"`
+	Location   string `json:"location"`
+}
+
+// Struct ini digunakan untuk response redirect dari URL lama (WordPress/Medium)
+type LegacyRedirectResponse struct {
+	Source   string `json:"source"`
+	OldUrl   string `json:"old_url"`
+	Slug     string `json:"slug"`
+	Location string `json:"location"`
+}
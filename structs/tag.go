@@ -9,3 +9,20 @@ type TagCreateRequest struct {
 type TagUpdateRequest struct {
 	Name string `json:"name" binding:"required"`
 }
+
+// Tambah alias (sinonim) untuk tag
+type TagAliasRequest struct {
+	Alias string `json:"alias" binding:"required,max=100"`
+}
+
+// Gabungkan tag sumber ke tag tujuan (:id); tag sumber dihapus dan jadi alias
+type TagMergeRequest struct {
+	SourceIds []uint `json:"source_ids" binding:"required,min=1"`
+}
+
+// Usulan tag untuk tulisan manual, boleh sebelum blog disimpan
+type TagSuggestRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	Content     string `json:"content"`
+}
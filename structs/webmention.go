@@ -0,0 +1,7 @@
+package structs
+
+// Struct ini digunakan untuk menerima webmention (form-urlencoded sesuai spesifikasi W3C)
+type WebmentionRequest struct {
+	Source string `form:"source" binding:"required,url,max=500"`
+	Target string `form:"target" binding:"required,url,max=255"`
+}